@@ -15,18 +15,26 @@
 package main
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
 	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"image/color"
+	"io"
+	"io/fs"
+	"math"
 	"math/big"
 	"net"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
 	"strconv"
@@ -53,6 +61,7 @@ import (
 	"github.com/creachadair/jrpc2/channel"
 	"github.com/creachadair/jrpc2/handler"
 	"github.com/gorilla/websocket"
+	bolt "go.etcd.io/bbolt"
 	"mvdan.cc/xurls/v2"
 
 	"github.com/civilware/Gnomon/storage"
@@ -200,10 +209,78 @@ type Theme struct {
 
 type Gnomon struct {
 	Active   int
+	Endpoint bool
 	Index    *indexer.Indexer
 	BBolt    *storage.BboltStore
 	Graviton *storage.GravitonStore
 	Path     string
+	Progress GnomonProgress
+	Events   GnomonEvents
+}
+
+type GnomonSnapshot struct {
+	Version int                  `json:"version"`
+	Network string               `json:"network"`
+	Backend string               `json:"backend"`
+	Height  int64                `json:"height"`
+	Created time.Time            `json:"created"`
+	Files   []GnomonSnapshotFile `json:"files"`
+}
+
+type GnomonSnapshotFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type GnomonEvents struct {
+	sync.Mutex
+	Running       bool
+	Height        int64
+	Invokes       map[string]int64
+	Variables     map[string]string
+	SCIDs         int
+	Subscriptions GnomonSubscriptions
+}
+
+type GnomonSubscriptions struct {
+	sync.Mutex
+	Apps map[string]map[rpc.EventType]bool
+}
+
+type GnomonProgress struct {
+	sync.Mutex
+	Samples  []GnomonSample
+	Network  string
+	Last     GetSyncStatus_Result
+	Counts   GnomonCounts
+	Counting bool
+}
+
+type GnomonCounts struct {
+	Path   string
+	Time   time.Time
+	SCIDs  int
+	DBSize int64
+}
+
+type GnomonSample struct {
+	Time   time.Time
+	Height int64
+}
+
+type GnomonProfile struct {
+	Preset        string   `json:"preset"`
+	SearchFilters []string `json:"search_filters"`
+	Exclusions    []string `json:"exclusions"`
+	Inclusions    []string `json:"inclusions"`
+	FastSync      bool     `json:"fastsync"`
+	ForceFastSync bool     `json:"force_fastsync"`
+	FastSyncDiff  int64    `json:"fastsync_diff"`
+	SkipFSRecheck bool     `json:"skip_fs_recheck"`
+	NoCode        bool     `json:"no_code"`
+	Parallel      int      `json:"parallel"`
+	Backend       string   `json:"backend"`
 }
 
 type ProofData struct {
@@ -248,11 +325,244 @@ type MessageBox struct {
 }
 
 type Messages struct {
-	Contact string
-	Address string
-	Data    []string
-	Height  uint64
-	Message string
+	Contact        string
+	Address        string
+	Data           []string
+	Height         uint64
+	Message        string
+	HideUnverified bool
+	ShowQuarantine bool
+	Index          MessageIndex
+}
+
+type MessageIndex struct {
+	sync.Mutex
+	updating sync.Mutex
+	Height   uint64
+	Contacts map[string]*MessageContact
+}
+
+type MessageContact struct {
+	Address   string           `json:"address"`
+	Username  string           `json:"username"`
+	Height    uint64           `json:"height"`
+	Verified  bool             `json:"verified"`
+	Outgoing  bool             `json:"outgoing,omitempty"`
+	MaxAmount uint64           `json:"max_amount,omitempty"`
+	Settings  *MessageSettings `json:"settings,omitempty"`
+}
+
+type MessageArchive struct {
+	Version       int                          `json:"version"`
+	Address       string                       `json:"address"`
+	Created       time.Time                    `json:"created"`
+	Conversations []MessageArchiveConversation `json:"conversations"`
+}
+
+type MessageArchiveConversation struct {
+	Contact  MessageContact   `json:"contact"`
+	Messages []IndexedMessage `json:"messages"`
+}
+
+type MessageFilters struct {
+	Blocked           []string `json:"blocked"`
+	MinAmount         uint64   `json:"min_amount"`
+	QuarantineUnknown bool     `json:"quarantine_unknown"`
+}
+
+type MessageSettings struct {
+	Amount   uint64 `json:"amount"`
+	Ringsize uint64 `json:"ringsize"`
+	Expiry   uint64 `json:"expiry"`
+	Port     uint64 `json:"port"`
+}
+
+type IndexedMessage struct {
+	TXID     string    `json:"txid"`
+	Height   uint64    `json:"height"`
+	Time     time.Time `json:"time"`
+	Incoming bool      `json:"incoming"`
+	Sender   string    `json:"sender"`
+	Comment  string    `json:"comment"`
+	Amount   uint64    `json:"amount"`
+	Verified bool      `json:"verified"`
+	ID       uint64    `json:"id,omitempty"`
+	Part     uint64    `json:"part,omitempty"`
+	Parts    uint64    `json:"parts,omitempty"`
+	Received uint64    `json:"-"`
+}
+
+type NameService struct {
+	sync.Mutex
+	Marker    int64
+	Names     map[string]NameServiceEntry
+	Addresses map[string]NameServiceEntry
+	History   map[string]string
+	Missing   map[string]int64
+	Pending   map[string]bool
+}
+
+type NameServiceEntry struct {
+	Address    string
+	Names      []string
+	Topoheight int64
+}
+
+type UsernameQueue struct {
+	sync.Mutex
+	Active   bool
+	Entries  []UsernameRegistration
+	OnUpdate func()
+}
+
+type UsernameRegistration struct {
+	Name   string
+	TXID   string
+	Status string
+	Error  string
+}
+
+type AssetTracker struct {
+	sync.Mutex
+	Loaded   bool
+	Updating bool
+	Height   uint64
+	Balances map[string]uint64
+	Entries  map[string]int
+	Hidden   map[string]bool
+	Trusted  map[string]bool
+	Scan     AssetScan
+	OnUpdate func()
+}
+
+type AssetFilters struct {
+	Hidden  []string `json:"hidden"`
+	Trusted []string `json:"trusted"`
+}
+
+type AssetScan struct {
+	Active  bool
+	Checked int
+	Total   int
+	Found   int
+	Last    string
+	cancel  chan struct{}
+}
+
+type AssetWatchlist struct {
+	Height   uint64            `json:"height"`
+	Balances map[string]uint64 `json:"balances"`
+}
+
+type AssetMetadata struct {
+	SCID        string `json:"scid"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Owner       string `json:"owner"`
+	Standard    string `json:"standard"`
+	Decimals    uint64 `json:"decimals"`
+	CodeHash    string `json:"codeHash"`
+	Updated     int64  `json:"updated"`
+}
+
+type AssetMetadataCache struct {
+	sync.Mutex
+	Entries  map[string]AssetMetadata
+	Pending  map[string]bool
+	OnUpdate func()
+	workers  chan struct{}
+}
+
+type NFAListing struct {
+	SCID         string
+	Owner        string
+	Creator      string
+	Collection   string
+	FileType     string
+	File         string
+	Cover        string
+	Signature    string
+	FileCheckC   string
+	FileCheckS   string
+	Royalty      uint64
+	ArtificerFee uint64
+	ListType     string
+	StartPrice   uint64
+	CurrentBid   uint64
+	Bidder       string
+	BidCount     uint64
+	BuyCount     uint64
+	Start        uint64
+	End          uint64
+	Charity      string
+	CharityPerc  uint64
+	Escrow       uint64
+	Contract     dvm.SmartContract
+}
+
+type Airdrop struct {
+	SCID       string             `json:"scid"`
+	Ringsize   uint64             `json:"ringsize"`
+	BatchSize  int                `json:"batchSize"`
+	Created    string             `json:"created"`
+	Recipients []AirdropRecipient `json:"recipients"`
+}
+
+type AirdropRecipient struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
+	Status  string `json:"status"`
+	TXID    string `json:"txid,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type AirdropRunner struct {
+	sync.Mutex
+	Active   bool
+	Job      Airdrop
+	OnUpdate func()
+	stop     chan struct{}
+}
+
+type DVMSandbox struct {
+	SCID      crypto.Hash
+	Contract  dvm.SmartContract
+	Installed bool
+	Height    uint64
+	Storage   map[string][]byte
+	Balances  map[crypto.Hash]uint64
+}
+
+type DVMResult struct {
+	Entrypoint string
+	Return     uint64
+	GasCompute uint64
+	GasStorage uint64
+	Transfers  []dvm.TransferExternal
+	Err        error
+}
+
+type ContractFunction struct {
+	Name       string
+	Params     []ContractParam
+	Signer     bool
+	DEROValue  bool
+	AssetValue bool
+}
+
+type ContractParam struct {
+	Name string
+	Type string
+}
+
+type ContractPreset struct {
+	Name       string            `json:"name"`
+	Function   string            `json:"function"`
+	Ringsize   uint64            `json:"ringsize"`
+	DEROValue  string            `json:"deroValue,omitempty"`
+	AssetValue string            `json:"assetValue,omitempty"`
+	Args       map[string]string `json:"args"`
 }
 
 type InstallContract struct {
@@ -322,7 +632,9 @@ func StartPulse() {
 							walletapi.Connected = false
 							status.Connection.FillColor = colors.Red
 							status.Sync.FillColor = colors.Red
-							status.Gnomon.FillColor = colors.Red
+							if !gnomon.Endpoint {
+								status.Gnomon.FillColor = colors.Red
+							}
 							status.EPOCH.FillColor = colors.Red
 							session.Offline = true
 
@@ -347,6 +659,9 @@ func StartPulse() {
 					} else {
 						if session.WalletHeight != engram.Disk.Get_Height() {
 							sentNotifications = false
+							go updateMessageIndex()
+							go nameService.update()
+							go assets.update()
 						}
 
 						session.Balance, _ = engram.Disk.Get_Balance()
@@ -368,24 +683,17 @@ func StartPulse() {
 								status.Sync.FillColor = color.Transparent
 							}
 
+							status.Gnomon.FillColor = gnomonStatusColor()
 							if gnomon.Index != nil {
-								if gnomon.Index.Status == "indexed" {
-									status.Gnomon.FillColor = colors.Green
-								} else {
-									if uint64(gnomon.Index.LastIndexedHeight) < session.WalletHeight-15 {
-										status.Gnomon.FillColor = colors.Red
-									} else {
-										status.Gnomon.FillColor = color.Transparent
-									}
-								}
-							} else {
-								status.Gnomon.FillColor = colors.Gray
+								gnomon.Progress.sample(gnomon.Index.LastIndexedHeight)
+								gnomon.Progress.count(gnomon.Path, true)
+								go gnomon.Events.process()
+							}
 
-								if gnomon.Index == nil && engram.Disk != nil {
-									enableGnomon, _ := getGnomon()
-									if enableGnomon == "1" {
-										startGnomon()
-									}
+							if gnomon.Index == nil && engram.Disk != nil {
+								enableGnomon, _ := getGnomon()
+								if enableGnomon == "1" {
+									startGnomon()
 								}
 							}
 
@@ -406,26 +714,48 @@ func StartPulse() {
 							status.Connection.FillColor = colors.Gray
 							status.Sync.FillColor = colors.Gray
 							status.Cyberdeck.FillColor = colors.Gray
-							status.Gnomon.FillColor = colors.Gray
 							status.EPOCH.FillColor = colors.Gray
+
+							// A dedicated Gnomon endpoint keeps indexing while the wallet daemon is offline
+							if gnomon.Endpoint {
+								status.Gnomon.FillColor = gnomonStatusColor()
+								if gnomon.Index != nil {
+									gnomon.Progress.sample(gnomon.Index.LastIndexedHeight)
+									gnomon.Progress.count(gnomon.Path, true)
+								}
+							} else {
+								status.Gnomon.FillColor = colors.Gray
+							}
 							logger.Printf("[Network] Offline › Last Height: " + strconv.FormatUint(session.WalletHeight, 10) + " / " + strconv.FormatUint(session.DaemonHeight, 10) + "\n")
 						}
 
 						// Check for updates and send appropriate notifications
 						var zeroscid crypto.Hash
 
-						// Query incoming messages
-						entries := engram.Disk.Show_Transfers(zeroscid, false, true, false, session.WalletHeight-1, session.WalletHeight-1, "", "", uint64(1337), 0)
-
-						for e := range entries {
-							if entries[e].Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) && !sentNotifications {
-								sender := entries[e].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string)
+						// Query incoming messages on every port used by the message settings. Checking a sender needs the
+						// daemon, so each height is only checked once even when no notification was sent
+						if !sentNotifications {
+							messages.Index.Lock()
+							messages.Index.load()
+							ports := messagePorts()
+							messages.Index.Unlock()
+
+							var entries []rpc.Entry
+							for _, port := range ports {
+								entries = append(entries, engram.Disk.Show_Transfers(zeroscid, false, true, false, session.WalletHeight-1, session.WalletHeight-1, "", "", port, 0)...)
+							}
 
-								notification := fyne.NewNotification(sender, "New message was received (Height: "+fmt.Sprintf("%d", entries[e].Height)+")")
-								fyne.CurrentApp().SendNotification(notification)
+							for e := range entries {
+								if entries[e].Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) && allowMessageNotification(entries[e]) {
+									sender := entries[e].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string)
 
-								sentNotifications = true
+									notification := fyne.NewNotification(sender, "New message was received (Height: "+fmt.Sprintf("%d", entries[e].Height)+")")
+									fyne.CurrentApp().SendNotification(notification)
+									break
+								}
 							}
+
+							sentNotifications = true
 						}
 
 						fyne.Do(func() {
@@ -524,6 +854,29 @@ func getDaemon() (r string) {
 	return
 }
 
+// Check if s is a valid daemon endpoint, with or without a http, https, ws or wss scheme
+func validEndpoint(s string) bool {
+	regex := `^(?:[a-zA-Z0-9]{1,62}(?:[-\.][a-zA-Z0-9]{1,62})+)(:\d+)?$`
+	test := regexp.MustCompile(regex)
+
+	// Trim off http, https, wss, ws to validate regex on 'actual' uri for connection. If none match, s is just s as normal
+	var ssplit string
+	if strings.HasPrefix(s, "https") {
+		ssplit = strings.TrimPrefix(strings.ToLower(s), "https://")
+	} else if strings.HasPrefix(s, "http") {
+		ssplit = strings.TrimPrefix(strings.ToLower(s), "http://")
+	} else if strings.HasPrefix(s, "wss") {
+		ssplit = strings.TrimPrefix(strings.ToLower(s), "wss://")
+	} else if strings.HasPrefix(s, "ws") {
+		ssplit = strings.TrimPrefix(strings.ToLower(s), "ws://")
+	} else {
+		// s is s
+		ssplit = s
+	}
+
+	return test.MatchString(ssplit)
+}
+
 // Set the daemon endpoint setting to the local Graviton tree
 func setDaemon(s string) (err error) {
 	StoreValue("settings", []byte("endpoint"), []byte(s))
@@ -636,7 +989,7 @@ func getGnomon() (r string, err error) {
 	if err != nil {
 		gnomon.Active = 1
 		if gnomon.Index != nil {
-			gnomon.Index.Endpoint = getDaemon()
+			gnomon.Index.Endpoint = getGnomonEndpoint()
 		}
 		StoreValue("settings", []byte("gnomon"), []byte("1"))
 	}
@@ -644,7 +997,7 @@ func getGnomon() (r string, err error) {
 	if string(v) == "1" {
 		gnomon.Active = 1
 		if gnomon.Index != nil {
-			gnomon.Index.Endpoint = getDaemon()
+			gnomon.Index.Endpoint = getGnomonEndpoint()
 		}
 	} else {
 		gnomon.Active = 0
@@ -660,7 +1013,7 @@ func setGnomon(s string) (err error) {
 		err = StoreValue("settings", []byte("gnomon"), []byte("1"))
 		gnomon.Active = 1
 		if gnomon.Index != nil {
-			gnomon.Index.Endpoint = getDaemon()
+			gnomon.Index.Endpoint = getGnomonEndpoint()
 		}
 	} else {
 		err = StoreValue("settings", []byte("gnomon"), []byte("0"))
@@ -669,6 +1022,142 @@ func setGnomon(s string) (err error) {
 	return
 }
 
+// Get the daemon endpoint Gnomon indexes from, the wallet daemon is used if no dedicated endpoint is set for the network
+func getGnomonEndpoint() (r string) {
+	result, err := GetValue("settings", []byte("gnomon.endpoint."+session.Network))
+	if err != nil || len(result) == 0 {
+		return session.Daemon
+	}
+
+	r = string(result)
+	return
+}
+
+// Set a dedicated Gnomon daemon endpoint for the network to the local Graviton tree, an empty endpoint uses the wallet daemon
+func setGnomonEndpoint(network, s string) (err error) {
+	err = StoreValue("settings", []byte("gnomon.endpoint."+network), []byte(strings.TrimSpace(s)))
+	return
+}
+
+// Check if Gnomon indexes from a dedicated endpoint rather than the wallet daemon
+func hasGnomonEndpoint() bool {
+	result, err := GetValue("settings", []byte("gnomon.endpoint."+session.Network))
+	return err == nil && len(result) > 0 && string(result) != session.Daemon
+}
+
+// Get the Gnomon status indicator color, a dedicated endpoint is checked by its own connection and chain height
+func gnomonStatusColor() color.Color {
+	if gnomon.Index == nil {
+		return colors.Gray
+	}
+
+	if gnomon.Endpoint && !indexer.Connected {
+		return colors.Red
+	}
+
+	if gnomon.Index.Status == "indexed" {
+		return colors.Green
+	}
+
+	height := int64(session.WalletHeight)
+	if gnomon.Endpoint {
+		height = gnomon.Index.ChainHeight
+	}
+
+	if gnomon.Index.LastIndexedHeight < height-15 {
+		return colors.Red
+	}
+
+	return color.Transparent
+}
+
+// Get the Gnomon indexing profile of a preset, Light only indexes what Engram needs while Full indexes the code of every smart contract
+func gnomonPreset(preset string) (p GnomonProfile) {
+	switch preset {
+	case GNOMON_PROFILE_FULL:
+		p = GnomonProfile{
+			Preset:   GNOMON_PROFILE_FULL,
+			FastSync: false,
+			NoCode:   false,
+			Parallel: 4,
+			Backend:  "gravdb",
+		}
+	default:
+		p = GnomonProfile{
+			Preset:        GNOMON_PROFILE_LIGHT,
+			SearchFilters: []string{"Function Initialize"},
+			FastSync:      true,
+			ForceFastSync: true,
+			FastSyncDiff:  20,
+			SkipFSRecheck: true,
+			NoCode:        true,
+			Parallel:      1,
+			Backend:       "gravdb",
+		}
+	}
+
+	return
+}
+
+// Check a Gnomon indexing profile can be used to start the indexer
+func (p GnomonProfile) validate() (err error) {
+	if p.Backend != "gravdb" && p.Backend != "boltdb" {
+		return fmt.Errorf("invalid storage backend %q", p.Backend)
+	}
+
+	if p.Parallel < 1 || p.Parallel > DEFAULT_GNOMON_MAX_PARALLEL {
+		return fmt.Errorf("parallel blocks must be between 1 and %d", DEFAULT_GNOMON_MAX_PARALLEL)
+	}
+
+	if p.FastSyncDiff < 0 {
+		return errors.New("fastsync difference can't be negative")
+	}
+
+	for _, list := range [][]string{p.Exclusions, p.Inclusions} {
+		for _, scid := range list {
+			if len(scid) != 64 {
+				return fmt.Errorf("invalid SCID %q", scid)
+			}
+
+			if _, err = hex.DecodeString(scid); err != nil {
+				return fmt.Errorf("invalid SCID %q", scid)
+			}
+		}
+	}
+
+	return
+}
+
+// Get the Gnomon indexing profile of a network from the local Graviton tree, the Light preset is used if none is stored
+func getGnomonProfile(network string) (p GnomonProfile) {
+	stored, err := GetValue("settings", []byte("gnomon.profile."+network))
+	if err != nil {
+		return gnomonPreset(GNOMON_PROFILE_LIGHT)
+	}
+
+	if err = json.Unmarshal(stored, &p); err != nil || p.validate() != nil {
+		logger.Errorf("[Gnomon] Invalid %s indexing profile, using defaults\n", network)
+		return gnomonPreset(GNOMON_PROFILE_LIGHT)
+	}
+
+	return
+}
+
+// Set the Gnomon indexing profile of a network to the local Graviton tree, it is applied the next time Gnomon is started
+func setGnomonProfile(network string, p GnomonProfile) (err error) {
+	if err = p.validate(); err != nil {
+		return
+	}
+
+	stored, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+
+	err = StoreValue("settings", []byte("gnomon.profile."+network), stored)
+	return
+}
+
 /*
 func getAuthMode() (result string, err error) {
 	r, err := GetValue("settings", []byte("auth_mode"))
@@ -717,6 +1206,9 @@ func closeWallet() {
 	if engram.Disk != nil {
 		logger.Printf("[Engram] Shutting down wallet services...\n")
 		stopEPOCH()
+		assets.reset()
+		assetMetadata.reset()
+		airdrop.reset()
 		engram.Disk.SetOfflineMode()
 		engram.Disk.Save_Wallet()
 
@@ -728,6 +1220,9 @@ func closeWallet() {
 		session.LastBalance = 0
 		engram.Disk = nil
 		tx = Transfers{}
+		messages.Index.reset()
+		nameService.reset()
+		usernameQueue.reset()
 
 		if gnomon.Index != nil {
 			logger.Printf("[Gnomon] Shutting down indexers...\n")
@@ -1379,55 +1874,83 @@ func setRingSize(wallet *walletapi.Wallet_Disk, s int) bool {
 	return true
 }
 
+// Returned when the name service has no registration for a username
+var errUsernameNotFound = errors.New("username does not exist")
+
 // Check if a username exists, return the registered address if so
 func checkUsername(s string, h int64) (address string, err error) {
+	if cached, ok := nameService.lookup(s, h); ok {
+		if cached == "" {
+			err = errUsernameNotFound
+			return
+		}
+
+		address = cached
+		return
+	}
+
 	if session.Offline {
 		return
 	}
 
+	// Use the Gnomon index of the name service before asking the daemon
 	if h < 0 {
-		address, err = engram.Disk.NameToAddress(s)
-	} else {
-		var params rpc.NameToAddress_Params
-		var response *jrpc2.Response
-		var result rpc.NameToAddress_Result
-
-		rpc_client.WS, _, err = websocket.DefaultDialer.Dial("ws://"+session.Daemon+"/ws", nil)
-		if err != nil {
+		if indexed := gnomonNameToAddress(s); indexed != "" {
+			address = indexed
+			nameService.store(s, h, address)
 			return
 		}
+	}
 
-		input_output := rwc.New(rpc_client.WS)
-		rpc_client.RPC = jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
+	address, err = resolveUsername(s, h)
+	if err == nil && address != "" {
+		nameService.store(s, h, address)
+	} else if errors.Is(err, errUsernameNotFound) {
+		nameService.store(s, h, "")
+	}
 
-		if rpc_client.RPC != nil {
-			params.Name = s
-			params.TopoHeight = h
+	return
+}
 
-			address = ""
-			response, err = rpc_client.RPC.Call(context.Background(), "DERO.NameToAddress", params)
+// Resolve a username to its address with the daemon at height h, or the latest height when h < 0
+func resolveUsername(s string, h int64) (address string, err error) {
+	var params rpc.NameToAddress_Params
+	var response *jrpc2.Response
+	var result rpc.NameToAddress_Result
 
-			rpc_client.WS.Close()
-			rpc_client.RPC.Close()
+	// Usernames are resolved from the pulse loop, the message index and the username queue at once,
+	// so each call uses its own connection instead of rpc_client
+	ws, _, err := websocket.DefaultDialer.Dial("ws://"+session.Daemon+"/ws", nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
 
-			if err != nil {
-				return
-			}
+	input_output := rwc.New(ws)
+	client := jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
+	defer client.Close()
 
-			err = response.UnmarshalResult(&result)
-			if err != nil {
-				return
-			}
+	params.Name = s
+	params.TopoHeight = h
 
-			if result.Status != "OK" {
-				err = errors.New("username does not exist")
-				return
-			}
+	response, err = client.Call(context.Background(), "DERO.NameToAddress", params)
+	if err != nil {
+		return
+	}
 
-			address = result.Address
-		}
+	err = response.UnmarshalResult(&result)
+	if err != nil {
+		return
+	}
+
+	// The daemon answers without a status when the name is not registered
+	if result.Status != "OK" {
+		err = errUsernameNotFound
+		return
 	}
 
+	address = result.Address
+
 	return
 }
 
@@ -1435,15 +1958,18 @@ func checkUsername(s string, h int64) (address string, err error) {
 func getGasEstimate(gp rpc.GasEstimate_Params) (gas uint64, err error) {
 	var result rpc.GasEstimate_Result
 
-	rpc_client.WS, _, err = websocket.DefaultDialer.Dial("ws://"+session.Daemon+"/ws", nil)
+	// Registrations are sent from the username queue in the background, so each call uses its own connection instead of rpc_client
+	ws, _, err := websocket.DefaultDialer.Dial("ws://"+session.Daemon+"/ws", nil)
 	if err != nil {
 		return
 	}
+	defer ws.Close()
 
-	input_output := rwc.New(rpc_client.WS)
-	rpc_client.RPC = jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
+	input_output := rwc.New(ws)
+	client := jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
+	defer client.Close()
 
-	if err = rpc_client.RPC.CallResult(context.Background(), "DERO.GetGasEstimate", gp, &result); err != nil {
+	if err = client.CallResult(context.Background(), "DERO.GetGasEstimate", gp, &result); err != nil {
 		return
 	}
 
@@ -1456,8 +1982,8 @@ func getGasEstimate(gp rpc.GasEstimate_Params) (gas uint64, err error) {
 	return
 }
 
-// Register a new DERO username
-func registerUsername(s string) (storage uint64, err error) {
+// Register a new DERO username, returns the registration TXID
+func registerUsername(s string) (txid string, storage uint64, err error) {
 	// Check first if the name is taken
 	valid, _ := checkUsername(s, -1)
 	if valid != "" {
@@ -1511,163 +2037,307 @@ func registerUsername(s string) (storage uint64, err error) {
 		return
 	}
 
-	logger.Printf("[Username] Username Registration TXID:  %s\n", tx.GetHash().String())
+	txid = tx.GetHash().String()
+	logger.Printf("[Username] Username Registration TXID:  %s\n", txid)
 
 	return
 }
 
-// Check to make sure the message transaction meets criteria
-func checkMessagePack(m string, s string, r string) (err error) {
-	if m == "" {
+// Add usernames to the registration queue and start processing it if needed
+func queueUsernames(names []string) (err error) {
+	if engram.Disk == nil {
+		err = errors.New("error: no active account found")
 		return
 	}
 
-	mapAddress := ""
-	a, err := globals.ParseValidateAddress(r)
-	if err != nil {
-		//mapAddress, err = engram.Disk.NameToAddress(r)
-		mapAddress, err = checkUsername(r, -1)
-		if err != nil {
-			return
-		}
-		a, err = globals.ParseValidateAddress(mapAddress)
-		if err != nil {
-			return
-		}
-	}
-
-	if s == "" {
-		s = engram.Disk.GetAddress().String()
-	}
-
-	var amount uint64
+	usernameQueue.Lock()
+	defer usernameQueue.Unlock()
 
-	if a.Arguments.Has(rpc.RPC_VALUE_TRANSFER, rpc.DataUint64) { // but only it is present
-		//logger.Info("Transaction", "Value", globals.FormatMoney(a.Arguments.Value(rpc.RPC_VALUE_TRANSFER, rpc.DataUint64).(uint64)))
-		amount = a.Arguments.Value(rpc.RPC_VALUE_TRANSFER, rpc.DataUint64).(uint64)
-	} else {
-		amount, err = globals.ParseAmount("0.00001")
-		if err != nil {
-			//logger.Error(err, "Err parsing amount\n")
-			return
+	queued := make(map[string]bool)
+	for _, e := range usernameQueue.Entries {
+		if e.Status == USERNAME_STATUS_QUEUED || e.Status == USERNAME_STATUS_CONFIRMING {
+			queued[e.Name] = true
 		}
 	}
 
-	var arguments = rpc.Arguments{
-		{Name: rpc.RPC_DESTINATION_PORT, DataType: rpc.DataUint64, Value: uint64(1337)},
-		{Name: rpc.RPC_VALUE_TRANSFER, DataType: rpc.DataUint64, Value: amount},
-		{Name: rpc.RPC_COMMENT, DataType: rpc.DataString, Value: m},
-		{Name: rpc.RPC_NEEDS_REPLYBACK_ADDRESS, DataType: rpc.DataString, Value: s},
-	}
+	var add []UsernameRegistration
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
 
-	if a.IsIntegratedAddress() { // read everything from the address
-		if a.Arguments.Validate_Arguments() != nil {
-			//fmt.Printf(err, "Integrated Address  arguments could not be validated.\n")
+		// Name Service SCID Logic
+		//	15  IF STRLEN(name) >= 64 THEN GOTO 50 // skip names misuse
+		//	20  IF STRLEN(name) >= 6 THEN GOTO 40
+		if len(n) < 6 || len(n) > 63 {
+			err = fmt.Errorf("username %q must be between 6 and 63 characters", n)
 			return
 		}
 
-		if !a.Arguments.Has(rpc.RPC_DESTINATION_PORT, rpc.DataUint64) { // but only it is present
-			//fmt.Printf(fmt.Errorf("Integrated Address does not contain destination port.\n"), "")
+		if queued[n] {
+			err = fmt.Errorf("username %q is already queued", n)
 			return
 		}
 
-		arguments = append(arguments, rpc.Argument{Name: rpc.RPC_DESTINATION_PORT, DataType: rpc.DataUint64, Value: a.Arguments.Value(rpc.RPC_DESTINATION_PORT, rpc.DataUint64).(uint64)})
-
-		if a.Arguments.Has(rpc.RPC_EXPIRY, rpc.DataTime) { // but only it is present
-			if a.Arguments.Value(rpc.RPC_EXPIRY, rpc.DataTime).(time.Time).Before(time.Now().UTC()) {
-				//fmt.Printf(nil, "This address has expired.", "expiry time", a.Arguments.Value(rpc.RPC_EXPIRY, rpc.DataTime))
-				return
-			}
+		if address, _ := checkUsername(n, -1); address != "" {
+			err = fmt.Errorf("username %q already exists", n)
+			return
 		}
 
-		logger.Printf("Destination port is integrated in address. %d\n", a.Arguments.Value(rpc.RPC_DESTINATION_PORT, rpc.DataUint64).(uint64))
+		queued[n] = true
+		add = append(add, UsernameRegistration{Name: n, Status: USERNAME_STATUS_QUEUED})
+	}
 
-		if a.Arguments.Has(rpc.RPC_COMMENT, rpc.DataString) { // but only it is present
-			logger.Printf("Integrated Message: %s\n", a.Arguments.Value(rpc.RPC_COMMENT, rpc.DataString))
-			arguments = append(arguments, rpc.Argument{Name: rpc.RPC_COMMENT, DataType: rpc.DataString, Value: a.Arguments.Value(rpc.RPC_COMMENT, rpc.DataString)})
-		}
+	if len(add) == 0 {
+		err = errors.New("no usernames to register")
+		return
 	}
 
-	for _, arg := range arguments {
-		if !(arg.Name == rpc.RPC_COMMENT || arg.Name == rpc.RPC_EXPIRY || arg.Name == rpc.RPC_DESTINATION_PORT || arg.Name == rpc.RPC_SOURCE_PORT || arg.Name == rpc.RPC_VALUE_TRANSFER || arg.Name == rpc.RPC_NEEDS_REPLYBACK_ADDRESS) {
-			switch arg.DataType {
-			case rpc.DataString:
-				arguments = append(arguments, rpc.Argument{Name: arg.Name, DataType: arg.DataType, Value: arg.Value.(string)})
-			case rpc.DataInt64:
-				arguments = append(arguments, rpc.Argument{Name: arg.Name, DataType: arg.DataType, Value: arg.Value.(string)})
-			case rpc.DataUint64:
-				arguments = append(arguments, rpc.Argument{Name: arg.Name, DataType: arg.DataType, Value: arg.Value.(string)})
-			case rpc.DataFloat64:
-				arguments = append(arguments, rpc.Argument{Name: arg.Name, DataType: arg.DataType, Value: arg.Value.(string)})
-			case rpc.DataTime:
-				logger.Warnf("[Service] Time currently not supported.\n")
-			}
+	pending := 0
+	for _, e := range usernameQueue.Entries {
+		if e.Status == USERNAME_STATUS_QUEUED || e.Status == USERNAME_STATUS_CONFIRMING {
+			pending++
 		}
 	}
 
-	if a.Arguments.Has(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
-		arguments = append(arguments, rpc.Argument{Name: rpc.RPC_NEEDS_REPLYBACK_ADDRESS, DataType: rpc.DataString, Value: s})
+	if pending+len(add) > DEFAULT_USERNAME_QUEUE_LIMIT {
+		err = fmt.Errorf("a maximum of %d usernames can be queued", DEFAULT_USERNAME_QUEUE_LIMIT)
+		return
 	}
 
-	// if no arguments, use space by embedding a small comment
-	if len(arguments) == 0 { // allow user to enter Comment
-		arguments = append(arguments, rpc.Argument{Name: rpc.RPC_DESTINATION_PORT, DataType: rpc.DataUint64, Value: uint64(1337)})
-		arguments = append(arguments, rpc.Argument{Name: rpc.RPC_COMMENT, DataType: rpc.DataString, Value: m})
-	}
+	usernameQueue.Entries = append(usernameQueue.Entries, add...)
 
-	if _, err = arguments.CheckPack(transaction.PAYLOAD0_LIMIT); err != nil {
-		logger.Errorf("[Message] Arguments packing err: %s\n", err)
-		return
+	if !usernameQueue.Active {
+		usernameQueue.Active = true
+		go usernameQueue.process()
 	}
 
 	return
 }
 
-// Send a private message to another account
-func sendMessage(m string, s string, r string) (txid crypto.Hash, err error) {
-	if m == "" {
-		return
-	}
+// Register the queued usernames one at a time, each registration is confirmed and its ownership verified before the next is sent
+func (q *UsernameQueue) process() {
+	for {
+		q.Lock()
+		i := -1
+		for n, e := range q.Entries {
+			if e.Status == USERNAME_STATUS_QUEUED {
+				i = n
+				break
+			}
+		}
 
-	mapAddress := ""
-	a, err := globals.ParseValidateAddress(r)
-	if err != nil {
-		//mapAddress, err = engram.Disk.NameToAddress(r)
-		mapAddress, err = checkUsername(r, -1)
-		if err != nil {
+		if i < 0 || engram.Disk == nil {
+			q.Active = false
+			q.Unlock()
+			q.notify()
 			return
 		}
-		a, err = globals.ParseValidateAddress(mapAddress)
-		if err != nil {
-			return
+
+		name := q.Entries[i].Name
+		q.Entries[i].Status = USERNAME_STATUS_CONFIRMING
+		q.Unlock()
+		q.notify()
+
+		txid, err := q.register(name)
+		q.finish(name, txid, err)
+	}
+}
+
+// Send a registration and follow it through to a verified owner
+func (q *UsernameQueue) register(name string) (txid string, err error) {
+	// Another registration may have claimed the name since it was queued
+	if address, _ := resolveUsername(name, -1); address != "" {
+		err = errors.New("username already exists")
+		return
+	}
+
+	txid, storage, err := registerUsername(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "somehow the tx could not be built") {
+			err = fmt.Errorf("insufficient balance, need %s DERO", globals.FormatMoney(storage))
 		}
+		return
 	}
 
-	if s == "" {
-		s = engram.Disk.GetAddress().String()
+	q.Lock()
+	for n := range q.Entries {
+		if q.Entries[n].Name == name && q.Entries[n].Status == USERNAME_STATUS_CONFIRMING {
+			q.Entries[n].TXID = txid
+		}
+	}
+	q.Unlock()
+	q.notify()
+
+	if err = waitTxConfirmation(txid); err != nil {
+		return
 	}
 
-	amount, err := globals.ParseAmount("0.00001")
+	// The transaction can be valid while a competing registration won the name first
+	address, err := resolveUsername(name, -1)
 	if err != nil {
-		//logger.Error(err, "Err parsing amount\n")
+		err = fmt.Errorf("unable to verify ownership: %s", err)
+		return
+	}
+
+	nameService.store(name, -1, address)
+
+	if address != engram.Disk.GetAddress().String() {
+		err = fmt.Errorf("username was claimed by %s", address)
 		return
 	}
 
-	var arguments = rpc.Arguments{
-		{Name: rpc.RPC_DESTINATION_PORT, DataType: rpc.DataUint64, Value: uint64(1337)},
+	return
+}
+
+// Record the result of a registration, registered names are saved and become the primary username if none is set
+func (q *UsernameQueue) finish(name string, txid string, err error) {
+	if engram.Disk == nil {
+		return
+	}
+
+	status := USERNAME_STATUS_REGISTERED
+	message := ""
+	if err != nil {
+		status = USERNAME_STATUS_FAILED
+		message = err.Error()
+		logger.Errorf("[Username] Registration of %s failed: %s\n", name, err)
+	} else {
+		logger.Printf("[Username] Successfully registered username: %s\n", name)
+		nameService.forget(engram.Disk.GetAddress().String())
+
+		if err := addUsername(name); err != nil {
+			logger.Errorf("[Username] Error saving username: %s\n", err)
+		}
+
+		if getPrimaryUsername() != nil || session.Username == "" {
+			if err := setPrimaryUsername(name); err != nil {
+				logger.Errorf("[Username] Error setting primary username: %s\n", err)
+			} else {
+				session.Username = name
+			}
+		}
+	}
+
+	q.Lock()
+	for n := range q.Entries {
+		if q.Entries[n].Name == name && q.Entries[n].Status == USERNAME_STATUS_CONFIRMING {
+			q.Entries[n].TXID = txid
+			q.Entries[n].Status = status
+			q.Entries[n].Error = message
+		}
+	}
+	q.Unlock()
+	q.notify()
+}
+
+// Call the queue update function on the UI thread
+func (q *UsernameQueue) notify() {
+	q.Lock()
+	update := q.OnUpdate
+	q.Unlock()
+
+	if update != nil {
+		fyne.Do(update)
+	}
+}
+
+// Get a copy of the registration queue entries
+func (q *UsernameQueue) entries() (result []UsernameRegistration) {
+	q.Lock()
+	defer q.Unlock()
+
+	result = append(result, q.Entries...)
+	return
+}
+
+// Remove the registered and failed entries from the queue
+func (q *UsernameQueue) clear() {
+	q.Lock()
+	defer q.Unlock()
+
+	var entries []UsernameRegistration
+	for _, e := range q.Entries {
+		if e.Status == USERNAME_STATUS_QUEUED || e.Status == USERNAME_STATUS_CONFIRMING {
+			entries = append(entries, e)
+		}
+	}
+
+	q.Entries = entries
+}
+
+// Drop the queue when an account is closed, an active registration stops once it sees there is no account
+func (q *UsernameQueue) reset() {
+	q.Lock()
+	q.Entries = nil
+	q.OnUpdate = nil
+	q.Unlock()
+}
+
+// Wait for a transaction to be mined into a valid block within DEFAULT_CONFIRMATION_TIMEOUT blocks
+func waitTxConfirmation(txid string) (err error) {
+	sHeight := walletapi.Get_Daemon_Height()
+	height := int64(-1)
+
+	for {
+		if engram.Disk == nil {
+			err = errors.New("error: no active account found")
+			return
+		}
+
+		if h := walletapi.Get_Daemon_Height(); h != height {
+			height = h
+
+			result, _ := getTxData(txid)
+			if len(result.Txs) > 0 {
+				if result.Txs[0].ValidBlock != "" && !result.Txs[0].In_pool {
+					return
+				}
+
+				if len(result.Txs[0].InvalidBlock) > 0 && result.Txs[0].ValidBlock == "" && !result.Txs[0].In_pool {
+					err = errors.New("transaction was rejected")
+					return
+				}
+			}
+
+			if h > sHeight+int64(DEFAULT_CONFIRMATION_TIMEOUT) {
+				err = errors.New("transaction was not confirmed")
+				return
+			}
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// Build the payload arguments for a message to the destination address, the comment is added per message part
+func messageArguments(s string, a *rpc.Address, ms MessageSettings) (arguments rpc.Arguments, amount uint64, err error) {
+	if s == "" {
+		s = engram.Disk.GetAddress().String()
+	}
+
+	amount = ms.Amount
+
+	arguments = rpc.Arguments{
+		{Name: rpc.RPC_DESTINATION_PORT, DataType: rpc.DataUint64, Value: ms.Port},
 		{Name: rpc.RPC_VALUE_TRANSFER, DataType: rpc.DataUint64, Value: amount},
-		{Name: rpc.RPC_EXPIRY, DataType: rpc.DataTime, Value: time.Now().Add(time.Hour).UTC()},
-		{Name: rpc.RPC_COMMENT, DataType: rpc.DataString, Value: m},
-		{Name: rpc.RPC_NEEDS_REPLYBACK_ADDRESS, DataType: rpc.DataString, Value: s},
 	}
 
+	if ms.Expiry > 0 {
+		arguments = append(arguments, rpc.Argument{Name: rpc.RPC_EXPIRY, DataType: rpc.DataTime, Value: time.Now().Add(time.Duration(ms.Expiry) * time.Second).UTC()})
+	}
+
+	arguments = append(arguments, rpc.Argument{Name: rpc.RPC_NEEDS_REPLYBACK_ADDRESS, DataType: rpc.DataString, Value: s})
+
 	if a.IsIntegratedAddress() {
-		if a.Arguments.Validate_Arguments() != nil {
+		if err = a.Arguments.Validate_Arguments(); err != nil {
 			return
 		}
 
 		if !a.Arguments.Has(rpc.RPC_DESTINATION_PORT, rpc.DataUint64) {
 			logger.Errorf("[Send Message] Integrated Address does not contain destination port.\n")
+			err = errors.New("integrated address does not contain destination port")
 			return
 		}
 
@@ -1676,6 +2346,7 @@ func sendMessage(m string, s string, r string) (txid crypto.Hash, err error) {
 		if a.Arguments.Has(rpc.RPC_EXPIRY, rpc.DataTime) {
 			if a.Arguments.Value(rpc.RPC_EXPIRY, rpc.DataTime).(time.Time).Before(time.Now().UTC()) {
 				logger.Errorf("[Send Message] This address has expired on %x\n", a.Arguments.Value(rpc.RPC_EXPIRY, rpc.DataTime))
+				err = errors.New("this address has expired")
 				return
 			} else {
 				logger.Warnf("[Send Message] This address will expire on %x\n", a.Arguments.Value(rpc.RPC_EXPIRY, rpc.DataTime))
@@ -1684,60 +2355,154 @@ func sendMessage(m string, s string, r string) (txid crypto.Hash, err error) {
 
 		logger.Printf("[Send Message] Destination port is integrated in address. %x\n", a.Arguments.Value(rpc.RPC_DESTINATION_PORT, rpc.DataUint64).(uint64))
 
-		if a.Arguments.Has(rpc.RPC_COMMENT, rpc.DataString) {
-			logger.Printf("[Send Message] Integrated Message: %s\n", a.Arguments.Value(rpc.RPC_COMMENT, rpc.DataString))
-			arguments = append(arguments, rpc.Argument{Name: rpc.RPC_COMMENT, DataType: rpc.DataString, Value: a.Arguments.Value(rpc.RPC_COMMENT, rpc.DataString)})
+		if a.Arguments.Has(rpc.RPC_VALUE_TRANSFER, rpc.DataUint64) {
+			amount = a.Arguments.Value(rpc.RPC_VALUE_TRANSFER, rpc.DataUint64).(uint64)
 		}
 	}
 
-	for _, arg := range arguments {
-		if !(arg.Name == rpc.RPC_COMMENT || arg.Name == rpc.RPC_EXPIRY || arg.Name == rpc.RPC_DESTINATION_PORT || arg.Name == rpc.RPC_SOURCE_PORT || arg.Name == rpc.RPC_VALUE_TRANSFER || arg.Name == rpc.RPC_NEEDS_REPLYBACK_ADDRESS) {
-			switch arg.DataType {
-			case rpc.DataString:
-				arguments = append(arguments, rpc.Argument{Name: arg.Name, DataType: arg.DataType, Value: arg.Value.(string)})
-			case rpc.DataInt64:
-				arguments = append(arguments, rpc.Argument{Name: arg.Name, DataType: arg.DataType, Value: arg.Value.(string)})
-			case rpc.DataUint64:
-				arguments = append(arguments, rpc.Argument{Name: arg.Name, DataType: arg.DataType, Value: arg.Value.(string)})
-			case rpc.DataFloat64:
-				arguments = append(arguments, rpc.Argument{Name: arg.Name, DataType: arg.DataType, Value: arg.Value.(string)})
-			case rpc.DataTime:
-				logger.Warnf("[Service] Time currently not supported.\n")
+	return
+}
+
+// Prefix the comment integrated in the destination address to the message, so it is carried by the message parts
+func messageComment(m string, a *rpc.Address) string {
+	if !a.IsIntegratedAddress() || !a.Arguments.Has(rpc.RPC_COMMENT, rpc.DataString) {
+		return m
+	}
+
+	comment := a.Arguments.Value(rpc.RPC_COMMENT, rpc.DataString).(string)
+	if comment == "" {
+		return m
+	}
+
+	return comment + "\n" + m
+}
+
+// Add the comment and multipart arguments (when parts > 1) to a copy of the message arguments
+func messagePartArguments(arguments rpc.Arguments, m string, id, part, parts uint64) (result rpc.Arguments) {
+	result = append(rpc.Arguments{}, arguments...)
+	result = append(result, rpc.Argument{Name: rpc.RPC_COMMENT, DataType: rpc.DataString, Value: m})
+	if parts > 1 {
+		result = append(result, rpc.Argument{Name: MESSAGE_ARG_ID, DataType: rpc.DataUint64, Value: id})
+		result = append(result, rpc.Argument{Name: MESSAGE_ARG_PART, DataType: rpc.DataUint64, Value: part})
+		result = append(result, rpc.Argument{Name: MESSAGE_ARG_PARTS, DataType: rpc.DataUint64, Value: parts})
+	}
+
+	return
+}
+
+// Split a message into parts where each part fits within a single transaction payload
+func splitMessage(m string, arguments rpc.Arguments) (parts []string, err error) {
+	if _, err = messagePartArguments(arguments, m, 0, 0, 0).CheckPack(transaction.PAYLOAD0_LIMIT); err == nil {
+		parts = append(parts, m)
+		return
+	}
+
+	// Size each part against the largest multipart argument values
+	fits := func(s string) bool {
+		_, err := messagePartArguments(arguments, s, math.MaxUint64, DEFAULT_MESSAGE_MAX_PARTS, DEFAULT_MESSAGE_MAX_PARTS).CheckPack(transaction.PAYLOAD0_LIMIT)
+		return err == nil
+	}
+
+	runes := []rune(m)
+	for len(runes) > 0 {
+		if len(parts) >= DEFAULT_MESSAGE_MAX_PARTS {
+			err = fmt.Errorf("message exceeds %d parts", DEFAULT_MESSAGE_MAX_PARTS)
+			parts = nil
+			return
+		}
+
+		n := 0
+		lo, hi := 1, len(runes)
+		for lo <= hi {
+			mid := (lo + hi) / 2
+			if fits(string(runes[:mid])) {
+				n = mid
+				lo = mid + 1
+			} else {
+				hi = mid - 1
 			}
 		}
+
+		if n == 0 {
+			err = errors.New("message arguments leave no room for a comment")
+			parts = nil
+			return
+		}
+
+		parts = append(parts, string(runes[:n]))
+		runes = runes[n:]
 	}
 
-	if a.Arguments.Has(rpc.RPC_VALUE_TRANSFER, rpc.DataUint64) {
-		amount = a.Arguments.Value(rpc.RPC_VALUE_TRANSFER, rpc.DataUint64).(uint64)
-	} else {
-		amount, err = globals.ParseAmount("0.00001")
+	err = nil
+
+	return
+}
+
+// Parse a message recipient (address or username) to its address
+func parseMessageRecipient(r string) (a *rpc.Address, err error) {
+	a, err = globals.ParseValidateAddress(r)
+	if err != nil {
+		var mapAddress string
+		mapAddress, err = checkUsername(r, -1)
 		if err != nil {
-			logger.Errorf("[Send Message] Failed parsing transfer amount: %s\n", err)
 			return
 		}
+		a, err = globals.ParseValidateAddress(mapAddress)
+	}
+
+	return
+}
+
+// Check to make sure the message transaction meets criteria, returns the number of transactions required to send it
+// and the total cost of sending it (amount and fees of every part)
+func checkMessagePack(m string, s string, r string) (parts int, cost uint64, err error) {
+	if m == "" {
+		return
 	}
 
-	if a.Arguments.Has(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
-		arguments = append(arguments, rpc.Argument{Name: rpc.RPC_NEEDS_REPLYBACK_ADDRESS, DataType: rpc.DataString, Value: s})
+	a, err := parseMessageRecipient(r)
+	if err != nil {
+		return
 	}
 
-	if len(arguments) == 0 {
-		arguments = append(arguments, rpc.Argument{Name: rpc.RPC_DESTINATION_PORT, DataType: rpc.DataUint64, Value: uint64(1337)})
-		arguments = append(arguments, rpc.Argument{Name: rpc.RPC_COMMENT, DataType: rpc.DataString, Value: m})
+	ms := getMessageSettings(a.BaseAddress().String())
+
+	arguments, amount, err := messageArguments(s, a, ms)
+	if err != nil {
+		return
 	}
 
+	split, err := splitMessage(messageComment(m, a), arguments)
+	if err != nil {
+		logger.Errorf("[Message] Arguments packing err: %s\n", err)
+		return
+	}
+
+	parts = len(split)
+	cost = uint64(parts) * (amount + messageFees(ms.Ringsize))
+
+	return
+}
+
+// Calculate the fees for a single message transaction with the given ring size
+func messageFees(ringsize uint64) uint64 {
+	return ((ringsize + 1) * config.FEE_PER_KB) / 4
+}
+
+// Send a single message part to the destination address
+func sendMessagePart(arguments rpc.Arguments, amount uint64, ringsize uint64, a *rpc.Address) (txid crypto.Hash, err error) {
 	if _, err = arguments.CheckPack(transaction.PAYLOAD0_LIMIT); err != nil {
 		logger.Errorf("[Message] Arguments packing err: %s\n", err)
 		return
 	}
 
-	fees := ((uint64(engram.Disk.GetRingSize()) + 1) * config.FEE_PER_KB) / 4
+	fees := messageFees(ringsize)
 
 	logger.Printf("[Message] Calculated Fees: %d\n", fees)
 
 	transfer := rpc.Transfer{Amount: amount, Destination: a.String(), Payload_RPC: arguments}
 
-	tx, err := engram.Disk.TransferPayload0([]rpc.Transfer{transfer}, 0, false, rpc.Arguments{}, fees, false)
+	tx, err := engram.Disk.TransferPayload0([]rpc.Transfer{transfer}, ringsize, false, rpc.Arguments{}, fees, false)
 	if err != nil {
 		logger.Errorf("[Message] Error while building transaction: %s\n", err)
 		return
@@ -1755,1510 +2520,5684 @@ func sendMessage(m string, s string, r string) (txid crypto.Hash, err error) {
 	return
 }
 
-// Get a list of message transactions from an address
-func getMessagesFromUser(s string, h uint64) (result []rpc.Entry) {
-	var zeroscid crypto.Hash
-	if s == "" {
+// Send a private message to another account, messages too long for a single payload are sent in multiple parts
+// and each part is confirmed before the next is sent, progress is called before each part is sent
+func sendMessage(m string, s string, r string, progress func(part, parts int)) (txids []crypto.Hash, err error) {
+	if m == "" {
+		return
+	}
+
+	a, err := parseMessageRecipient(r)
+	if err != nil {
+		return
+	}
+
+	ms := getMessageSettings(a.BaseAddress().String())
+
+	arguments, amount, err := messageArguments(s, a, ms)
+	if err != nil {
+		logger.Errorf("[Message] Building arguments: %s\n", err)
 		return
 	}
 
-	messages := engram.Disk.Get_Payments_DestinationPort(zeroscid, uint64(1337), h)
+	split, err := splitMessage(messageComment(m, a), arguments)
+	if err != nil {
+		logger.Errorf("[Message] Arguments packing err: %s\n", err)
+		return
+	}
 
-	for m := range messages {
-		var username bool
-		var username2 bool
+	id, err := rand.Int(rand.Reader, new(big.Int).SetUint64(math.MaxUint64))
+	if err != nil {
+		return
+	}
 
-		txid := messages[m].TXID
-		_, tx := engram.Disk.Get_Payments_TXID(zeroscid, txid)
+	parts := uint64(len(split))
+	for i, comment := range split {
+		if progress != nil {
+			progress(i+1, len(split))
+		}
 
-		//check, err := engram.Disk.NameToAddress(s)
-		check, err := checkUsername(s, -1)
+		var txid crypto.Hash
+		txid, err = sendMessagePart(messagePartArguments(arguments, comment, id.Uint64(), uint64(i+1), parts), amount, ms.Ringsize, a)
 		if err != nil {
-			username = false
-		} else {
-			username = true
+			return
 		}
 
-		if tx.Incoming {
-			if tx.Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
-				height := int64(tx.Height)
-				check2, err := checkUsername(tx.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string), height)
-				if err != nil {
-					username2 = false
-					addr, err := globals.ParseValidateAddress(tx.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string))
-					if err != nil {
-						check2 = ""
-					} else {
-						check2 = addr.String()
-					}
-				} else {
-					username2 = true
-				}
+		txids = append(txids, txid)
 
-				// Check for spoofing
-				//if ring_member_exists(txid, check2) {
+		if i == len(split)-1 {
+			break
+		}
 
-				if username && username2 {
-					if check == check2 {
-						result = append(result, messages[m])
-					}
-				} else if !username && !username2 {
-					if s == tx.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string) {
-						result = append(result, messages[m])
-					}
-				} else if check == tx.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string) {
-					result = append(result, messages[m])
-				} else if s == check2 {
-					result = append(result, messages[m])
-				}
-				//}
+		// Wait for the part to be confirmed so the next part is built from the updated balance
+		var zeroscid crypto.Hash
+		sHeight := walletapi.Get_Daemon_Height()
+		for {
+			if engram.Disk == nil {
+				err = errors.New("error: no active account found")
+				return
 			}
-		} else {
-			//addr, err := engram.Disk.NameToAddress(s)
-			addr, err := checkUsername(s, -1)
-			if err != nil {
-				if tx.Destination == s {
-					result = append(result, messages[m])
-				}
-			} else {
-				if tx.Destination == addr {
-					result = append(result, messages[m])
-				}
+
+			_, entry := engram.Disk.Get_Payments_TXID(zeroscid, txid.String())
+			if entry.TXID == txid.String() {
+				break
+			}
+
+			if walletapi.Get_Daemon_Height() > sHeight+int64(DEFAULT_CONFIRMATION_TIMEOUT) {
+				err = fmt.Errorf("message part %d/%d was not confirmed", i+1, parts)
+				logger.Errorf("[Message] %s\n", err)
+				return
 			}
+
+			time.Sleep(time.Second)
 		}
 	}
 
 	return
 }
 
-// Get a list of all message transactions and sort them by address
-func getMessages(h uint64) (result []string) {
-	var zeroscid crypto.Hash
-	messages := engram.Disk.Get_Payments_DestinationPort(zeroscid, uint64(1337), h)
+// Build a transfer with its own reply-back payload for each recipient (address or username) of a message broadcast,
+// broadcasts use the global message settings and must fit within a single payload
+func broadcastTransfers(m string, s string, recipients []string) (transfers []rpc.Transfer, ms MessageSettings, err error) {
+	if m == "" {
+		err = errors.New("message is empty")
+		return
+	}
 
-	for m := range messages {
-		if messages[m].Incoming {
-			if messages[m].Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
-				if messages[m].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string) == "" {
+	ms = getGlobalMessageSettings()
+	self := engram.Disk.GetAddress().BaseAddress().String()
+	seen := make(map[string]bool)
 
-				} else {
-					height := int64(messages[m].Height)
-					sender, _ := checkUsername(messages[m].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string), height)
-					if sender == "" {
-						addr, err := globals.ParseValidateAddress(messages[m].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string))
-						if err != nil {
+	for _, r := range recipients {
+		var a *rpc.Address
+		a, err = parseMessageRecipient(r)
+		if err != nil {
+			err = fmt.Errorf("%s: %s", r, err)
+			return
+		}
 
-						} else {
-							sender = addr.String()
-							for r := range result {
-								if r > -1 && r < len(result) {
-									if strings.Contains(result[r], sender+"~~~") {
-										copy(result[r:], result[r+1:])
-										result[len(result)-1] = ""
-										result = result[:len(result)-1]
-									}
-								}
-							}
-							result = append(result, sender+"~~~")
-						}
-					} else {
-						// Check for spoofing
-						//if ring_member_exists(messages[m].TXID, sender) {
-						for r := range result {
-							if r > -1 && r < len(result) {
-								//if strings.Contains(result[r], sender+"```"+messages[m].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string)) {
-								if strings.Contains(result[r], sender+"~~~") {
-									copy(result[r:], result[r+1:])
-									result[len(result)-1] = ""
-									result = result[:len(result)-1]
-								}
-							}
-						}
-						result = append(result, sender+"~~~"+messages[m].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string))
-						//} else {
-						// TODO: Add spoofing address to the ban list?
-						//}
-					}
-				}
-			}
-		} else {
-			if messages[m].Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
-				uname := ""
-				for r := range result {
-					if r > -1 && r < len(result) {
-						if strings.Contains(result[r], messages[m].Destination+"~~~") {
-							split := strings.Split(result[r], "~~~")
-							uname = split[1]
-							copy(result[r:], result[r+1:])
-							result[len(result)-1] = ""
-							result = result[:len(result)-1]
-						}
-					}
-				}
-				result = append(result, messages[m].Destination+"~~~"+uname)
-			}
+		base := a.BaseAddress().String()
+		if base == self {
+			err = errors.New("cannot send a message to yourself")
+			return
 		}
-	}
 
-	sort.Sort(sort.Reverse(sort.StringSlice(result)))
-	return
-}
+		if seen[base] {
+			continue
+		}
+		seen[base] = true
 
-// Returns a list of registered usernames from Gnomon
-func queryUsernames(address string) (result []string, err error) {
-	if gnomon.Index != nil && engram.Disk != nil {
-		result, _ = gnomon.Graviton.GetSCIDKeysByValue("0000000000000000000000000000000000000000000000000000000000000001", address, engram.Disk.Get_Daemon_TopoHeight(), false)
-		if len(result) <= 0 {
-			result, _, err = gnomon.Index.GetSCIDKeysByValue(nil, "0000000000000000000000000000000000000000000000000000000000000001", address, engram.Disk.Get_Daemon_TopoHeight())
-			if err != nil {
-				logger.Errorf("[Gnomon] Querying usernames failed: %s\n", err)
-				return
-			}
+		if len(seen) > DEFAULT_MESSAGE_MAX_RECIPIENTS {
+			err = fmt.Errorf("broadcast exceeds %d recipients", DEFAULT_MESSAGE_MAX_RECIPIENTS)
+			return
 		}
 
-		sort.Strings(result)
-	}
+		var arguments rpc.Arguments
+		var amount uint64
+		arguments, amount, err = messageArguments(s, a, ms)
+		if err != nil {
+			return
+		}
 
-	return
-}
+		arguments = messagePartArguments(arguments, messageComment(m, a), 0, 0, 0)
+		if _, err = arguments.CheckPack(transaction.PAYLOAD0_LIMIT); err != nil {
+			err = errors.New("message is too long to broadcast")
+			return
+		}
 
-// Get the local list of registered usernames saved from previous Gnomon scans
-func getUsernames() (result []string, err error) {
-	usernames, err := GetEncryptedValue("Usernames", []byte("usernames"))
+		transfers = append(transfers, rpc.Transfer{Amount: amount, Destination: a.String(), Payload_RPC: arguments})
+	}
+
+	if len(transfers) < 1 {
+		err = errors.New("no recipients")
+	}
+
+	return
+}
+
+// Check to make sure the message broadcast meets criteria, returns the total cost of sending it
+func checkBroadcastPack(m string, s string, recipients []string) (cost uint64, err error) {
+	transfers, ms, err := broadcastTransfers(m, s, recipients)
 	if err != nil {
 		return
 	}
 
-	result = strings.Split(string(usernames), ",")
+	for _, t := range transfers {
+		cost += t.Amount
+	}
+
+	cost += messageFees(ms.Ringsize) * uint64(len(transfers))
+
 	return
 }
 
-// Set the Primary Username saved to a wallet's datashard
-func setPrimaryUsername(s string) (err error) {
-	err = StoreEncryptedValue("settings", []byte("username"), []byte(s))
+// Send the same private message to many accounts in a single transaction
+func sendBroadcast(m string, s string, recipients []string) (txid crypto.Hash, err error) {
+	transfers, ms, err := broadcastTransfers(m, s, recipients)
+	if err != nil {
+		logger.Errorf("[Message] Building broadcast: %s\n", err)
+		return
+	}
+
+	fees := messageFees(ms.Ringsize) * uint64(len(transfers))
+
+	logger.Printf("[Message] Calculated Fees: %d\n", fees)
+
+	tx, err := engram.Disk.TransferPayload0(transfers, ms.Ringsize, false, rpc.Arguments{}, fees, false)
+	if err != nil {
+		logger.Errorf("[Message] Error while building transaction: %s\n", err)
+		return
+	}
+
+	if err = engram.Disk.SendTransaction(tx); err != nil {
+		logger.Errorf("[Message] Error while dispatching transaction: %s\n", err)
+		return
+	}
+
+	txid = tx.GetHash()
+
+	logger.Printf("[Message] Dispatched broadcast to %d recipients: %s\n", len(transfers), txid)
+
 	return
 }
 
-// Get the Primary Username saved to a wallet's datashard
-func getPrimaryUsername() (err error) {
-	u, err := GetEncryptedValue("settings", []byte("username"))
+// Get the saved message groups of contact addresses from the active wallet's datashard
+func getMessageGroups() (groups map[string][]string) {
+	groups = make(map[string][]string)
+
+	stored, err := GetEncryptedValue("Messages", []byte("groups"))
 	if err != nil {
-		session.Username = ""
 		return
 	}
-	session.Username = string(u)
+
+	if err = json.Unmarshal(stored, &groups); err != nil {
+		logger.Errorf("[Message] Loading groups: %s\n", err)
+		groups = make(map[string][]string)
+	}
+
 	return
 }
 
-// Start the Gnomon indexer
-func startGnomon() {
-	if walletapi.Connected {
-		if gnomon.Index == nil && gnomon.Active == 1 {
-			path := filepath.Join(AppPath(), "datashards", "gnomon")
-			switch session.Network {
-			case NETWORK_TESTNET:
-				path = filepath.Join(AppPath(), "datashards", "gnomon_testnet")
-			case NETWORK_SIMULATOR:
-				path = filepath.Join(AppPath(), "datashards", "gnomon_simulator")
-			}
-			gnomon.BBolt, _ = storage.NewBBoltDB(path, "gnomon")
-			gnomon.Graviton, _ = storage.NewGravDB(path, "25ms")
-			term := []string(nil)
-			term = append(term, "Function Initialize")
-			height, err := gnomon.Graviton.GetLastIndexHeight()
-			if err != nil {
-				height = 0
-			}
+// Save a message group of contact addresses to the active wallet's datashard, no members removes the group
+func setMessageGroup(name string, members []string) (err error) {
+	if name == "" {
+		err = errors.New("group name is empty")
+		return
+	}
 
-			// Fastsync Config
-			config := &structures.FastSyncConfig{
-				Enabled:           true,
-				SkipFSRecheck:     true,
-				ForceFastSync:     true,
-				ForceFastSyncDiff: 20,
-				NoCode:            true,
+	groups := getMessageGroups()
+	if len(members) < 1 {
+		delete(groups, name)
+	} else {
+		groups[name] = members
+	}
+
+	data, err := json.Marshal(groups)
+	if err != nil {
+		return
+	}
+
+	return StoreEncryptedValue("Messages", []byte("groups"), data)
+}
+
+// Combine the indexed parts of multipart messages, missing parts are noted in the combined message
+func assembleMessages(msgs []IndexedMessage) (result []IndexedMessage) {
+	grouped := make(map[string][]IndexedMessage)
+	for _, m := range msgs {
+		if m.Parts > 1 {
+			key := fmt.Sprintf("%t.%d", m.Incoming, m.ID)
+			if _, ok := grouped[key]; !ok {
+				// Placeholder to retain message order by the first received part
+				result = append(result, IndexedMessage{TXID: key})
 			}
+			grouped[key] = append(grouped[key], m)
+		} else {
+			result = append(result, m)
+		}
+	}
 
-			// exclude the Gnomon SC, etc. to keep faster sync times
-			var exclusions []string
+	for i := range result {
+		parts, ok := grouped[result[i].TXID]
+		if !ok {
+			continue
+		}
 
-			gnomon.Index = indexer.NewIndexer(gnomon.Graviton, gnomon.BBolt, "gravdb", term, height, session.Daemon, "daemon", false, false, config, exclusions)
-			indexer.InitLog(globals.Arguments, os.Stdout)
+		sort.SliceStable(parts, func(x, y int) bool {
+			return parts[x].Part < parts[y].Part
+		})
 
-			// We can allow parallel processing of x blocks at a time
-			go gnomon.Index.StartDaemonMode(1)
+		combined := parts[0]
+		combined.Comment = ""
+		combined.Received = 0
+		combined.Verified = true
 
-			logger.Printf("[Gnomon] Scan Status: [%d / %d]\n", height, gnomon.Index.LastIndexedHeight)
+		var last uint64
+		for _, p := range parts {
+			if p.Part == last {
+				continue
+			}
+
+			for missing := last + 1; missing < p.Part; missing++ {
+				combined.Comment += fmt.Sprintf(" [part %d missing] ", missing)
+			}
+
+			combined.Comment += p.Comment
+			combined.Received++
+			combined.Height = p.Height
+			if !p.Verified {
+				combined.Verified = false
+			}
+			last = p.Part
+		}
+
+		for missing := last + 1; missing <= combined.Parts; missing++ {
+			combined.Comment += fmt.Sprintf(" [part %d missing]", missing)
 		}
+
+		result[i] = combined
 	}
+
+	return
 }
 
-// Stop all indexers and close Gnomon
-func stopGnomon() {
-	if gnomon.Index != nil {
-		gnomon.Index.Close()
-		gnomon.Index = nil
-		logger.Printf("[Gnomon] Closed all indexers.\n")
+// Load the message index from the active wallet's datashard, the caller must hold the index lock
+func (m *MessageIndex) load() {
+	if m.Contacts != nil {
+		return
 	}
-}
 
-// Method of Gnomon GetAllOwnersAndSCIDs() where DB type is defined by Indexer.DBType
-func (g *Gnomon) GetAllOwnersAndSCIDs() (scids map[string]string) {
-	switch g.Index.DBType {
-	case "gravdb":
-		return g.Index.GravDBBackend.GetAllOwnersAndSCIDs()
-	case "boltdb":
-		return g.Index.BBSBackend.GetAllOwnersAndSCIDs()
-	default:
+	m.Height = 0
+	m.Contacts = make(map[string]*MessageContact)
+
+	stored, err := GetEncryptedValue("Messages", []byte("index"))
+	if err != nil {
+		logger.Debugf("[Message] Loading index: %s\n", err)
 		return
 	}
-}
 
-// Method of Gnomon GetAllSCIDVariableDetails() where DB type is defined by Indexer.DBType
-func (g *Gnomon) GetAllSCIDVariableDetails(scid string) (vars []*structures.SCIDVariable) {
-	switch g.Index.DBType {
-	case "gravdb":
-		return g.Index.GravDBBackend.GetAllSCIDVariableDetails(scid)
-	case "boltdb":
-		return g.Index.BBSBackend.GetAllSCIDVariableDetails(scid)
-	default:
+	var index struct {
+		Height   uint64                     `json:"height"`
+		Contacts map[string]*MessageContact `json:"contacts"`
+	}
+
+	if err = json.Unmarshal(stored, &index); err != nil {
+		logger.Errorf("[Message] Loading index: %s\n", err)
 		return
 	}
+
+	m.Height = index.Height
+	if index.Contacts != nil {
+		m.Contacts = index.Contacts
+	}
 }
 
-// Method of Gnomon GetSCIDValuesByKey() where DB type is defined by Indexer.DBType
-func (g *Gnomon) GetSCIDValuesByKey(scid string, key interface{}) (valuesstring []string, valuesuint64 []uint64) {
-	switch g.Index.DBType {
-	case "gravdb":
-		return g.Index.GravDBBackend.GetSCIDValuesByKey(scid, key, g.Index.ChainHeight, true)
-	case "boltdb":
-		return g.Index.BBSBackend.GetSCIDValuesByKey(scid, key, g.Index.ChainHeight, true)
-	default:
+// Store the message index to the active wallet's datashard, the caller must hold the index lock
+func (m *MessageIndex) store() (err error) {
+	index := struct {
+		Height   uint64                     `json:"height"`
+		Contacts map[string]*MessageContact `json:"contacts"`
+	}{
+		Height:   m.Height,
+		Contacts: m.Contacts,
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
 		return
 	}
+
+	return StoreEncryptedValue("Messages", []byte("index"), data)
 }
 
-// Add a var store only scid to Gnomon DB
-func (g *Gnomon) AddSCIDToIndex(scid string) (err error) {
-	add := make(map[string]*structures.FastSyncImport)
-	add[scid] = &structures.FastSyncImport{}
+// Clear the message index held in memory when an account is closed
+func (m *MessageIndex) reset() {
+	m.Lock()
+	m.Height = 0
+	m.Contacts = nil
+	m.Unlock()
+}
 
-	return gnomon.Index.AddSCIDToIndex(add, false, true)
+// Get the indexed messages for a contact address from the active wallet's datashard
+func getIndexedMessages(address string) (result []IndexedMessage) {
+	stored, err := GetEncryptedValue("Messages", []byte(address))
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(stored, &result); err != nil {
+		logger.Errorf("[Message] Loading messages for %s: %s\n", address, err)
+	}
+
+	return
 }
 
-// Get the current code of a smart contract
-func getContractCode(scid string) (code string, err error) {
-	var params = rpc.GetSC_Params{SCID: scid, Variables: false, Code: true}
-	var result rpc.GetSC_Result
+// Resolve a message contact (address or username) to its base address at height h
+func resolveMessageContact(s string, h int64) (address string, username string, err error) {
+	addr, err := globals.ParseValidateAddress(s)
+	if err == nil {
+		address = addr.BaseAddress().String()
+		return
+	}
 
-	rpc_client.WS, _, err = websocket.DefaultDialer.Dial("ws://"+session.Daemon+"/ws", nil)
+	username = s
+	check, err := checkUsername(s, h)
 	if err != nil {
 		return
 	}
 
-	input_output := rwc.New(rpc_client.WS)
-	rpc_client.RPC = jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
-
-	err = rpc_client.RPC.CallResult(context.Background(), "DERO.GetSC", params, &result)
+	addr, err = globals.ParseValidateAddress(check)
 	if err != nil {
-		logger.Errorf("[Engram] Error getting SC code: %s\n", err)
 		return
 	}
 
-	code = result.Code
+	address = addr.BaseAddress().String()
 
 	return
 }
 
-// DVM starter InitializePrivate() example for smart contract builder
-func dvmInitFuncExample() string {
-	return `Function InitializePrivate() Uint64
-10 IF EXISTS("owner") == 0 THEN GOTO 30
-20 RETURN 1
-30 STORE("owner", SIGNER())
-31 STORE("var_header_name", "")
-32 STORE("var_header_description", "")
-33 STORE("var_header_icon", "")
-40 RETURN 0
-End Function`
-}
+// Merge messages into the indexed messages for a contact address, messages are matched by TXID
+func mergeIndexedMessages(address string, update []IndexedMessage) (err error) {
+	stored := getIndexedMessages(address)
+	exists := make(map[string]int, len(stored))
+	for i, m := range stored {
+		exists[m.TXID] = i
+	}
+
+	changed := false
+	for _, m := range update {
+		if i, ok := exists[m.TXID]; !ok {
+			stored = append(stored, m)
+			exists[m.TXID] = len(stored) - 1
+			changed = true
+		} else if stored[i].Verified != m.Verified {
+			stored[i].Verified = m.Verified
+			changed = true
+		}
+	}
 
-// DVM starter function for smart contract builder
-func dvmFuncExample(increment int) string {
-	return `Function new` + fmt.Sprintf("%d", increment) + `() Uint64
-10 
-20 
-30 RETURN 0
-End Function`
-}
+	if !changed {
+		return
+	}
 
-// Verification overlay for user actions with or without password
-func verificationOverlay(password bool, headerText, subText, dismiss string, callback func(bool)) {
-	overlay := session.Window.Canvas().Overlays()
+	sort.SliceStable(stored, func(i, j int) bool {
+		if stored[i].Height == stored[j].Height {
+			return stored[i].Time.Before(stored[j].Time)
+		}
+		return stored[i].Height < stored[j].Height
+	})
 
-	rectSpacer := canvas.NewRectangle(color.Transparent)
-	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return
+	}
 
-	if password {
-		headerText = "ACCOUNT  VERIFICATION  REQUIRED"
-		dismiss = "Submit"
+	return StoreEncryptedValue("Messages", []byte(address), data)
+}
+
+// Update the local message index with any new entries on the message ports from the active wallet
+func updateMessageIndex() (err error) {
+	if engram.Disk == nil {
+		err = errors.New("error: no active account found")
+		return
 	}
 
-	header := canvas.NewText(headerText, colors.Gray)
-	header.TextSize = 14
-	header.Alignment = fyne.TextAlignCenter
-	header.TextStyle = fyne.TextStyle{Bold: true}
+	// Updates run one at a time, the index is only locked to read its height and to apply the results so the
+	// daemon calls for resolving and verifying senders never block the message views
+	messages.Index.updating.Lock()
+	defer messages.Index.updating.Unlock()
 
-	btnConfirm := widget.NewButton(dismiss, nil)
-	btnConfirm.Disable()
+	messages.Index.Lock()
+	messages.Index.load()
+	indexedHeight := messages.Index.Height
+	ports := messagePorts()
+	messages.Index.Unlock()
 
-	entryPassword := NewReturnEntry()
-	entryPassword.Password = true
-	entryPassword.PlaceHolder = "Password"
-	entryPassword.OnChanged = func(s string) {
-		if s == "" {
-			btnConfirm.Text = dismiss
-			btnConfirm.Disable()
-			btnConfirm.Refresh()
-		} else {
-			btnConfirm.Text = dismiss
-			btnConfirm.Enable()
-			btnConfirm.Refresh()
-		}
+	walletHeight := engram.Disk.Get_Height()
+	if indexedHeight > walletHeight {
+		// Wallet has been rescanned below the indexed height, existing TXIDs will be skipped
+		indexedHeight = 0
 	}
 
-	subHeader := canvas.NewText(subText, colors.Account)
-	if password {
-		subText = "Confirm Password"
-		subHeader.TextSize = 22
-	} else {
-		subHeader.TextSize = 18
-		entryPassword.Hide()
-		btnConfirm.Enable()
-		btnConfirm.Refresh()
+	var zeroscid crypto.Hash
+	var entries []rpc.Entry
+	for _, port := range ports {
+		entries = append(entries, engram.Disk.Get_Payments_DestinationPort(zeroscid, port, indexedHeight)...)
 	}
 
-	subHeader.Text = subText
-	subHeader.Alignment = fyne.TextAlignCenter
-	subHeader.TextStyle = fyne.TextStyle{Bold: true}
-	subHeader.Refresh()
+	// Fetch the rings of incoming messages up front, claimed senders are checked against them below
+	rings := make(map[string][][]string)
+	for _, e := range entries {
+		if !e.Incoming {
+			continue
+		}
 
-	linkClose := widget.NewHyperlinkWithStyle("Cancel", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-	linkClose.OnTapped = func() {
-		callback(false)
-		overlay.Top().Hide()
-		overlay.Remove(overlay.Top())
-		overlay.Remove(overlay.Top())
+		if _, ok := rings[e.TXID]; ok {
+			continue
+		}
+
+		ring, err := getTxRing(e.TXID)
+		if err != nil {
+			logger.Debugf("[Message] Getting ring for TXID %s: %s\n", e.TXID, err)
+			continue
+		}
+
+		rings[e.TXID] = ring
 	}
 
-	btnConfirm.OnTapped = func() {
-		btnConfirm.Disable()
-		if password {
-			if engram.Disk.Check_Password(entryPassword.Text) {
-				callback(true)
-				overlay.Top().Hide()
-				overlay.Remove(overlay.Top())
-				overlay.Remove(overlay.Top())
+	nextHeight := walletHeight
+	resolved := make(map[string][2]string)
+	contacts := make(map[string]*MessageContact)
+	updates := make(map[string][]IndexedMessage)
+
+	for _, e := range entries {
+		if !e.Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) || !e.Payload_RPC.HasValue(rpc.RPC_COMMENT, rpc.DataString) {
+			continue
+		}
+
+		sender := e.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string)
+
+		var address, username string
+		if e.Incoming {
+			if sender == "" {
+				continue
+			}
+
+			if r, ok := resolved[sender]; ok {
+				address, username = r[0], r[1]
 			} else {
-				btnConfirm.Text = "Invalid Password..."
-				btnConfirm.Refresh()
+				address, username, err = resolveMessageContact(sender, int64(e.Height))
+				if err != nil && !errors.Is(err, errUsernameNotFound) {
+					// Could not reach the daemon, retry this height on the next update
+					logger.Debugf("[Message] Resolving sender %s: %s\n", sender, err)
+					if e.Height < nextHeight {
+						nextHeight = e.Height
+					}
+					continue
+				}
+
+				resolved[sender] = [2]string{address, username}
 			}
 		} else {
-			callback(true)
-			overlay.Top().Hide()
-			overlay.Remove(overlay.Top())
-			overlay.Remove(overlay.Top())
+			address, _, _ = resolveMessageContact(e.Destination, -1)
+		}
+
+		if address == "" {
+			continue
+		}
+
+		// Check for spoofing, the claimed sender must be a ring member of the transaction
+		verified := true
+		if e.Incoming {
+			ring, ok := rings[e.TXID]
+			if !ok {
+				// Ring could not be fetched, retry this height on the next update
+				if e.Height < nextHeight {
+					nextHeight = e.Height
+				}
+				continue
+			}
+
+			verified = ringMemberExists(ring, address)
+
+			if !verified {
+				logger.Warnf("[Message] Sender %s could not be verified for TXID: %s\n", sender, e.TXID)
+			}
+		}
+
+		contact, ok := contacts[address]
+		if !ok {
+			contact = &MessageContact{Address: address}
+			contacts[address] = contact
+		}
+
+		if username != "" {
+			contact.Username = username
+		}
+
+		if e.Height > contact.Height {
+			contact.Height = e.Height
 		}
+
+		if verified {
+			contact.Verified = true
+		}
+
+		if !e.Incoming {
+			contact.Outgoing = true
+		} else if verified && e.Amount > contact.MaxAmount {
+			contact.MaxAmount = e.Amount
+		}
+
+		var id, part, parts uint64
+		if e.Payload_RPC.HasValue(MESSAGE_ARG_ID, rpc.DataUint64) && e.Payload_RPC.HasValue(MESSAGE_ARG_PART, rpc.DataUint64) && e.Payload_RPC.HasValue(MESSAGE_ARG_PARTS, rpc.DataUint64) {
+			id = e.Payload_RPC.Value(MESSAGE_ARG_ID, rpc.DataUint64).(uint64)
+			part = e.Payload_RPC.Value(MESSAGE_ARG_PART, rpc.DataUint64).(uint64)
+			parts = e.Payload_RPC.Value(MESSAGE_ARG_PARTS, rpc.DataUint64).(uint64)
+			if parts > DEFAULT_MESSAGE_MAX_PARTS || part < 1 || part > parts {
+				id, part, parts = 0, 0, 0
+			}
+		}
+
+		updates[address] = append(updates[address], IndexedMessage{
+			TXID:     e.TXID,
+			Height:   e.Height,
+			Time:     e.Time,
+			Incoming: e.Incoming,
+			Sender:   sender,
+			Comment:  e.Payload_RPC.Value(rpc.RPC_COMMENT, rpc.DataString).(string),
+			Amount:   e.Amount,
+			Verified: verified,
+			ID:       id,
+			Part:     part,
+			Parts:    parts,
+		})
 	}
 
-	entryPassword.OnReturn = btnConfirm.OnTapped
+	err = nil
 
-	span := canvas.NewRectangle(color.Transparent)
-	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+	for address, update := range updates {
+		if err = mergeIndexedMessages(address, update); err != nil {
+			logger.Errorf("[Message] Indexing messages for %s: %s\n", address, err)
+			return
+		}
+	}
 
-	overlay.Add(
-		container.NewStack(
-			&iframe{},
-			canvas.NewRectangle(colors.DarkMatter),
-		),
-	)
+	messages.Index.Lock()
+	defer messages.Index.Unlock()
 
-	overlay.Add(
-		container.NewStack(
-			&iframe{},
-			container.NewCenter(
-				container.NewVBox(
-					span,
-					container.NewCenter(
-						header,
-					),
-					rectSpacer,
-					rectSpacer,
-					subHeader,
-					widget.NewLabel(""),
-					container.NewCenter(
-						container.NewStack(
-							span,
-							entryPassword,
-						),
-					),
-					rectSpacer,
-					rectSpacer,
-					btnConfirm,
-					rectSpacer,
-					rectSpacer,
-					container.NewHBox(
-						layout.NewSpacer(),
-						linkClose,
-						layout.NewSpacer(),
-					),
-					rectSpacer,
-					rectSpacer,
-				),
-			),
-		),
-	)
+	// The account was closed while the update ran
+	if messages.Index.Contacts == nil {
+		return
+	}
 
-	if password {
-		session.Window.Canvas().Focus(entryPassword)
+	for address, update := range contacts {
+		contact, ok := messages.Index.Contacts[address]
+		if !ok {
+			messages.Index.Contacts[address] = update
+			continue
+		}
+
+		if update.Username != "" {
+			contact.Username = update.Username
+		}
+
+		if update.Height > contact.Height {
+			contact.Height = update.Height
+		}
+
+		if update.Verified {
+			contact.Verified = true
+		}
+
+		if update.Outgoing {
+			contact.Outgoing = true
+		}
+
+		if update.MaxAmount > contact.MaxAmount {
+			contact.MaxAmount = update.MaxAmount
+		}
 	}
-}
 
-// Color for the TELA likes ratio and individual rating numbers
-func telaRatingColor(r uint64) color.Color {
-	if r > 65 {
-		return colors.Green
-	} else if r > 32 {
-		return colors.Yellow
-	} else {
-		return colors.Red
+	messages.Index.Height = nextHeight
+
+	if err = messages.Index.store(); err != nil {
+		logger.Errorf("[Message] Storing index: %s\n", err)
 	}
+
+	return
 }
 
-// Color for the TELA average rating number hexagon
-func telaHexagonColor(r float64) fyne.Resource {
-	if r > 6.5 {
-		return resourceTelaHexagonGreen
-	} else if r > 3.2 {
-		return resourceTelaHexagonYellow
-	} else {
-		return resourceTelaHexagonRed
+// Build an archive of the indexed messages for a contact address, or all contacts when address is empty
+func buildMessageArchive(address string) (archive MessageArchive, err error) {
+	if engram.Disk == nil {
+		err = errors.New("error: no active account found")
+		return
+	}
+
+	archive = MessageArchive{
+		Version: MESSAGE_ARCHIVE_VERSION,
+		Address: engram.Disk.GetAddress().BaseAddress().String(),
+		Created: time.Now().UTC(),
+	}
+
+	messages.Index.Lock()
+	messages.Index.load()
+	var contacts []MessageContact
+	for _, c := range messages.Index.Contacts {
+		if address == "" || c.Address == address {
+			contacts = append(contacts, *c)
+		}
+	}
+	messages.Index.Unlock()
+
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].Height > contacts[j].Height
+	})
+
+	for _, c := range contacts {
+		msgs := getIndexedMessages(c.Address)
+		if len(msgs) < 1 {
+			continue
+		}
+
+		archive.Conversations = append(archive.Conversations, MessageArchiveConversation{Contact: c, Messages: msgs})
 	}
+
+	if len(archive.Conversations) < 1 {
+		err = errors.New("no messages to export")
+	}
+
+	return
 }
 
-// Display ratings overview and the details of each rating for the TELA SCID
-func viewTELARatingsOverlay(name, scid string) (err error) {
-	rectWidth90 := canvas.NewRectangle(color.Transparent)
-	rectWidth90.SetMinSize(fyne.NewSize(ui.Width, 10))
+// Key used to encrypt message archives, derived from the account secret so a restored wallet can import them
+func messageArchiveKey() []byte {
+	key := crypto.Keccak256([]byte("engram.messages.archive"), engram.Disk.Get_Keys().Secret.BigInt().Bytes())
+	return key[:]
+}
 
-	rectSpacer := canvas.NewRectangle(color.Transparent)
-	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+// Export the messages for a contact address, or all contacts when address is empty, as an encrypted JSON archive
+func exportMessageArchive(address string) (result []byte, err error) {
+	archive, err := buildMessageArchive(address)
+	if err != nil {
+		return
+	}
 
-	header := canvas.NewText("TELA  RATINGS", colors.Gray)
-	header.TextSize = 16
-	header.Alignment = fyne.TextAlignCenter
-	header.TextStyle = fyne.TextStyle{Bold: true}
+	data, err := json.Marshal(archive)
+	if err != nil {
+		return
+	}
 
-	if len(name) > 30 {
-		name = fmt.Sprintf("%s...", name[0:30])
+	return walletapi.EncryptWithKey(messageArchiveKey(), data)
+}
+
+// Export the messages for a contact address, or all contacts when address is empty, as plaintext Markdown
+func exportMessageMarkdown(address string) (result []byte, err error) {
+	archive, err := buildMessageArchive(address)
+	if err != nil {
+		return
 	}
 
-	nameHdr := canvas.NewText(name, colors.Account)
-	nameHdr.Alignment = fyne.TextAlignCenter
-	nameHdr.TextStyle = fyne.TextStyle{Bold: true}
+	var md strings.Builder
+	md.WriteString("# Engram Message Archive\n\n")
+	md.WriteString(fmt.Sprintf("Account: `%s`  \nExported: %s\n", archive.Address, archive.Created.Format(time.RFC822)))
 
-	labelSCID := canvas.NewText("   SMART  CONTRACT  ID", colors.Gray)
-	labelSCID.TextSize = 14
-	labelSCID.Alignment = fyne.TextAlignLeading
-	labelSCID.TextStyle = fyne.TextStyle{Bold: true}
+	for _, c := range archive.Conversations {
+		if c.Contact.Username != "" {
+			md.WriteString(fmt.Sprintf("\n## %s\n\n`%s`\n", c.Contact.Username, c.Contact.Address))
+		} else {
+			md.WriteString(fmt.Sprintf("\n## %s\n", c.Contact.Address))
+		}
 
-	textSCID := widget.NewRichTextWithText(scid)
-	textSCID.Wrapping = fyne.TextWrapWord
+		for _, m := range assembleMessages(c.Messages) {
+			direction := "Sent"
+			if m.Incoming {
+				direction = "Received"
+				if !m.Verified {
+					direction += " (Unverified Sender)"
+				}
+			}
 
-	textLikes := widget.NewRichTextFromMarkdown("Likes:")
-	textDislikes := widget.NewRichTextFromMarkdown("Dislikes:")
-	textAverage := widget.NewRichTextFromMarkdown("Average:")
+			md.WriteString(fmt.Sprintf("\n**%s**  ·  %s  ·  Height %d  ·  TXID `%s`\n\n", direction, m.Time.Format(time.RFC822), m.Height, m.TXID))
+			for _, line := range strings.Split(m.Comment, "\n") {
+				md.WriteString("> " + line + "\n")
+			}
+		}
+	}
 
-	ratingsBox := container.NewVBox(labelSCID, textSCID)
+	result = []byte(md.String())
 
-	ratings, err := tela.GetRating(scid, session.Daemon, 0)
+	return
+}
+
+// Import an encrypted message archive exported from this account into the message index, returns the number of conversations imported
+func importMessageArchive(data []byte) (count int, err error) {
+	if engram.Disk == nil {
+		err = errors.New("error: no active account found")
+		return
+	}
+
+	decrypted, err := walletapi.DecryptWithKey(messageArchiveKey(), data)
 	if err != nil {
-		removeOverlays()
-		logger.Errorf("[Engram] GetRating: %s\n", err)
-		err = fmt.Errorf("error could not get ratings")
+		err = errors.New("archive was not exported from this account")
 		return
 	}
 
-	removeOverlays()
-	overlay := session.Window.Canvas().Overlays()
+	var archive MessageArchive
+	if err = json.Unmarshal(decrypted, &archive); err != nil {
+		return
+	}
 
-	ratingsBox.Add(container.NewHBox(textLikes, canvas.NewText(fmt.Sprintf("%d", ratings.Likes), colors.Green)))
-	ratingsBox.Add(container.NewHBox(textDislikes, canvas.NewText(fmt.Sprintf("%d", ratings.Dislikes), colors.Red)))
-	ratingsBox.Add(container.NewHBox(textAverage, canvas.NewText(fmt.Sprintf("%0.1f/10", ratings.Average), colors.Account)))
+	if archive.Version > MESSAGE_ARCHIVE_VERSION {
+		err = fmt.Errorf("unsupported archive version %d", archive.Version)
+		return
+	}
 
-	linkRate := widget.NewHyperlinkWithStyle("Rate SCID", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
-	linkRate.OnTapped = func() {
-		rateTELAOverlay(name, scid)
+	if archive.Address != engram.Disk.GetAddress().BaseAddress().String() {
+		err = errors.New("archive was not exported from this account")
+		return
 	}
-	linkRate.Hide()
 
-	// Check if wallet has rated SCID
-	if gnomon.Index != nil {
-		ratingStore, _ := gnomon.GetSCIDValuesByKey(scid, engram.Disk.GetAddress().String())
-		if ratingStore == nil {
-			linkRate.Show()
+	// Senders are verified locally against the transaction rings, the archive is never trusted to mark them
+	addresses := make([]string, len(archive.Conversations))
+	for i, c := range archive.Conversations {
+		var addr *rpc.Address
+		if addr, err = globals.ParseValidateAddress(c.Contact.Address); err != nil {
+			return
 		}
-	}
 
-	linkBack := widget.NewHyperlinkWithStyle("Back to Application", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-	linkBack.OnTapped = func() {
-		overlay.Top().Hide()
-		overlay.Remove(overlay.Top())
-		overlay.Remove(overlay.Top())
+		addresses[i] = addr.BaseAddress().String()
+		archive.Conversations[i].Contact.Verified = false
+		archive.Conversations[i].Contact.MaxAmount = 0
+
+		for j, m := range c.Messages {
+			verified := true
+			if m.Incoming {
+				var ring [][]string
+				if ring, err = getTxRing(m.TXID); err != nil {
+					err = fmt.Errorf("could not verify sender of %s: %s", m.TXID, err)
+					return
+				}
+
+				verified = ringMemberExists(ring, addresses[i])
+			}
+
+			c.Messages[j].Verified = verified
+			if verified {
+				archive.Conversations[i].Contact.Verified = true
+				if m.Incoming && m.Amount > archive.Conversations[i].Contact.MaxAmount {
+					archive.Conversations[i].Contact.MaxAmount = m.Amount
+				}
+			}
+		}
 	}
 
-	labelSeparator := widget.NewRichTextFromMarkdown("")
-	labelSeparator.Wrapping = fyne.TextWrapOff
-	labelSeparator.ParseMarkdown("---")
-	labelSeparator2 := widget.NewRichTextFromMarkdown("")
-	labelSeparator2.Wrapping = fyne.TextWrapOff
-	labelSeparator2.ParseMarkdown("---")
+	messages.Index.Lock()
+	defer messages.Index.Unlock()
 
-	span := canvas.NewRectangle(color.Transparent)
-	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+	messages.Index.load()
 
-	userRatingsBox := container.NewVBox()
+	for i, c := range archive.Conversations {
+		address := addresses[i]
+		contact, ok := messages.Index.Contacts[address]
+		if !ok {
+			contact = &MessageContact{Address: address, Settings: c.Contact.Settings}
+			messages.Index.Contacts[address] = contact
+		}
 
-	for _, r := range ratings.Ratings {
-		ratingString, err := tela.Ratings.ParseString(r.Rating)
-		if err != nil {
-			ratingString = fmt.Sprintf("%d", r.Rating)
+		if contact.Username == "" {
+			contact.Username = c.Contact.Username
 		}
 
-		labelSeparator := widget.NewRichTextFromMarkdown("")
-		labelSeparator.Wrapping = fyne.TextWrapOff
-		labelSeparator.ParseMarkdown("---")
+		if c.Contact.Height > contact.Height {
+			contact.Height = c.Contact.Height
+		}
 
-		labelAddress := widget.NewRichTextFromMarkdown(r.Address)
-		labelAddress.Wrapping = fyne.TextWrapWord
+		if c.Contact.MaxAmount > contact.MaxAmount {
+			contact.MaxAmount = c.Contact.MaxAmount
+		}
 
-		userRatingsBox.Add(
-			container.NewVBox(
-				labelAddress,
-				container.NewHBox(widget.NewRichTextFromMarkdown("Height:"), canvas.NewText(fmt.Sprintf("%d", r.Height), colors.Account)),
-				container.NewHBox(widget.NewRichTextFromMarkdown("Rating:"), canvas.NewText(fmt.Sprintf("%d", r.Rating), telaRatingColor(r.Rating))),
-				widget.NewRichTextFromMarkdown(ratingString),
-				labelSeparator,
-			),
-		)
+		contact.Verified = contact.Verified || c.Contact.Verified
+		contact.Outgoing = contact.Outgoing || c.Contact.Outgoing
+
+		if err = mergeIndexedMessages(address, c.Messages); err != nil {
+			return
+		}
+
+		count++
 	}
 
-	userRatingsBoxScroll := container.NewVScroll(
-		container.NewHBox(
-			layout.NewSpacer(),
-			container.NewVBox(
-				ratingsBox,
-				rectWidth90,
-				container.NewHBox(
-					linkRate,
-					layout.NewSpacer(),
-				),
-				rectSpacer,
-				rectSpacer,
-				labelSeparator2,
-				rectSpacer,
-				rectSpacer,
-				userRatingsBox,
-			),
-			layout.NewSpacer(),
-		),
-	)
-	userRatingsBoxScroll.SetMinSize(fyne.NewSize(ui.Width*0.80, ui.Height*0.50))
-
-	overlayCont := container.NewVBox(
-		span,
-		container.NewCenter(
-			header,
-		),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		container.NewCenter(
-			nameHdr,
-		),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		labelSeparator,
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		container.NewHBox(
-			layout.NewSpacer(),
-		),
-		userRatingsBoxScroll,
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-	)
-
-	menuLabel := canvas.NewText("  M O R E   O P T I O N S  ", colors.Gray)
-	menuLabel.TextSize = 11
-	menuLabel.Alignment = fyne.TextAlignCenter
-	menuLabel.TextStyle = fyne.TextStyle{Bold: true}
+	err = messages.Index.store()
 
-	sep := canvas.NewRectangle(colors.Gray)
-	sep.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+	return
+}
 
-	line1 := container.NewVBox(
-		layout.NewSpacer(),
-		sep,
-		layout.NewSpacer(),
-	)
+// Get a list of indexed messages from a contact (address or username)
+func getMessagesFromUser(s string, h uint64) (result []IndexedMessage) {
+	if s == "" || engram.Disk == nil {
+		return
+	}
 
-	line2 := container.NewVBox(
-		layout.NewSpacer(),
-		sep,
-		layout.NewSpacer(),
-	)
+	address := ""
+	if addr, err := globals.ParseValidateAddress(s); err == nil {
+		address = addr.BaseAddress().String()
+	} else {
+		messages.Index.Lock()
+		messages.Index.load()
+		for _, c := range messages.Index.Contacts {
+			if c.Username == s {
+				address = c.Address
+				break
+			}
+		}
+		messages.Index.Unlock()
 
-	bottom := container.NewStack(
-		container.NewVBox(
-			rectSpacer,
-			rectSpacer,
-			container.NewStack(
-				container.NewHBox(
-					layout.NewSpacer(),
-					line1,
-					layout.NewSpacer(),
-					menuLabel,
-					layout.NewSpacer(),
-					line2,
-					layout.NewSpacer(),
-				),
-			),
-			rectSpacer,
-			rectSpacer,
-			container.NewCenter(
-				layout.NewSpacer(),
-				linkBack,
-				layout.NewSpacer(),
-			),
-			rectSpacer,
-			rectSpacer,
-			rectSpacer,
-			rectSpacer,
-		),
-	)
+		if address == "" {
+			address, _, _ = resolveMessageContact(s, -1)
+			if address == "" {
+				return
+			}
+		}
+	}
 
-	overlay.Add(
-		container.NewStack(
-			&iframe{},
-			canvas.NewRectangle(colors.DarkMatter),
-		),
-	)
+	filters := getMessageFilters()
+	if filters.isBlocked(address) {
+		return
+	}
 
-	overlay.Add(
-		container.NewStack(
-			&iframe{},
-			container.NewBorder(
-				nil,
-				bottom,
-				nil,
-				nil,
-				overlayCont,
-			),
-		),
-	)
+	for _, m := range assembleMessages(getIndexedMessages(address)) {
+		if m.Height >= h && (m.Verified || !messages.HideUnverified) && filters.allowMessage(m) {
+			result = append(result, m)
+		}
+	}
 
 	return
 }
 
-// TELA smart contract rating overlay with password confirmation
-func rateTELAOverlay(name, scid string) {
-	overlay := session.Window.Canvas().Overlays()
+// Get the ring members of a transaction from the daemon
+func getTxRing(txid string) (ring [][]string, err error) {
+	result, err := getTxData(txid)
+	if err != nil {
+		return
+	}
 
-	rectSpacer := canvas.NewRectangle(color.Transparent)
-	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+	if len(result.Txs) < 1 {
+		err = fmt.Errorf("could not get transaction %s", txid)
+		return
+	}
 
-	selectSpacer := canvas.NewRectangle(color.Transparent)
-	selectSpacer.SetMinSize(fyne.NewSize(80, 5))
+	ring = result.Txs[0].Ring
 
-	header := canvas.NewText("RATE  TELA  INDEX", colors.Gray)
-	header.TextSize = 16
-	header.Alignment = fyne.TextAlignCenter
-	header.TextStyle = fyne.TextStyle{Bold: true}
+	return
+}
 
-	if len(name) > 30 {
-		name = fmt.Sprintf("%s...", name[0:30])
+// Check if the daemon positively reports a transaction as unknown, it is neither in the pool nor in a block
+func txUnknown(txid string) (unknown bool, err error) {
+	result, err := getTxData(txid)
+	if err != nil {
+		return
 	}
 
-	nameHdr := canvas.NewText(name, colors.Account)
-	nameHdr.Alignment = fyne.TextAlignCenter
-	nameHdr.TextStyle = fyne.TextStyle{Bold: true}
+	if result.Status != "OK" || len(result.Txs) < 1 || len(result.Txs_as_hex) < 1 {
+		err = fmt.Errorf("could not get transaction %s", txid)
+		return
+	}
 
-	btnConfirm := widget.NewButton("Rate", nil)
-	btnConfirm.Disable()
+	unknown = result.Txs_as_hex[0] == "" && result.Txs[0].ValidBlock == "" && !result.Txs[0].In_pool
 
-	var telaCategories, negativeDetails, positiveDetails []string
-	for i := uint64(0); i < 10; i++ {
-		telaCategories = append(telaCategories, tela.Ratings.Category(i))
+	return
+}
+
+// Check if an address is a member of a transaction ring
+func ringMemberExists(ring [][]string, address string) bool {
+	for _, members := range ring {
+		for _, member := range members {
+			if member == address {
+				return true
+			}
+		}
 	}
-	categorySelect := widget.NewSelect(telaCategories, nil)
 
-	scidLabel := canvas.NewText("   SMART  CONTRACT  ID", colors.Gray)
-	scidLabel.TextSize = 14
-	scidLabel.Alignment = fyne.TextAlignCenter
-	scidLabel.TextStyle = fyne.TextStyle{Bold: true}
+	return false
+}
 
-	scidText := widget.NewRichTextFromMarkdown(scid)
-	scidText.Wrapping = fyne.TextWrapWord
+// Get a list of all indexed message contacts sorted by most recent activity
+func getMessages(h uint64) (result []string) {
+	if engram.Disk == nil {
+		return
+	}
 
-	errorText := canvas.NewText(" ", colors.Green)
-	errorText.TextSize = 12
-	errorText.Alignment = fyne.TextAlignCenter
+	filters := getMessageFilters()
 
-	for i := uint64(0); i < 10; i++ {
-		negativeDetails = append(negativeDetails, tela.Ratings.Detail(i, false))
-		positiveDetails = append(positiveDetails, tela.Ratings.Detail(i, true))
+	messages.Index.Lock()
+	messages.Index.load()
+
+	var contacts []MessageContact
+	for _, c := range messages.Index.Contacts {
+		if c.Height >= h && (c.Verified || !messages.HideUnverified) && filters.allowContact(*c, messages.ShowQuarantine) {
+			contacts = append(contacts, *c)
+		}
 	}
-	negativeSelect := widget.NewSelect(negativeDetails, nil)
-	positiveSelect := widget.NewSelect(positiveDetails, nil)
 
-	categoryHeader := canvas.NewText("Category", colors.Account)
-	categoryHeader.Alignment = fyne.TextAlignLeading
-	categoryHeader.TextStyle = fyne.TextStyle{Bold: true}
-	categoryHeader.Refresh()
+	messages.Index.Unlock()
 
-	detailHeader := canvas.NewText("Detail", colors.Account)
-	detailHeader.Alignment = fyne.TextAlignLeading
-	detailHeader.TextStyle = fyne.TextStyle{Bold: true}
-	detailHeader.Refresh()
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].Height > contacts[j].Height
+	})
 
-	ratingHeader := canvas.NewText("Rating", colors.Account)
-	ratingHeader.Alignment = fyne.TextAlignLeading
-	ratingHeader.TextStyle = fyne.TextStyle{Bold: true}
-	ratingHeader.Refresh()
+	for _, c := range contacts {
+		result = append(result, c.Address+"~~~"+c.Username)
+	}
 
-	ratingText := widget.NewRichTextFromMarkdown("")
-	ratingText.Wrapping = fyne.TextWrapWord
+	return
+}
 
-	linkClose := widget.NewHyperlinkWithStyle("Cancel", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-	linkClose.OnTapped = func() {
-		overlay.Top().Hide()
-		overlay.Remove(overlay.Top())
-		overlay.Remove(overlay.Top())
+// Set the hide unverified messages setting saved to a wallet's datashard
+func setHideUnverified(b bool) (err error) {
+	messages.HideUnverified = b
+	err = StoreEncryptedValue("settings", []byte("messages.hide_unverified"), []byte(strconv.FormatBool(b)))
+	return
+}
+
+// Get the hide unverified messages setting saved to a wallet's datashard
+func getHideUnverified() (err error) {
+	v, err := GetEncryptedValue("settings", []byte("messages.hide_unverified"))
+	if err != nil {
+		messages.HideUnverified = false
+		return
 	}
+	messages.HideUnverified = string(v) == "true"
+	return
+}
 
-	btnConfirm.OnTapped = func() {
-		errorText.Text = ""
-		errorText.Refresh()
-		btnConfirm.Disable()
-		if gnomon.Index != nil {
-			var ratingStore []string
-			switch gnomon.Index.DBType {
-			case "gravdb":
-				ratingStore, _ = gnomon.Index.GravDBBackend.GetSCIDValuesByKey(scid, engram.Disk.GetAddress().String(), gnomon.Index.LastIndexedHeight, false)
-			case "boltdb":
-				ratingStore, _ = gnomon.Index.BBSBackend.GetSCIDValuesByKey(scid, engram.Disk.GetAddress().String(), gnomon.Index.LastIndexedHeight, false)
-			}
-			if ratingStore != nil {
-				errorText.Text = "already rated this contract"
-				errorText.Color = colors.Red
-				errorText.Refresh()
-				return
-			}
-		}
+// Get the message block list and spam filters saved to a wallet's datashard
+func getMessageFilters() (filters MessageFilters) {
+	stored, err := GetEncryptedValue("Messages", []byte("filters"))
+	if err != nil {
+		return
+	}
 
-		category := categorySelect.SelectedIndex()
-		if category < 0 {
-			errorText.Text = "select a category"
-			errorText.Color = colors.Red
-			errorText.Refresh()
-			return
-		}
+	if err = json.Unmarshal(stored, &filters); err != nil {
+		logger.Errorf("[Message] Loading filters: %s\n", err)
+		filters = MessageFilters{}
+	}
 
-		var detail int
-		if category > 4 {
-			detail = positiveSelect.SelectedIndex()
-		} else {
-			detail = negativeSelect.SelectedIndex()
-		}
+	return
+}
 
-		if detail < 0 {
-			errorText.Text = "select a detail"
-			errorText.Color = colors.Red
-			errorText.Refresh()
+// Save the message block list and spam filters to a wallet's datashard
+func setMessageFilters(filters MessageFilters) (err error) {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return
+	}
+
+	return StoreEncryptedValue("Messages", []byte("filters"), data)
+}
+
+// Add or remove a contact address from the message block list
+func setMessageBlocked(address string, blocked bool) (err error) {
+	filters := getMessageFilters()
+
+	var list []string
+	for _, b := range filters.Blocked {
+		if b != address {
+			list = append(list, b)
+		}
+	}
+
+	if blocked {
+		list = append(list, address)
+	}
+
+	filters.Blocked = list
+
+	return setMessageFilters(filters)
+}
+
+// Check if a contact address is on the message block list
+func (f MessageFilters) isBlocked(address string) bool {
+	for _, b := range f.Blocked {
+		if b == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check if a contact we have never sent a message to is quarantined by the filters
+func (f MessageFilters) isQuarantined(c MessageContact) bool {
+	return f.QuarantineUnknown && !c.Outgoing
+}
+
+// Check if a contact passes the message filters, quarantined contacts are allowed only when shown
+func (f MessageFilters) allowContact(c MessageContact, showQuarantine bool) bool {
+	if f.isBlocked(c.Address) {
+		return false
+	}
+
+	// Contacts we have written to are always allowed, otherwise require a message with the minimum amount
+	if !c.Outgoing && c.MaxAmount < f.MinAmount {
+		return false
+	}
+
+	return !f.isQuarantined(c) || showQuarantine
+}
+
+// Check if a message passes the minimum amount filter, outgoing messages are always allowed
+func (f MessageFilters) allowMessage(m IndexedMessage) bool {
+	return !m.Incoming || m.Amount >= f.MinAmount
+}
+
+// Check if an incoming message entry should send a notification, unverified, muted and quarantined senders never notify
+func allowMessageNotification(e rpc.Entry) bool {
+	filters := getMessageFilters()
+	if e.Amount < filters.MinAmount {
+		return false
+	}
+
+	sender := e.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string)
+
+	address, _, err := resolveMessageContact(sender, -1)
+	if err != nil {
+		return false
+	}
+
+	// The reply-back address can be spoofed, the sender must be a ring member of the transaction
+	ring, err := getTxRing(e.TXID)
+	if err != nil || !ringMemberExists(ring, address) {
+		logger.Warnf("[Message] Sender %s could not be verified for TXID: %s\n", sender, e.TXID)
+		return false
+	}
+
+	known := MessageContact{Address: address}
+	messages.Index.Lock()
+	messages.Index.load()
+	if contact, ok := messages.Index.Contacts[address]; ok {
+		known = *contact
+	}
+	messages.Index.Unlock()
+
+	return !filters.isBlocked(known.Address) && !filters.isQuarantined(known)
+}
+
+// Get the default message settings used when no global settings have been saved
+func defaultMessageSettings() (ms MessageSettings) {
+	ms = MessageSettings{
+		Amount:   DEFAULT_MESSAGE_AMOUNT,
+		Ringsize: 16,
+		Expiry:   DEFAULT_MESSAGE_EXPIRY,
+		Port:     DEFAULT_MESSAGE_PORT,
+	}
+
+	if engram.Disk != nil {
+		ms.Ringsize = uint64(engram.Disk.GetRingSize())
+	}
+
+	return
+}
+
+// Check that the message settings can be used to build a transaction
+func (ms MessageSettings) validate() (err error) {
+	if ms.Ringsize < 2 || ms.Ringsize > 128 || ms.Ringsize&(ms.Ringsize-1) != 0 {
+		err = errors.New("ring size must be a power of 2 between 2 and 128")
+		return
+	}
+
+	if ms.Port == 0 {
+		err = errors.New("destination port cannot be 0")
+		return
+	}
+
+	return
+}
+
+// Get the global message settings saved to a wallet's datashard
+func getGlobalMessageSettings() (ms MessageSettings) {
+	ms = defaultMessageSettings()
+
+	stored, err := GetEncryptedValue("Messages", []byte("settings"))
+	if err != nil {
+		return
+	}
+
+	var saved MessageSettings
+	if err = json.Unmarshal(stored, &saved); err != nil {
+		logger.Errorf("[Message] Loading settings: %s\n", err)
+		return
+	}
+
+	if saved.validate() == nil {
+		ms = saved
+	}
+
+	return
+}
+
+// Get the message settings for a contact address, falling back to the global settings
+// when the conversation has no settings of its own
+func getMessageSettings(address string) (ms MessageSettings) {
+	if address != "" {
+		messages.Index.Lock()
+		messages.Index.load()
+		contact, ok := messages.Index.Contacts[address]
+		if ok && contact.Settings != nil && contact.Settings.validate() == nil {
+			ms = *contact.Settings
+			messages.Index.Unlock()
 			return
 		}
+		messages.Index.Unlock()
+	}
 
-		rating := (category * 10) + detail
+	return getGlobalMessageSettings()
+}
 
-		verificationOverlay(
-			true,
-			"",
-			"",
-			"",
-			func(b bool) {
-				if !b {
-					btnConfirm.Enable()
-					return
-				}
+// Check if a contact address has its own message settings
+func hasMessageSettings(address string) bool {
+	messages.Index.Lock()
+	defer messages.Index.Unlock()
 
-				overlay.Top().Hide()
-				overlay.Remove(overlay.Top())
-				overlay.Remove(overlay.Top())
+	messages.Index.load()
+	contact, ok := messages.Index.Contacts[address]
 
-				txid, err := tela.Rate(engram.Disk, scid, uint64(rating))
-				if err != nil {
-					logger.Errorf("[Engram] Rate TX: %s\n", err)
-					return
-				}
+	return ok && contact.Settings != nil
+}
 
-				logger.Printf("[Engram] Rate TXID: %s\n", txid)
-			},
-		)
+// Save the message settings for a contact address, or the global settings when address is empty.
+// Passing nil settings for a contact address removes its settings so the global settings are used
+func setMessageSettings(address string, ms *MessageSettings) (err error) {
+	if engram.Disk == nil {
+		err = errors.New("error: no active account found")
+		return
 	}
 
-	span := canvas.NewRectangle(color.Transparent)
-	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+	if ms != nil {
+		if err = ms.validate(); err != nil {
+			return
+		}
+	}
 
-	overlayCont := container.NewVBox(
-		span,
-		container.NewCenter(
-			header,
-		),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		container.NewCenter(
-			nameHdr,
-		),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		scidLabel,
-		scidText,
-		widget.NewLabel(""),
-		container.NewCenter(
-			container.NewStack(
-				span,
-				container.NewBorder(
-					nil,
-					nil,
-					container.NewStack(
-						selectSpacer,
-						categoryHeader,
-					),
-					nil,
-					categorySelect,
-				),
-			),
-		),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		container.NewCenter(
-			container.NewStack(
-				span,
-				container.NewBorder(
-					nil,
-					nil,
-					container.NewStack(
-						selectSpacer,
-						detailHeader,
-					),
-					nil,
-					positiveSelect,
-				),
-			),
-		),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		container.NewCenter(
-			container.NewStack(
-				span,
-				container.NewBorder(
-					nil,
-					nil,
-					container.NewStack(
-						selectSpacer,
-						ratingHeader,
-					),
-					nil,
-					ratingText,
-				),
-			),
-		),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		errorText,
-		rectSpacer,
-		rectSpacer,
-		btnConfirm,
-		rectSpacer,
-		rectSpacer,
-		container.NewHBox(
-			layout.NewSpacer(),
-			linkClose,
-			layout.NewSpacer(),
-		),
-		rectSpacer,
-		rectSpacer,
-	)
+	messages.Index.Lock()
+	defer messages.Index.Unlock()
+
+	messages.Index.load()
+	ports := messagePorts()
+
+	if address == "" {
+		if ms == nil {
+			err = DeleteKey("Messages", []byte("settings"))
+		} else {
+			var data []byte
+			if data, err = json.Marshal(ms); err != nil {
+				return
+			}
+			err = StoreEncryptedValue("Messages", []byte("settings"), data)
+		}
+		if err != nil {
+			return
+		}
+	} else {
+		contact, ok := messages.Index.Contacts[address]
+		if !ok {
+			if ms == nil {
+				return
+			}
+			contact = &MessageContact{Address: address}
+			messages.Index.Contacts[address] = contact
+		}
+
+		contact.Settings = ms
+	}
+
+	// Rescan the wallet entries if a port that has not been indexed is now in use
+	if ms != nil {
+		indexed := false
+		for _, port := range ports {
+			if port == ms.Port {
+				indexed = true
+				break
+			}
+		}
+
+		if !indexed {
+			messages.Index.Height = 0
+		}
+	}
+
+	err = messages.Index.store()
+
+	return
+}
+
+// Get the destination ports used for messages by the global and conversation settings, the caller must hold the index lock
+func messagePorts() (ports []uint64) {
+	seen := make(map[uint64]bool)
+	add := func(port uint64) {
+		if port != 0 && !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+
+	add(DEFAULT_MESSAGE_PORT)
+
+	add(getGlobalMessageSettings().Port)
+	for _, c := range messages.Index.Contacts {
+		if c.Settings != nil {
+			add(c.Settings.Port)
+		}
+	}
+
+	return
+}
+
+// Returns a list of registered usernames from Gnomon
+func queryUsernames(address string) (result []string, err error) {
+	if cached, ok := nameService.lookupNames(address); ok {
+		result = cached
+		return
+	}
+
+	if gnomon.Index != nil && engram.Disk != nil {
+		result, _ = gnomon.GetSCIDKeysByValue(NAME_SERVICE_SCID, address)
+		if len(result) <= 0 {
+			result, _, err = gnomon.Index.GetSCIDKeysByValue(nil, NAME_SERVICE_SCID, address, engram.Disk.Get_Daemon_TopoHeight())
+			if err != nil {
+				logger.Errorf("[Gnomon] Querying usernames failed: %s\n", err)
+				return
+			}
+		}
+
+		sort.Strings(result)
+		nameService.storeNames(address, result)
+	}
+
+	return
+}
+
+// Get the address of a username from the Gnomon index of the name service, returns empty if it is not indexed
+func gnomonNameToAddress(name string) (address string) {
+	if gnomon.Index == nil {
+		return
+	}
+
+	values, _ := gnomon.GetSCIDValuesByKey(NAME_SERVICE_SCID, name)
+	if len(values) > 0 {
+		address = values[0]
+	}
+
+	return
+}
+
+// Get the usernames registered to an address from the Gnomon index of the name service
+func gnomonAddressToNames(address string) (names []string) {
+	if gnomon.Index == nil {
+		return
+	}
+
+	names, _ = gnomon.GetSCIDKeysByValue(NAME_SERVICE_SCID, address)
+	sort.Strings(names)
+
+	return
+}
+
+// Get the registered username of an address without blocking. When the address isn't cached, Gnomon is
+// searched in the background and refresh is called if a username is found
+func addressUsername(address string, refresh func()) (username string) {
+	if engram.Disk == nil {
+		return
+	}
+
+	addr, err := globals.ParseValidateAddress(address)
+	if err != nil {
+		return
+	}
+
+	base := addr.BaseAddress().String()
+	if base == engram.Disk.GetAddress().BaseAddress().String() && session.Username != "" {
+		return session.Username
+	}
+
+	if names, ok := nameService.lookupNames(base); ok {
+		if len(names) > 0 {
+			username = names[0]
+		}
+		return
+	}
+
+	nameService.Lock()
+	nameService.init()
+	_, missing := nameService.Missing[base]
+	pending := nameService.Pending[base]
+	if !missing && !pending {
+		nameService.Pending[base] = true
+	}
+	nameService.Unlock()
+
+	if missing || pending {
+		return
+	}
+
+	go func() {
+		names := gnomonAddressToNames(base)
+
+		nameService.Lock()
+		nameService.init()
+		delete(nameService.Pending, base)
+		if len(names) < 1 && engram.Disk != nil {
+			nameService.Missing[base] = engram.Disk.Get_Daemon_TopoHeight()
+		}
+		nameService.Unlock()
+
+		if len(names) > 0 {
+			nameService.storeNames(base, names)
+			if refresh != nil {
+				fyne.Do(refresh)
+			}
+		}
+	}()
+
+	return
+}
+
+// Show an address in a rich text widget with its registered username above it when one is known
+func setAddressMarkdown(rt *widget.RichText, address string) {
+	username := addressUsername(address, func() {
+		setAddressMarkdown(rt, address)
+	})
+
+	if username == "" {
+		rt.ParseMarkdown(address)
+	} else {
+		rt.ParseMarkdown("**" + username + "**\n\n" + address)
+	}
+}
+
+// Get the last height the name service was interacted with from Gnomon, returns -1 if it is not indexed
+func nameServiceMarker() (marker int64) {
+	marker = -1
+	if gnomon.Index == nil {
+		return
+	}
+
+	for _, h := range gnomon.GetSCIDInteractionHeight(NAME_SERVICE_SCID) {
+		if h > marker {
+			marker = h
+		}
+	}
+
+	return
+}
+
+// Make the cache maps if needed, the caller must hold the cache lock
+func (n *NameService) init() {
+	if n.Names == nil {
+		n.Names = make(map[string]NameServiceEntry)
+		n.Addresses = make(map[string]NameServiceEntry)
+		n.History = make(map[string]string)
+		n.Missing = make(map[string]int64)
+		n.Pending = make(map[string]bool)
+	}
+}
+
+// Check if a latest cache entry is still current, the caller must hold the cache lock. Without Gnomon tracking
+// the name service, entries older than DEFAULT_USERNAME_CACHE_BLOCKS are stale even before update removes them
+func (n *NameService) current(entry NameServiceEntry) bool {
+	if n.Marker >= 0 || engram.Disk == nil {
+		return true
+	}
+
+	return engram.Disk.Get_Daemon_TopoHeight()-entry.Topoheight <= DEFAULT_USERNAME_CACHE_BLOCKS
+}
+
+// Get a cached username address at height h, or the latest height when h < 0. An empty address means the username does not exist
+func (n *NameService) lookup(name string, h int64) (address string, ok bool) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.init()
+	if h >= 0 {
+		address, ok = n.History[fmt.Sprintf("%s@%d", name, h)]
+		return
+	}
+
+	entry, ok := n.Names[name]
+	if ok && !n.current(entry) {
+		delete(n.Names, name)
+		ok = false
+		return
+	}
+	address = entry.Address
+
+	return
+}
+
+// Cache a username address at height h, or the latest height when h < 0
+func (n *NameService) store(name string, h int64, address string) {
+	if engram.Disk == nil {
+		return
+	}
+
+	n.Lock()
+	defer n.Unlock()
+
+	n.init()
+	if h >= 0 {
+		// Resolving at a height never changes
+		n.History[fmt.Sprintf("%s@%d", name, h)] = address
+		return
+	}
+
+	n.Names[name] = NameServiceEntry{Address: address, Topoheight: engram.Disk.Get_Daemon_TopoHeight()}
+}
+
+// Get the cached usernames registered to an address
+func (n *NameService) lookupNames(address string) (names []string, ok bool) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.init()
+	entry, ok := n.Addresses[address]
+	if ok && !n.current(entry) {
+		delete(n.Addresses, address)
+		ok = false
+		return
+	}
+	names = entry.Names
+
+	return
+}
+
+// Cache the usernames registered to an address
+func (n *NameService) storeNames(address string, names []string) {
+	if engram.Disk == nil {
+		return
+	}
+
+	n.Lock()
+	defer n.Unlock()
+
+	n.init()
+	n.Addresses[address] = NameServiceEntry{Names: names, Topoheight: engram.Disk.Get_Daemon_TopoHeight()}
+}
+
+// Remove the cached usernames of an address so they are looked up again
+func (n *NameService) forget(address string) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.init()
+	delete(n.Addresses, address)
+	delete(n.Missing, address)
+}
+
+// Invalidate the latest cached usernames when the name service has changed. Without Gnomon the
+// change can't be seen, so entries older than DEFAULT_USERNAME_CACHE_BLOCKS are removed instead
+func (n *NameService) update() {
+	if engram.Disk == nil {
+		return
+	}
+
+	marker := nameServiceMarker()
+	topoheight := engram.Disk.Get_Daemon_TopoHeight()
+
+	n.Lock()
+	defer n.Unlock()
+
+	n.init()
+	if marker >= 0 {
+		if marker != n.Marker {
+			n.Names = make(map[string]NameServiceEntry)
+			n.Addresses = make(map[string]NameServiceEntry)
+			n.Missing = make(map[string]int64)
+			n.Marker = marker
+		}
+		return
+	}
+
+	n.Marker = -1
+
+	for k, t := range n.Missing {
+		if topoheight-t > DEFAULT_USERNAME_CACHE_BLOCKS {
+			delete(n.Missing, k)
+		}
+	}
+
+	for k, e := range n.Names {
+		if topoheight-e.Topoheight > DEFAULT_USERNAME_CACHE_BLOCKS {
+			delete(n.Names, k)
+		}
+	}
+
+	for k, e := range n.Addresses {
+		if topoheight-e.Topoheight > DEFAULT_USERNAME_CACHE_BLOCKS {
+			delete(n.Addresses, k)
+		}
+	}
+}
+
+// Clear the username cache when an account is closed
+func (n *NameService) reset() {
+	n.Lock()
+	n.Marker = -1
+	n.Names = nil
+	n.Addresses = nil
+	n.History = nil
+	n.Missing = nil
+	n.Pending = nil
+	n.Unlock()
+}
+
+// Get the local list of registered usernames saved from previous Gnomon scans
+func getUsernames() (result []string, err error) {
+	usernames, err := GetEncryptedValue("Usernames", []byte("usernames"))
+	if err != nil {
+		return
+	}
+
+	result = strings.Split(string(usernames), ",")
+	return
+}
+
+// Add a registered username to the local list of usernames
+func addUsername(s string) (err error) {
+	usernames, _ := getUsernames()
+
+	var result []string
+	for _, u := range usernames {
+		if u == s {
+			return
+		}
+
+		if u != "" {
+			result = append(result, u)
+		}
+	}
+
+	result = append(result, s)
+	err = StoreEncryptedValue("Usernames", []byte("usernames"), []byte(strings.Join(result, ",")))
+	return
+}
+
+// Set the Primary Username saved to a wallet's datashard
+func setPrimaryUsername(s string) (err error) {
+	err = StoreEncryptedValue("settings", []byte("username"), []byte(s))
+	return
+}
+
+// Get the Primary Username saved to a wallet's datashard
+func getPrimaryUsername() (err error) {
+	u, err := GetEncryptedValue("settings", []byte("username"))
+	if err != nil {
+		session.Username = ""
+		return
+	}
+	session.Username = string(u)
+	return
+}
+
+// Start the Gnomon indexer
+func startGnomon() {
+	if walletapi.Connected {
+		if gnomon.Index == nil && gnomon.Active == 1 {
+			path := gnomonPath()
+			gnomon.Path = path
+			gnomon.Progress.reset()
+			profile := getGnomonProfile(session.Network)
+
+			gnomon.BBolt, _ = storage.NewBBoltDB(path, "gnomon")
+			gnomon.Graviton, _ = storage.NewGravDB(path, "25ms")
+
+			var height int64
+			var err error
+			if profile.Backend == "boltdb" {
+				height, err = gnomon.BBolt.GetLastIndexHeight()
+			} else {
+				height, err = gnomon.Graviton.GetLastIndexHeight()
+			}
+			if err != nil {
+				height = 0
+			}
+
+			// Fastsync Config
+			config := &structures.FastSyncConfig{
+				Enabled:           profile.FastSync,
+				SkipFSRecheck:     profile.SkipFSRecheck,
+				ForceFastSync:     profile.ForceFastSync,
+				ForceFastSyncDiff: profile.FastSyncDiff,
+				NoCode:            profile.NoCode,
+			}
+
+			endpoint := getGnomonEndpoint()
+			gnomon.Endpoint = hasGnomonEndpoint()
+			if gnomon.Endpoint {
+				logger.Printf("[Gnomon] Using dedicated endpoint: %s\n", endpoint)
+			}
+
+			gnomon.Index = indexer.NewIndexer(gnomon.Graviton, gnomon.BBolt, profile.Backend, profile.SearchFilters, height, endpoint, "daemon", false, false, config, profile.Exclusions)
+			indexer.InitLog(globals.Arguments, os.Stdout)
+
+			// We can allow parallel processing of x blocks at a time
+			go gnomon.Index.StartDaemonMode(profile.Parallel)
+
+			if len(profile.Inclusions) > 0 {
+				go addGnomonInclusions(gnomon.Index, profile)
+			}
+
+			logger.Printf("[Gnomon] Scan Status: [%d / %d]\n", height, gnomon.Index.LastIndexedHeight)
+		}
+	}
+}
+
+// Add the SCIDs included by a Gnomon indexing profile once the indexer has connected
+func addGnomonInclusions(index *indexer.Indexer, profile GnomonProfile) {
+	for index.ChainHeight == 0 || index.Status == "initializing" {
+		if index.Closing || gnomon.Index != index {
+			return
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	add := make(map[string]*structures.FastSyncImport)
+	for _, scid := range profile.Inclusions {
+		add[scid] = &structures.FastSyncImport{}
+	}
+
+	if err := index.AddSCIDToIndex(add, false, profile.NoCode); err != nil {
+		logger.Errorf("[Gnomon] Error adding included SCIDs: %s\n", err)
+		return
+	}
+
+	logger.Printf("[Gnomon] Added %d included SCIDs to index\n", len(add))
+}
+
+// Get the Gnomon directory of the current network
+func gnomonPath() (path string) {
+	path = filepath.Join(AppPath(), "datashards", "gnomon")
+	switch session.Network {
+	case NETWORK_TESTNET:
+		path = filepath.Join(AppPath(), "datashards", "gnomon_testnet")
+	case NETWORK_SIMULATOR:
+		path = filepath.Join(AppPath(), "datashards", "gnomon_simulator")
+	}
+
+	return
+}
+
+// Get the size in bytes of all files within a directory
+func dirSize(path string) (size int64) {
+	filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// Format a size in bytes for display (Ex: 1.5 MB)
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// Record the indexed height of Gnomon, samples from the last minute are kept to measure the sync rate
+func (p *GnomonProgress) sample(height int64) {
+	p.Lock()
+	defer p.Unlock()
+
+	now := time.Now()
+	p.Samples = append(p.Samples, GnomonSample{Time: now, Height: height})
+
+	i := 0
+	for i < len(p.Samples)-2 && now.Sub(p.Samples[i].Time) > time.Minute {
+		i++
+	}
+
+	p.Samples = p.Samples[i:]
+}
+
+// Get the blocks per second Gnomon has indexed over the sampled period
+func (p *GnomonProgress) rate() float64 {
+	p.Lock()
+	defer p.Unlock()
+
+	if len(p.Samples) < 2 {
+		return 0
+	}
+
+	first := p.Samples[0]
+	last := p.Samples[len(p.Samples)-1]
+	elapsed := last.Time.Sub(first.Time).Seconds()
+	if elapsed <= 0 || last.Height <= first.Height {
+		return 0
+	}
+
+	return float64(last.Height-first.Height) / elapsed
+}
+
+// Clear the progress samples when Gnomon is started
+func (p *GnomonProgress) reset() {
+	p.Lock()
+	p.Samples = nil
+	p.Unlock()
+}
+
+// Clear the last known progress and counts, when the Gnomon data has been replaced or deleted
+func (p *GnomonProgress) clear() {
+	p.Lock()
+	p.Last = GetSyncStatus_Result{}
+	p.Counts = GnomonCounts{}
+	p.Unlock()
+}
+
+// Count the indexed SCIDs and DB size of the Gnomon path when the last count is older than DEFAULT_GNOMON_STATUS_INTERVAL
+// seconds, SCIDs are only counted while Gnomon is active. Counting walks the index so it is kept off the status requests
+func (p *GnomonProgress) count(path string, active bool) {
+	p.Lock()
+	if p.Counting || (p.Counts.Path == path && time.Since(p.Counts.Time) < time.Second*DEFAULT_GNOMON_STATUS_INTERVAL) {
+		p.Unlock()
+		return
+	}
+	p.Counting = true
+	p.Unlock()
+
+	counts := GnomonCounts{Path: path, Time: time.Now(), DBSize: dirSize(path)}
+	if active && gnomon.Index != nil {
+		counts.SCIDs = len(gnomon.GetAllOwnersAndSCIDs())
+	}
+
+	p.Lock()
+	p.Counts = counts
+	p.Counting = false
+	p.Unlock()
+}
+
+// Get the last counts of the Gnomon path
+func (p *GnomonProgress) counted(path string) (counts GnomonCounts, ok bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	counts = p.Counts
+	ok = counts.Path == path
+
+	return
+}
+
+// Get the Gnomon sync progress, when Gnomon is not running the last known progress is returned
+func getGnomonSyncStatus() (result GetSyncStatus_Result) {
+	if gnomon.Index == nil {
+		gnomon.Progress.Lock()
+		if gnomon.Progress.Network == session.Network {
+			result = gnomon.Progress.Last
+		}
+		gnomon.Progress.Unlock()
+
+		result.Active = false
+		result.Status = "inactive"
+		result.BlocksPerSecond = 0
+		result.ETA = -1
+		path := gnomonPath()
+		go gnomon.Progress.count(path, false)
+		if counts, ok := gnomon.Progress.counted(path); ok {
+			result.DBSize = counts.DBSize
+		}
+
+		return
+	}
+
+	result.Active = true
+	result.Status = gnomon.Index.Status
+	result.Endpoint = gnomon.Index.Endpoint
+	result.LastIndexedHeight = gnomon.Index.LastIndexedHeight
+	result.ChainHeight = gnomon.Index.ChainHeight
+	result.BlocksPerSecond = gnomon.Progress.rate()
+	if counts, ok := gnomon.Progress.counted(gnomon.Path); ok {
+		result.SCIDsIndexed = counts.SCIDs
+		result.DBSize = counts.DBSize
+	}
+
+	remaining := result.ChainHeight - result.LastIndexedHeight
+	if remaining <= 0 {
+		result.ETA = 0
+	} else if result.BlocksPerSecond > 0 {
+		result.ETA = int64(float64(remaining) / result.BlocksPerSecond)
+	} else {
+		result.ETA = -1
+	}
+
+	if result.ChainHeight > 0 {
+		result.Progress = math.Min(float64(result.LastIndexedHeight)/float64(result.ChainHeight)*100, 100)
+	}
+
+	gnomon.Progress.Lock()
+	gnomon.Progress.Network = session.Network
+	gnomon.Progress.Last = result
+	gnomon.Progress.Unlock()
+
+	return
+}
+
+// Format the Gnomon sync progress as markdown for display
+func formatGnomonSyncStatus(r GetSyncStatus_Result) string {
+	eta := "Unknown"
+	if r.ETA == 0 {
+		eta = "Synced"
+	} else if r.ETA > 0 {
+		eta = (time.Duration(r.ETA) * time.Second).String()
+	}
+
+	state := "Not running"
+	if r.Active {
+		state = r.Status
+	}
+
+	var md strings.Builder
+	md.WriteString(fmt.Sprintf("* Status:  %s\n", state))
+	if r.ChainHeight > 0 {
+		md.WriteString(fmt.Sprintf("* Indexed Height:  %d / %d  (%.1f%%)\n", r.LastIndexedHeight, r.ChainHeight, r.Progress))
+	}
+	if r.Active {
+		md.WriteString(fmt.Sprintf("* Sync Rate:  %.1f blocks/sec\n", r.BlocksPerSecond))
+		md.WriteString(fmt.Sprintf("* ETA:  %s\n", eta))
+	}
+	if r.SCIDsIndexed > 0 {
+		md.WriteString(fmt.Sprintf("* SCIDs Indexed:  %d\n", r.SCIDsIndexed))
+	}
+	md.WriteString(fmt.Sprintf("* Database Size:  %s\n", formatBytes(r.DBSize)))
+
+	return md.String()
+}
+
+// Get the Gnomon events subscribed to by XSWD applications. Events are subscribed with the XSWD Subscribe method as:
+//
+//	gnomon_new_invoke:<scid>
+//	gnomon_variable_change:<scid>:<key>
+//	gnomon_new_scid or gnomon_new_scid:<owner>
+func gnomonSubscriptions() (events map[rpc.EventType]bool) {
+	events = make(map[rpc.EventType]bool)
+	if cyberdeck.WS.server == nil {
+		return
+	}
+
+	connected := make(map[string]bool)
+	for _, app := range cyberdeck.WS.server.GetApplications() {
+		connected[app.Id] = true
+	}
+
+	// Subscriptions are tracked by Engram's Subscribe method, xswd's own event maps are not safe to read here
+	s := &gnomon.Events.Subscriptions
+	s.Lock()
+	for id, subscribed := range s.Apps {
+		if !connected[id] {
+			delete(s.Apps, id)
+			continue
+		}
+
+		for event := range subscribed {
+			events[event] = true
+		}
+	}
+	s.Unlock()
+
+	return
+}
+
+// Track a Gnomon event subscribed to by an XSWD application, the caller must hold the subscriptions lock
+func (s *GnomonSubscriptions) add(app string, event rpc.EventType) {
+	if s.Apps == nil {
+		s.Apps = make(map[string]map[rpc.EventType]bool)
+	}
+
+	if s.Apps[app] == nil {
+		s.Apps[app] = make(map[rpc.EventType]bool)
+	}
+
+	s.Apps[app][event] = true
+}
+
+// Broadcast the subscribed Gnomon events that occurred since the last indexed height was processed,
+// a subscription starts tracking from the height it is first seen so past events are not sent
+func (e *GnomonEvents) process() {
+	index := gnomon.Index
+	server := cyberdeck.WS.server
+	if index == nil || server == nil {
+		return
+	}
+
+	e.Lock()
+	if e.Running || e.Height == index.LastIndexedHeight {
+		e.Unlock()
+		return
+	}
+	e.Running = true
+	height := index.LastIndexedHeight
+
+	if e.Invokes == nil {
+		e.Invokes = make(map[string]int64)
+		e.Variables = make(map[string]string)
+		e.SCIDs = -1
+	}
+	lastInvokes, lastVariables, lastSCIDs := e.Invokes, e.Variables, e.SCIDs
+	e.Unlock()
+
+	type broadcast struct {
+		event rpc.EventType
+		value GnomonEvent
+	}
+
+	var broadcasts []broadcast
+	subscriptions := gnomonSubscriptions()
+	invokes := make(map[string]int64)
+	variables := make(map[string]string)
+	scids := -1
+
+	for event := range subscriptions {
+		split := strings.Split(string(event), ":")
+		switch split[0] {
+		case GNOMON_EVENT_INVOKE:
+			if len(split) != 2 {
+				continue
+			}
+
+			scid := split[1]
+			last, ok := lastInvokes[scid]
+			if !ok {
+				invokes[scid] = height
+				continue
+			}
+
+			invokes[scid] = last
+			for _, i := range gnomon.GetAllSCIDInvokeDetails(scid) {
+				if i.Height > last {
+					broadcasts = append(broadcasts, broadcast{event, GnomonEvent{SCID: scid, Height: i.Height, Invoke: i}})
+					if i.Height > invokes[scid] {
+						invokes[scid] = i.Height
+					}
+				}
+			}
+		case GNOMON_EVENT_VARIABLE:
+			if len(split) != 3 {
+				continue
+			}
+
+			scid, key := split[1], split[2]
+			value := gnomonValueByKey(scid, key)
+			variables[scid+":"+key] = value
+
+			if last, ok := lastVariables[scid+":"+key]; ok && last != value {
+				broadcasts = append(broadcasts, broadcast{event, GnomonEvent{SCID: scid, Height: height, Key: key, Value: value}})
+			}
+		case GNOMON_EVENT_SCID:
+			scids = 0
+		}
+	}
+
+	// New SCIDs are appended to the indexer's validated SCIDs, only the ones past the last seen position are read
+	if scids >= 0 {
+		index.RLock()
+		validated := index.ValidatedSCs
+		index.RUnlock()
+
+		scids = len(validated)
+		if lastSCIDs >= 0 && lastSCIDs < scids {
+			for _, scid := range validated[lastSCIDs:] {
+				owner := gnomon.GetOwner(scid)
+				for _, event := range []rpc.EventType{GNOMON_EVENT_SCID, rpc.EventType(GNOMON_EVENT_SCID + ":" + owner)} {
+					if subscriptions[event] {
+						broadcasts = append(broadcasts, broadcast{event, GnomonEvent{SCID: scid, Height: height, Owner: owner}})
+					}
+				}
+			}
+		}
+	}
+
+	e.Lock()
+	e.Running = false
+	e.Height = height
+	// Only keep the state of subscribed events so a new subscription starts from the current height
+	if e.Invokes != nil {
+		e.Invokes = invokes
+		e.Variables = variables
+		e.SCIDs = scids
+	}
+	e.Unlock()
+
+	// BroadcastEvent reads the applications' event maps, which Subscribe writes while holding this lock
+	e.Subscriptions.Lock()
+	for _, b := range broadcasts {
+		server.BroadcastEvent(b.event, b.value)
+	}
+	e.Subscriptions.Unlock()
+}
+
+// Clear the Gnomon event state when Gnomon is stopped
+func (e *GnomonEvents) reset() {
+	e.Lock()
+	e.Height = 0
+	e.Invokes = nil
+	e.Variables = nil
+	e.SCIDs = -1
+	e.Unlock()
+}
+
+// Get the current value of a SCID key from Gnomon as a string, numeric keys are tried if the string key has no value
+func gnomonValueByKey(scid, key string) (value string) {
+	valuesString, valuesUint64 := gnomon.GetSCIDValuesByKey(scid, key)
+	if len(valuesString) == 0 && len(valuesUint64) == 0 {
+		if k, err := strconv.ParseUint(key, 10, 64); err == nil {
+			valuesString, valuesUint64 = gnomon.GetSCIDValuesByKey(scid, k)
+		}
+	}
+
+	if len(valuesString) > 0 {
+		return valuesString[0]
+	}
+
+	if len(valuesUint64) > 0 {
+		return strconv.FormatUint(valuesUint64[0], 10)
+	}
+
+	return
+}
+
+// Stop all indexers and close Gnomon
+func stopGnomon() {
+	if gnomon.Index != nil {
+		// Keep the last progress to show while Gnomon is not running
+		getGnomonSyncStatus()
+		gnomon.Index.Close()
+		gnomon.Index = nil
+		closeGnomonBackends()
+		gnomon.Endpoint = false
+		gnomon.Events.reset()
+		logger.Printf("[Gnomon] Closed all indexers.\n")
+	}
+}
+
+// Close both Gnomon DB backends, the indexer only closes the one it uses and the other would keep its files locked
+func closeGnomonBackends() {
+	if gnomon.BBolt != nil && gnomon.BBolt.DB != nil {
+		gnomon.BBolt.DB.Close()
+	}
+
+	if gnomon.Graviton != nil && gnomon.Graviton.DB != nil {
+		gnomon.Graviton.DB.Close()
+	}
+
+	gnomon.BBolt = nil
+	gnomon.Graviton = nil
+}
+
+// Method of Gnomon GetOwner() where DB type is defined by Indexer.DBType
+func (g *Gnomon) GetOwner(scid string) (owner string) {
+	switch g.Index.DBType {
+	case "gravdb":
+		return g.Index.GravDBBackend.GetOwner(scid)
+	case "boltdb":
+		return g.Index.BBSBackend.GetOwner(scid)
+	default:
+		return
+	}
+}
+
+// Method of Gnomon GetAllOwnersAndSCIDs() where DB type is defined by Indexer.DBType
+func (g *Gnomon) GetAllOwnersAndSCIDs() (scids map[string]string) {
+	switch g.Index.DBType {
+	case "gravdb":
+		return g.Index.GravDBBackend.GetAllOwnersAndSCIDs()
+	case "boltdb":
+		return g.Index.BBSBackend.GetAllOwnersAndSCIDs()
+	default:
+		return
+	}
+}
+
+// Method of Gnomon to page owners and SCIDs sorted by SCID after a cursor, optionally filtered by owner, where DB type is defined by Indexer.DBType.
+// BoltDB is read with a cursor from the position of after, gravdb trees are ordered by key hash so they are read in full and sorted
+func (g *Gnomon) GetOwnersAndSCIDsAfter(after string, limit int, owner string) (owners []OwnerAndSCID, more bool) {
+	match := func(scid, o string) bool {
+		return scid > after && (owner == "" || owner == o)
+	}
+
+	switch g.Index.DBType {
+	case "gravdb":
+		for scid, o := range g.Index.GravDBBackend.GetAllOwnersAndSCIDs() {
+			if match(scid, o) {
+				owners = append(owners, OwnerAndSCID{SCID: scid, Owner: o})
+			}
+		}
+
+		sort.Slice(owners, func(i, j int) bool {
+			return owners[i].SCID < owners[j].SCID
+		})
+
+		if len(owners) > limit {
+			owners = owners[:limit]
+			more = true
+		}
+	case "boltdb":
+		g.Index.BBSBackend.DB.View(func(tx *bolt.Tx) error {
+			b := tx.Bucket([]byte("scowner"))
+			if b == nil {
+				return nil
+			}
+
+			c := b.Cursor()
+			for k, v := c.Seek([]byte(after)); k != nil; k, v = c.Next() {
+				if !match(string(k), string(v)) {
+					continue
+				}
+
+				if len(owners) == limit {
+					more = true
+					return nil
+				}
+
+				owners = append(owners, OwnerAndSCID{SCID: string(k), Owner: string(v)})
+			}
+
+			return nil
+		})
+	}
+
+	return
+}
+
+// Method of Gnomon to page miniblock details sorted by block hash after a cursor, optionally filtered to the miniblocks of a miner, where DB type
+// is defined by Indexer.DBType. BoltDB is read with a cursor from the position of after, gravdb trees are ordered by key hash so they are read in full and sorted
+func (g *Gnomon) GetMiniblockDetailsAfter(after string, limit int, miner string) (blocks []MiniblockDetails, more bool) {
+	mined := func(mbls []*structures.MBLInfo) (result []*structures.MBLInfo) {
+		if miner == "" {
+			return mbls
+		}
+
+		for _, m := range mbls {
+			if m.Miner == miner {
+				result = append(result, m)
+			}
+		}
+
+		return
+	}
+
+	switch g.Index.DBType {
+	case "gravdb":
+		for blid, mbls := range g.Index.GravDBBackend.GetAllMiniblockDetails() {
+			if blid <= after {
+				continue
+			}
+
+			if mbls = mined(mbls); len(mbls) > 0 {
+				blocks = append(blocks, MiniblockDetails{Blid: blid, MBLdetails: mbls})
+			}
+		}
+
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].Blid < blocks[j].Blid
+		})
+
+		if len(blocks) > limit {
+			blocks = blocks[:limit]
+			more = true
+		}
+	case "boltdb":
+		g.Index.BBSBackend.DB.View(func(tx *bolt.Tx) error {
+			b := tx.Bucket([]byte("miniblocks"))
+			if b == nil {
+				return nil
+			}
+
+			c := b.Cursor()
+			for k, v := c.Seek([]byte(after)); k != nil; k, v = c.Next() {
+				if string(k) <= after {
+					continue
+				}
+
+				var mbls []*structures.MBLInfo
+				if json.Unmarshal(v, &mbls) != nil {
+					continue
+				}
+
+				if mbls = mined(mbls); len(mbls) == 0 {
+					continue
+				}
+
+				if len(blocks) == limit {
+					more = true
+					return nil
+				}
+
+				blocks = append(blocks, MiniblockDetails{Blid: string(k), MBLdetails: mbls})
+			}
+
+			return nil
+		})
+	}
+
+	return
+}
+
+// Method of Gnomon GetAllSCIDVariableDetails() where DB type is defined by Indexer.DBType
+func (g *Gnomon) GetAllSCIDVariableDetails(scid string) (vars []*structures.SCIDVariable) {
+	switch g.Index.DBType {
+	case "gravdb":
+		return g.Index.GravDBBackend.GetAllSCIDVariableDetails(scid)
+	case "boltdb":
+		return g.Index.BBSBackend.GetAllSCIDVariableDetails(scid)
+	default:
+		return
+	}
+}
+
+// Method of Gnomon GetAllSCIDInvokeDetails() where DB type is defined by Indexer.DBType
+func (g *Gnomon) GetAllSCIDInvokeDetails(scid string) (invokes []*structures.SCTXParse) {
+	switch g.Index.DBType {
+	case "gravdb":
+		return g.Index.GravDBBackend.GetAllSCIDInvokeDetails(scid)
+	case "boltdb":
+		return g.Index.BBSBackend.GetAllSCIDInvokeDetails(scid)
+	default:
+		return
+	}
+}
+
+// Method of Gnomon GetAllNormalTxWithSCIDByAddr() where DB type is defined by Indexer.DBType
+func (g *Gnomon) GetAllNormalTxWithSCIDByAddr(addr string) (normTxsWithSCID []*structures.NormalTXWithSCIDParse) {
+	switch g.Index.DBType {
+	case "gravdb":
+		return g.Index.GravDBBackend.GetAllNormalTxWithSCIDByAddr(addr)
+	case "boltdb":
+		return g.Index.BBSBackend.GetAllNormalTxWithSCIDByAddr(addr)
+	default:
+		return
+	}
+}
+
+// Method of Gnomon GetSCIDValuesByKey() where DB type is defined by Indexer.DBType
+func (g *Gnomon) GetSCIDValuesByKey(scid string, key interface{}) (valuesstring []string, valuesuint64 []uint64) {
+	switch g.Index.DBType {
+	case "gravdb":
+		return g.Index.GravDBBackend.GetSCIDValuesByKey(scid, key, g.Index.ChainHeight, true)
+	case "boltdb":
+		return g.Index.BBSBackend.GetSCIDValuesByKey(scid, key, g.Index.ChainHeight, true)
+	default:
+		return
+	}
+}
+
+// Method of Gnomon GetSCIDKeysByValue() where DB type is defined by Indexer.DBType
+func (g *Gnomon) GetSCIDKeysByValue(scid string, val interface{}) (keysstring []string, keysuint64 []uint64) {
+	switch g.Index.DBType {
+	case "gravdb":
+		return g.Index.GravDBBackend.GetSCIDKeysByValue(scid, val, g.Index.ChainHeight, true)
+	case "boltdb":
+		return g.Index.BBSBackend.GetSCIDKeysByValue(scid, val, g.Index.ChainHeight, true)
+	default:
+		return
+	}
+}
+
+// Method of Gnomon GetSCIDInteractionHeight() where DB type is defined by Indexer.DBType
+func (g *Gnomon) GetSCIDInteractionHeight(scid string) (heights []int64) {
+	switch g.Index.DBType {
+	case "gravdb":
+		return g.Index.GravDBBackend.GetSCIDInteractionHeight(scid)
+	case "boltdb":
+		return g.Index.BBSBackend.GetSCIDInteractionHeight(scid)
+	default:
+		return
+	}
+}
+
+// Add a var store only scid to Gnomon DB
+func (g *Gnomon) AddSCIDToIndex(scid string) (err error) {
+	add := make(map[string]*structures.FastSyncImport)
+	add[scid] = &structures.FastSyncImport{}
+
+	return gnomon.Index.AddSCIDToIndex(add, false, true)
+}
+
+// Get the current code of a smart contract
+func getContractCode(scid string) (code string, err error) {
+	var params = rpc.GetSC_Params{SCID: scid, Variables: false, Code: true}
+	var result rpc.GetSC_Result
+
+	// Asset metadata is read by several workers at once, so each call uses its own connection instead of rpc_client
+	ws, _, err := websocket.DefaultDialer.Dial("ws://"+session.Daemon+"/ws", nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	input_output := rwc.New(ws)
+	client := jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
+	defer client.Close()
+
+	err = client.CallResult(context.Background(), "DERO.GetSC", params, &result)
+	if err != nil {
+		logger.Errorf("[Engram] Error getting SC code: %s\n", err)
+		return
+	}
+
+	code = result.Code
+
+	return
+}
+
+// DVM starter InitializePrivate() example for smart contract builder
+func dvmInitFuncExample() string {
+	return `Function InitializePrivate() Uint64
+10 IF EXISTS("owner") == 0 THEN GOTO 30
+20 RETURN 1
+30 STORE("owner", SIGNER())
+31 STORE("var_header_name", "")
+32 STORE("var_header_description", "")
+33 STORE("var_header_icon", "")
+40 RETURN 0
+End Function`
+}
+
+// DVM starter function for smart contract builder
+func dvmFuncExample(increment int) string {
+	return `Function new` + fmt.Sprintf("%d", increment) + `() Uint64
+10 
+20 
+30 RETURN 0
+End Function`
+}
+
+// Verification overlay for user actions with or without password
+func verificationOverlay(password bool, headerText, subText, dismiss string, callback func(bool)) {
+	overlay := session.Window.Canvas().Overlays()
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	if password {
+		headerText = "ACCOUNT  VERIFICATION  REQUIRED"
+		dismiss = "Submit"
+	}
+
+	header := canvas.NewText(headerText, colors.Gray)
+	header.TextSize = 14
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
+
+	btnConfirm := widget.NewButton(dismiss, nil)
+	btnConfirm.Disable()
+
+	entryPassword := NewReturnEntry()
+	entryPassword.Password = true
+	entryPassword.PlaceHolder = "Password"
+	entryPassword.OnChanged = func(s string) {
+		if s == "" {
+			btnConfirm.Text = dismiss
+			btnConfirm.Disable()
+			btnConfirm.Refresh()
+		} else {
+			btnConfirm.Text = dismiss
+			btnConfirm.Enable()
+			btnConfirm.Refresh()
+		}
+	}
+
+	subHeader := canvas.NewText(subText, colors.Account)
+	if password {
+		subText = "Confirm Password"
+		subHeader.TextSize = 22
+	} else {
+		subHeader.TextSize = 18
+		entryPassword.Hide()
+		btnConfirm.Enable()
+		btnConfirm.Refresh()
+	}
+
+	subHeader.Text = subText
+	subHeader.Alignment = fyne.TextAlignCenter
+	subHeader.TextStyle = fyne.TextStyle{Bold: true}
+	subHeader.Refresh()
+
+	linkClose := widget.NewHyperlinkWithStyle("Cancel", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClose.OnTapped = func() {
+		callback(false)
+		overlay.Top().Hide()
+		overlay.Remove(overlay.Top())
+		overlay.Remove(overlay.Top())
+	}
+
+	btnConfirm.OnTapped = func() {
+		btnConfirm.Disable()
+		if password {
+			if engram.Disk.Check_Password(entryPassword.Text) {
+				callback(true)
+				overlay.Top().Hide()
+				overlay.Remove(overlay.Top())
+				overlay.Remove(overlay.Top())
+			} else {
+				btnConfirm.Text = "Invalid Password..."
+				btnConfirm.Refresh()
+			}
+		} else {
+			callback(true)
+			overlay.Top().Hide()
+			overlay.Remove(overlay.Top())
+			overlay.Remove(overlay.Top())
+		}
+	}
+
+	entryPassword.OnReturn = btnConfirm.OnTapped
+
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	overlay.Add(
+		container.NewStack(
+			&iframe{},
+			canvas.NewRectangle(colors.DarkMatter),
+		),
+	)
+
+	overlay.Add(
+		container.NewStack(
+			&iframe{},
+			container.NewCenter(
+				container.NewVBox(
+					span,
+					container.NewCenter(
+						header,
+					),
+					rectSpacer,
+					rectSpacer,
+					subHeader,
+					widget.NewLabel(""),
+					container.NewCenter(
+						container.NewStack(
+							span,
+							entryPassword,
+						),
+					),
+					rectSpacer,
+					rectSpacer,
+					btnConfirm,
+					rectSpacer,
+					rectSpacer,
+					container.NewHBox(
+						layout.NewSpacer(),
+						linkClose,
+						layout.NewSpacer(),
+					),
+					rectSpacer,
+					rectSpacer,
+				),
+			),
+		),
+	)
+
+	if password {
+		session.Window.Canvas().Focus(entryPassword)
+	}
+}
+
+// Color for the TELA likes ratio and individual rating numbers
+func telaRatingColor(r uint64) color.Color {
+	if r > 65 {
+		return colors.Green
+	} else if r > 32 {
+		return colors.Yellow
+	} else {
+		return colors.Red
+	}
+}
+
+// Color for the TELA average rating number hexagon
+func telaHexagonColor(r float64) fyne.Resource {
+	if r > 6.5 {
+		return resourceTelaHexagonGreen
+	} else if r > 3.2 {
+		return resourceTelaHexagonYellow
+	} else {
+		return resourceTelaHexagonRed
+	}
+}
+
+// Display ratings overview and the details of each rating for the TELA SCID
+func viewTELARatingsOverlay(name, scid string) (err error) {
+	rectWidth90 := canvas.NewRectangle(color.Transparent)
+	rectWidth90.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	header := canvas.NewText("TELA  RATINGS", colors.Gray)
+	header.TextSize = 16
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
+
+	if len(name) > 30 {
+		name = fmt.Sprintf("%s...", name[0:30])
+	}
+
+	nameHdr := canvas.NewText(name, colors.Account)
+	nameHdr.Alignment = fyne.TextAlignCenter
+	nameHdr.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelSCID := canvas.NewText("   SMART  CONTRACT  ID", colors.Gray)
+	labelSCID.TextSize = 14
+	labelSCID.Alignment = fyne.TextAlignLeading
+	labelSCID.TextStyle = fyne.TextStyle{Bold: true}
+
+	textSCID := widget.NewRichTextWithText(scid)
+	textSCID.Wrapping = fyne.TextWrapWord
+
+	textLikes := widget.NewRichTextFromMarkdown("Likes:")
+	textDislikes := widget.NewRichTextFromMarkdown("Dislikes:")
+	textAverage := widget.NewRichTextFromMarkdown("Average:")
+
+	ratingsBox := container.NewVBox(labelSCID, textSCID)
+
+	ratings, err := tela.GetRating(scid, session.Daemon, 0)
+	if err != nil {
+		removeOverlays()
+		logger.Errorf("[Engram] GetRating: %s\n", err)
+		err = fmt.Errorf("error could not get ratings")
+		return
+	}
+
+	removeOverlays()
+	overlay := session.Window.Canvas().Overlays()
+
+	ratingsBox.Add(container.NewHBox(textLikes, canvas.NewText(fmt.Sprintf("%d", ratings.Likes), colors.Green)))
+	ratingsBox.Add(container.NewHBox(textDislikes, canvas.NewText(fmt.Sprintf("%d", ratings.Dislikes), colors.Red)))
+	ratingsBox.Add(container.NewHBox(textAverage, canvas.NewText(fmt.Sprintf("%0.1f/10", ratings.Average), colors.Account)))
+
+	linkRate := widget.NewHyperlinkWithStyle("Rate SCID", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	linkRate.OnTapped = func() {
+		rateTELAOverlay(name, scid)
+	}
+	linkRate.Hide()
+
+	// Check if wallet has rated SCID
+	if gnomon.Index != nil {
+		ratingStore, _ := gnomon.GetSCIDValuesByKey(scid, engram.Disk.GetAddress().String())
+		if ratingStore == nil {
+			linkRate.Show()
+		}
+	}
+
+	linkBack := widget.NewHyperlinkWithStyle("Back to Application", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkBack.OnTapped = func() {
+		overlay.Top().Hide()
+		overlay.Remove(overlay.Top())
+		overlay.Remove(overlay.Top())
+	}
+
+	labelSeparator := widget.NewRichTextFromMarkdown("")
+	labelSeparator.Wrapping = fyne.TextWrapOff
+	labelSeparator.ParseMarkdown("---")
+	labelSeparator2 := widget.NewRichTextFromMarkdown("")
+	labelSeparator2.Wrapping = fyne.TextWrapOff
+	labelSeparator2.ParseMarkdown("---")
+
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	userRatingsBox := container.NewVBox()
+
+	for _, r := range ratings.Ratings {
+		ratingString, err := tela.Ratings.ParseString(r.Rating)
+		if err != nil {
+			ratingString = fmt.Sprintf("%d", r.Rating)
+		}
+
+		labelSeparator := widget.NewRichTextFromMarkdown("")
+		labelSeparator.Wrapping = fyne.TextWrapOff
+		labelSeparator.ParseMarkdown("---")
+
+		labelAddress := widget.NewRichTextFromMarkdown(r.Address)
+		labelAddress.Wrapping = fyne.TextWrapWord
+
+		userRatingsBox.Add(
+			container.NewVBox(
+				labelAddress,
+				container.NewHBox(widget.NewRichTextFromMarkdown("Height:"), canvas.NewText(fmt.Sprintf("%d", r.Height), colors.Account)),
+				container.NewHBox(widget.NewRichTextFromMarkdown("Rating:"), canvas.NewText(fmt.Sprintf("%d", r.Rating), telaRatingColor(r.Rating))),
+				widget.NewRichTextFromMarkdown(ratingString),
+				labelSeparator,
+			),
+		)
+	}
+
+	userRatingsBoxScroll := container.NewVScroll(
+		container.NewHBox(
+			layout.NewSpacer(),
+			container.NewVBox(
+				ratingsBox,
+				rectWidth90,
+				container.NewHBox(
+					linkRate,
+					layout.NewSpacer(),
+				),
+				rectSpacer,
+				rectSpacer,
+				labelSeparator2,
+				rectSpacer,
+				rectSpacer,
+				userRatingsBox,
+			),
+			layout.NewSpacer(),
+		),
+	)
+	userRatingsBoxScroll.SetMinSize(fyne.NewSize(ui.Width*0.80, ui.Height*0.50))
+
+	overlayCont := container.NewVBox(
+		span,
+		container.NewCenter(
+			header,
+		),
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		container.NewCenter(
+			nameHdr,
+		),
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		labelSeparator,
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		container.NewHBox(
+			layout.NewSpacer(),
+		),
+		userRatingsBoxScroll,
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+	)
+
+	menuLabel := canvas.NewText("  M O R E   O P T I O N S  ", colors.Gray)
+	menuLabel.TextSize = 11
+	menuLabel.Alignment = fyne.TextAlignCenter
+	menuLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	sep := canvas.NewRectangle(colors.Gray)
+	sep.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+
+	line1 := container.NewVBox(
+		layout.NewSpacer(),
+		sep,
+		layout.NewSpacer(),
+	)
+
+	line2 := container.NewVBox(
+		layout.NewSpacer(),
+		sep,
+		layout.NewSpacer(),
+	)
+
+	bottom := container.NewStack(
+		container.NewVBox(
+			rectSpacer,
+			rectSpacer,
+			container.NewStack(
+				container.NewHBox(
+					layout.NewSpacer(),
+					line1,
+					layout.NewSpacer(),
+					menuLabel,
+					layout.NewSpacer(),
+					line2,
+					layout.NewSpacer(),
+				),
+			),
+			rectSpacer,
+			rectSpacer,
+			container.NewCenter(
+				layout.NewSpacer(),
+				linkBack,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			rectSpacer,
+			rectSpacer,
+			rectSpacer,
+		),
+	)
+
+	overlay.Add(
+		container.NewStack(
+			&iframe{},
+			canvas.NewRectangle(colors.DarkMatter),
+		),
+	)
+
+	overlay.Add(
+		container.NewStack(
+			&iframe{},
+			container.NewBorder(
+				nil,
+				bottom,
+				nil,
+				nil,
+				overlayCont,
+			),
+		),
+	)
+
+	return
+}
+
+// TELA smart contract rating overlay with password confirmation
+func rateTELAOverlay(name, scid string) {
+	overlay := session.Window.Canvas().Overlays()
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	selectSpacer := canvas.NewRectangle(color.Transparent)
+	selectSpacer.SetMinSize(fyne.NewSize(80, 5))
+
+	header := canvas.NewText("RATE  TELA  INDEX", colors.Gray)
+	header.TextSize = 16
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
+
+	if len(name) > 30 {
+		name = fmt.Sprintf("%s...", name[0:30])
+	}
+
+	nameHdr := canvas.NewText(name, colors.Account)
+	nameHdr.Alignment = fyne.TextAlignCenter
+	nameHdr.TextStyle = fyne.TextStyle{Bold: true}
+
+	btnConfirm := widget.NewButton("Rate", nil)
+	btnConfirm.Disable()
+
+	var telaCategories, negativeDetails, positiveDetails []string
+	for i := uint64(0); i < 10; i++ {
+		telaCategories = append(telaCategories, tela.Ratings.Category(i))
+	}
+	categorySelect := widget.NewSelect(telaCategories, nil)
+
+	scidLabel := canvas.NewText("   SMART  CONTRACT  ID", colors.Gray)
+	scidLabel.TextSize = 14
+	scidLabel.Alignment = fyne.TextAlignCenter
+	scidLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	scidText := widget.NewRichTextFromMarkdown(scid)
+	scidText.Wrapping = fyne.TextWrapWord
+
+	errorText := canvas.NewText(" ", colors.Green)
+	errorText.TextSize = 12
+	errorText.Alignment = fyne.TextAlignCenter
+
+	for i := uint64(0); i < 10; i++ {
+		negativeDetails = append(negativeDetails, tela.Ratings.Detail(i, false))
+		positiveDetails = append(positiveDetails, tela.Ratings.Detail(i, true))
+	}
+	negativeSelect := widget.NewSelect(negativeDetails, nil)
+	positiveSelect := widget.NewSelect(positiveDetails, nil)
+
+	categoryHeader := canvas.NewText("Category", colors.Account)
+	categoryHeader.Alignment = fyne.TextAlignLeading
+	categoryHeader.TextStyle = fyne.TextStyle{Bold: true}
+	categoryHeader.Refresh()
+
+	detailHeader := canvas.NewText("Detail", colors.Account)
+	detailHeader.Alignment = fyne.TextAlignLeading
+	detailHeader.TextStyle = fyne.TextStyle{Bold: true}
+	detailHeader.Refresh()
+
+	ratingHeader := canvas.NewText("Rating", colors.Account)
+	ratingHeader.Alignment = fyne.TextAlignLeading
+	ratingHeader.TextStyle = fyne.TextStyle{Bold: true}
+	ratingHeader.Refresh()
+
+	ratingText := widget.NewRichTextFromMarkdown("")
+	ratingText.Wrapping = fyne.TextWrapWord
+
+	linkClose := widget.NewHyperlinkWithStyle("Cancel", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClose.OnTapped = func() {
+		overlay.Top().Hide()
+		overlay.Remove(overlay.Top())
+		overlay.Remove(overlay.Top())
+	}
+
+	btnConfirm.OnTapped = func() {
+		errorText.Text = ""
+		errorText.Refresh()
+		btnConfirm.Disable()
+		if gnomon.Index != nil {
+			var ratingStore []string
+			switch gnomon.Index.DBType {
+			case "gravdb":
+				ratingStore, _ = gnomon.Index.GravDBBackend.GetSCIDValuesByKey(scid, engram.Disk.GetAddress().String(), gnomon.Index.LastIndexedHeight, false)
+			case "boltdb":
+				ratingStore, _ = gnomon.Index.BBSBackend.GetSCIDValuesByKey(scid, engram.Disk.GetAddress().String(), gnomon.Index.LastIndexedHeight, false)
+			}
+			if ratingStore != nil {
+				errorText.Text = "already rated this contract"
+				errorText.Color = colors.Red
+				errorText.Refresh()
+				return
+			}
+		}
+
+		category := categorySelect.SelectedIndex()
+		if category < 0 {
+			errorText.Text = "select a category"
+			errorText.Color = colors.Red
+			errorText.Refresh()
+			return
+		}
+
+		var detail int
+		if category > 4 {
+			detail = positiveSelect.SelectedIndex()
+		} else {
+			detail = negativeSelect.SelectedIndex()
+		}
+
+		if detail < 0 {
+			errorText.Text = "select a detail"
+			errorText.Color = colors.Red
+			errorText.Refresh()
+			return
+		}
+
+		rating := (category * 10) + detail
+
+		verificationOverlay(
+			true,
+			"",
+			"",
+			"",
+			func(b bool) {
+				if !b {
+					btnConfirm.Enable()
+					return
+				}
+
+				overlay.Top().Hide()
+				overlay.Remove(overlay.Top())
+				overlay.Remove(overlay.Top())
+
+				txid, err := tela.Rate(engram.Disk, scid, uint64(rating))
+				if err != nil {
+					logger.Errorf("[Engram] Rate TX: %s\n", err)
+					return
+				}
+
+				logger.Printf("[Engram] Rate TXID: %s\n", txid)
+			},
+		)
+	}
+
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	overlayCont := container.NewVBox(
+		span,
+		container.NewCenter(
+			header,
+		),
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		container.NewCenter(
+			nameHdr,
+		),
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		scidLabel,
+		scidText,
+		widget.NewLabel(""),
+		container.NewCenter(
+			container.NewStack(
+				span,
+				container.NewBorder(
+					nil,
+					nil,
+					container.NewStack(
+						selectSpacer,
+						categoryHeader,
+					),
+					nil,
+					categorySelect,
+				),
+			),
+		),
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		container.NewCenter(
+			container.NewStack(
+				span,
+				container.NewBorder(
+					nil,
+					nil,
+					container.NewStack(
+						selectSpacer,
+						detailHeader,
+					),
+					nil,
+					positiveSelect,
+				),
+			),
+		),
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		container.NewCenter(
+			container.NewStack(
+				span,
+				container.NewBorder(
+					nil,
+					nil,
+					container.NewStack(
+						selectSpacer,
+						ratingHeader,
+					),
+					nil,
+					ratingText,
+				),
+			),
+		),
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		errorText,
+		rectSpacer,
+		rectSpacer,
+		btnConfirm,
+		rectSpacer,
+		rectSpacer,
+		container.NewHBox(
+			layout.NewSpacer(),
+			linkClose,
+			layout.NewSpacer(),
+		),
+		rectSpacer,
+		rectSpacer,
+	)
+
+	categorySelect.OnChanged = func(s string) {
+		if positiveSelect.SelectedIndex() > -1 && negativeSelect.SelectedIndex() > -1 {
+			btnConfirm.Enable()
+		}
+
+		if categorySelect.SelectedIndex() > 4 {
+			overlayCont.Objects[17] = container.NewCenter(
+				container.NewStack(
+					span,
+					container.NewBorder(
+						nil,
+						nil,
+						container.NewStack(
+							selectSpacer,
+							detailHeader,
+						),
+						nil,
+
+						positiveSelect,
+					),
+				),
+			)
+			positiveSelect.SetSelectedIndex(0)
+			ratingText.ParseMarkdown(fmt.Sprintf("%d", (categorySelect.SelectedIndex()*10)+positiveSelect.SelectedIndex()))
+		} else {
+			overlayCont.Objects[17] = container.NewCenter(
+				container.NewStack(
+					span,
+					container.NewBorder(
+						nil,
+						nil,
+						container.NewStack(
+							selectSpacer,
+							detailHeader,
+						),
+						nil,
+						negativeSelect,
+					),
+				),
+			)
+			negativeSelect.SetSelectedIndex(0)
+			ratingText.ParseMarkdown(fmt.Sprintf("%d", (categorySelect.SelectedIndex()*10)+negativeSelect.SelectedIndex()))
+		}
+	}
+
+	positiveSelect.OnChanged = func(s string) {
+		if categorySelect.SelectedIndex() > -1 {
+			btnConfirm.Enable()
+			ratingText.ParseMarkdown(fmt.Sprintf("%d", (categorySelect.SelectedIndex()*10)+positiveSelect.SelectedIndex()))
+		}
+	}
+
+	negativeSelect.OnChanged = func(s string) {
+		if categorySelect.SelectedIndex() > -1 {
+			btnConfirm.Enable()
+			ratingText.ParseMarkdown(fmt.Sprintf("%d", (categorySelect.SelectedIndex()*10)+negativeSelect.SelectedIndex()))
+		}
+	}
+
+	overlay.Add(
+		container.NewStack(
+			&iframe{},
+			canvas.NewRectangle(colors.DarkMatter),
+		),
+	)
+
+	overlay.Add(
+		container.NewStack(
+			&iframe{},
+			container.NewCenter(
+				overlayCont,
+			),
+		),
+	)
+}
+
+// Install a new smart contract
+func installSC(code string, args []rpc.Argument) (txid string, err error) {
+	var dest string
+	switch session.Network {
+	case NETWORK_MAINNET:
+		dest = "dero1qykyta6ntpd27nl0yq4xtzaf4ls6p5e9pqu0k2x4x3pqq5xavjsdxqgny8270"
+	case NETWORK_SIMULATOR:
+		dest = "deto1qyvyeyzrcm2fzf6kyq7egkes2ufgny5xn77y6typhfx9s7w3mvyd5qqynr5hx"
+	case NETWORK_TESTNET:
+		dest = "deto1qy0ehnqjpr0wxqnknyc66du2fsxyktppkr8m8e6jvplp954klfjz2qqdzcd8p"
+	}
+
+	transfer := rpc.Transfer{
+		Destination: dest,
+		Amount:      0,
+		Burn:        0,
+	}
+
+	_, err = transfer.Payload_RPC.CheckPack(transaction.PAYLOAD0_LIMIT)
+	if err != nil {
+		logger.Errorf("[Engram] Install arguments packing err: %s\n", err)
+		err = fmt.Errorf("contract install pack error")
+		return
+	}
+
+	// decode SC from base64 if possible
+	if sc, err := base64.StdEncoding.DecodeString(code); err == nil {
+		code = string(sc)
+	}
+
+	args = append(args, rpc.Argument{Name: rpc.SCACTION, DataType: rpc.DataUint64, Value: uint64(rpc.SC_INSTALL)})
+	args = append(args, rpc.Argument{Name: rpc.SCCODE, DataType: rpc.DataString, Value: code})
+
+	fees := uint64(0)
+	gasParams := rpc.GasEstimate_Params{
+		Transfers: []rpc.Transfer{transfer},
+		SC_Code:   code,
+		SC_Value:  0,
+		SC_RPC:    args,
+		Ringsize:  2,
+		Signer:    engram.Disk.GetAddress().String(),
+	}
+
+	if gas, err := getGasEstimate(gasParams); err == nil {
+		fees = gas
+		logger.Printf("[Engram] SC install fees: %d\n", fees)
+	} else {
+		// uses default fees
+		logger.Errorf("[Engram] Error estimating fees: %s\n", err)
+	}
+
+	tx, err := engram.Disk.TransferPayload0([]rpc.Transfer{transfer}, 2, false, args, fees, false)
+	if err != nil {
+		logger.Errorf("[Engram] Error while building install transaction: %s\n", err)
+		err = fmt.Errorf("contract install build error")
+		return
+	}
+
+	if err = engram.Disk.SendTransaction(tx); err != nil {
+		logger.Errorf("[Engram] Error while dispatching install transaction: %s\n", err)
+		err = fmt.Errorf("contract install dispatch error")
+		return
+	}
+
+	txid = tx.GetHash().String()
+
+	logger.Printf("[Engram] SC Installed: %s\n", txid)
+
+	return
+}
+
+// Set the Cyberdeck password
+func newRPCPassword() (s string) {
+	r := make([]byte, 20)
+	_, err := rand.Read(r)
+	if err != nil {
+		panic(err)
+	}
+
+	s = base64.URLEncoding.EncodeToString(r)
+	cyberdeck.RPC.pass = s
+	return
+}
+
+// Set the Cyberdeck username
+func newRPCUsername() (s string) {
+	r, _ := rand.Int(rand.Reader, big.NewInt(1600))
+	w := mnemonics.Key_To_Words(r, "english")
+	l := strings.Split(string(w), " ")
+	s = l[len(l)-2]
+	cyberdeck.RPC.user = s
+	return
+}
+
+// Start an RPC server to allow decentralized application communication
+func toggleRPCServer(port string) {
+	var err error
+	if engram.Disk == nil {
+		return
+	}
+
+	if cyberdeck.RPC.server != nil {
+		cyberdeck.RPC.server.RPCServer_Stop()
+		cyberdeck.RPC.server = nil
+		cyberdeck.RPC.status.Text = "Blocked"
+		cyberdeck.RPC.status.Color = colors.Gray
+		cyberdeck.RPC.status.Refresh()
+		cyberdeck.RPC.toggle.Text = "Turn On"
+		cyberdeck.RPC.toggle.Refresh()
+		status.Cyberdeck.FillColor = colors.Gray
+		status.Cyberdeck.StrokeColor = colors.Gray
+		status.Cyberdeck.Refresh()
+		cyberdeck.RPC.userText.Text = cyberdeck.RPC.user
+		cyberdeck.RPC.passText.Text = cyberdeck.RPC.pass
+		cyberdeck.RPC.userText.Enable()
+		cyberdeck.RPC.passText.Enable()
+		logger.Printf("[Engram] RPC server closed\n")
+	} else {
+		logger.Printf("[Engram] Starting RPC server %s\n", port)
+
+		globals.Arguments["--rpc-bind"] = port
+
+		if cyberdeck.RPC.user == "" {
+			cyberdeck.RPC.user = newRPCUsername()
+		}
+
+		if cyberdeck.RPC.pass == "" {
+			cyberdeck.RPC.pass = newRPCPassword()
+		}
+
+		globals.Arguments["--rpc-login"] = cyberdeck.RPC.user + ":" + cyberdeck.RPC.pass
+
+		cyberdeck.RPC.server, err = rpcserver.RPCServer_Start(engram.Disk, "Cyberdeck")
+		if err != nil {
+			cyberdeck.RPC.server = nil
+			cyberdeck.RPC.status.Text = "Blocked"
+			cyberdeck.RPC.status.Color = colors.Gray
+			cyberdeck.RPC.status.Refresh()
+			cyberdeck.RPC.toggle.Text = "Turn On"
+			cyberdeck.RPC.toggle.Refresh()
+			status.Cyberdeck.FillColor = colors.Gray
+			status.Cyberdeck.StrokeColor = colors.Gray
+			status.Cyberdeck.Refresh()
+			cyberdeck.RPC.userText.Text = cyberdeck.RPC.user
+			cyberdeck.RPC.passText.Text = cyberdeck.RPC.pass
+			cyberdeck.RPC.userText.Enable()
+			cyberdeck.RPC.passText.Enable()
+		} else {
+			cyberdeck.RPC.status.Text = "Allowed"
+			cyberdeck.RPC.status.Color = colors.Green
+			cyberdeck.RPC.status.Refresh()
+			cyberdeck.RPC.toggle.Text = "Turn Off"
+			cyberdeck.RPC.toggle.Refresh()
+			status.Cyberdeck.FillColor = colors.Green
+			status.Cyberdeck.StrokeColor = colors.Green
+			status.Cyberdeck.Refresh()
+			cyberdeck.RPC.userText.Text = cyberdeck.RPC.user
+			cyberdeck.RPC.passText.Text = cyberdeck.RPC.pass
+			cyberdeck.RPC.userText.Disable()
+			cyberdeck.RPC.passText.Disable()
+		}
+	}
+}
+
+// Get the header data of a smart contract from the asset metadata cache, the code is only read from the local Gnomon index
+func getContractHeader(scid crypto.Hash) (name string, desc string, icon string, owner string, code string) {
+	meta := assetMetadata.get(scid.String())
+	if gnomon.Index != nil {
+		code = gnomonValueByKey(scid.String(), "C")
+	}
+
+	return meta.Name, meta.Description, meta.Icon, meta.Owner, code
+}
+
+// Read the metadata of a smart contract from Gnomon (headers must follow the standard here: https://github.com/civilware/artificer-nfa-standard/blob/main/Headers/README.md)
+// When index is true, contracts missing from Gnomon are added to the index and the code is requested from the daemon if Gnomon does not store it
+func readAssetMetadata(scid string, index bool) (meta AssetMetadata) {
+	meta.SCID = scid
+	meta.Decimals = 5
+	if gnomon.Index == nil {
+		return
+	}
+
+	var found bool
+	var code string
+	vars := make(map[string]interface{})
+
+	headerData := gnomon.GetAllSCIDVariableDetails(scid)
+	if headerData == nil && index {
+		if err := gnomon.AddSCIDToIndex(scid); err != nil {
+			logger.Errorf("[Assets] Failed to index %s: %s\n", scid, err)
+		}
+		headerData = gnomon.GetAllSCIDVariableDetails(scid)
+	}
+
+	for _, h := range headerData {
+		key, ok := h.Key.(string)
+		if !ok {
+			continue
+		}
+
+		vars[key] = h.Value
+		value, _ := h.Value.(string)
+
+		switch key {
+		case "var_header_name":
+			found = true
+			meta.Name = value
+		case "var_header_description":
+			found = true
+			meta.Description = value
+		case "var_header_icon":
+			found = true
+			meta.Icon = value
+		case "owner":
+			meta.Owner = value
+		case "C":
+			code = value
+		case "decimals":
+			if d, ok := h.Value.(uint64); ok {
+				meta.Decimals = min(d, MAX_ASSET_DECIMALS)
+			}
+		}
+	}
+
+	if meta.Name == "" {
+		if value, ok := vars["nameHdr"].(string); ok {
+			found = true
+			meta.Name = value
+		}
+	}
+
+	if meta.Description == "" {
+		if value, ok := vars["descrHdr"].(string); ok {
+			found = true
+			meta.Description = value
+		}
+	}
+
+	if meta.Icon == "" {
+		if value, ok := vars["iconURLHdr"].(string); ok {
+			found = true
+			meta.Icon = value
+		}
+	}
+
+	// Secondary check for headers in Gnomon SC
+	if !found {
+		headerData = gnomon.GetAllSCIDVariableDetails(structures.MAINNET_GNOMON_SCID)
+		if headerData == nil && index {
+			if err := gnomon.AddSCIDToIndex(structures.MAINNET_GNOMON_SCID); err != nil {
+				logger.Errorf("[Assets] Failed to index Gnomon SC: %s\n", err)
+			}
+			headerData = gnomon.GetAllSCIDVariableDetails(structures.MAINNET_GNOMON_SCID)
+		}
+
+		for _, h := range headerData {
+			key, ok := h.Key.(string)
+			if !ok || !strings.HasPrefix(key, scid) {
+				continue
+			}
+
+			value, _ := h.Value.(string)
+			if key == scid {
+				header := strings.Split(value, ";")
+				if len(header) > 2 {
+					meta.Name = header[0]
+					meta.Description = header[1]
+					meta.Icon = header[2]
+				}
+			} else if key == scid+"owner" && meta.Owner == "" {
+				meta.Owner = value
+			}
+		}
+	}
+
+	// Owners stored raw by SIGNER() are shown as addresses
+	if len(meta.Owner) == 33 {
+		if addr, err := rpc.NewAddressFromCompressedKeys([]byte(meta.Owner)); err == nil {
+			addr.Mainnet = engram.Disk != nil && engram.Disk.GetNetwork()
+			meta.Owner = addr.String()
+		}
+	}
+
+	if code == "" && index {
+		code, _ = getContractCode(scid)
+	}
+
+	if code != "" {
+		hash := sha256.Sum256([]byte(code))
+		meta.CodeHash = hex.EncodeToString(hash[:])
+	}
+
+	meta.Standard = detectAssetStandard(vars, code)
+	if meta.Standard == ASSET_STANDARD_G45_NFT || meta.Standard == ASSET_STANDARD_NFA {
+		if _, ok := vars["decimals"]; !ok {
+			meta.Decimals = 0
+		}
+	}
+
+	return
+}
+
+// Detect the standard a smart contract follows from its stored variables and code
+func detectAssetStandard(vars map[string]interface{}, code string) string {
+	if _, ok := vars["telaVersion"]; ok {
+		return ASSET_STANDARD_TELA_INDEX
+	}
+
+	if _, ok := vars["docVersion"]; ok {
+		return ASSET_STANDARD_TELA_DOC
+	}
+
+	if _, ok := vars["artificerAddr"]; ok {
+		return ASSET_STANDARD_NFA
+	}
+
+	if _, ok := vars["artificerFee"]; ok {
+		return ASSET_STANDARD_NFA
+	}
+
+	if t, ok := vars["type"].(string); ok {
+		switch t {
+		case ASSET_STANDARD_G45_NFT, ASSET_STANDARD_G45_AT, ASSET_STANDARD_G45_FAT, ASSET_STANDARD_G45_C:
+			return t
+		}
+	}
+
+	if strings.Contains(code, "SEND_ASSET_TO_ADDRESS") {
+		return ASSET_STANDARD_TOKEN
+	}
+
+	return ASSET_STANDARD_CONTRACT
+}
+
+// Get the cached metadata of a smart contract, missing or expired entries are refreshed in the background
+func (c *AssetMetadataCache) get(scid string) (meta AssetMetadata) {
+	c.Lock()
+	if c.Entries == nil {
+		c.Entries = make(map[string]AssetMetadata)
+		c.Pending = make(map[string]bool)
+	}
+
+	meta, ok := c.Entries[scid]
+	c.Unlock()
+
+	if !ok {
+		var stored []byte
+		var err error
+		if engram.Disk != nil {
+			stored, err = GetEncryptedValue("Asset Metadata", []byte(scid))
+		}
+
+		if err != nil || len(stored) == 0 || json.Unmarshal(stored, &meta) != nil {
+			// Use what is already in the local index until the refresh completes
+			meta = readAssetMetadata(scid, false)
+		}
+
+		c.Lock()
+		if c.Entries != nil {
+			c.Entries[scid] = meta
+		}
+		c.Unlock()
+	}
+
+	if time.Now().Unix()-meta.Updated > DEFAULT_ASSET_METADATA_EXPIRY {
+		c.Lock()
+		if c.Pending != nil && !c.Pending[scid] {
+			c.Pending[scid] = true
+			go c.refresh(scid)
+		}
+		c.Unlock()
+	}
+
+	return
+}
+
+// Get the metadata of many smart contracts, used to load lists before they are rendered
+func (c *AssetMetadataCache) preload(scids []string) (metas map[string]AssetMetadata) {
+	metas = make(map[string]AssetMetadata, len(scids))
+	for _, scid := range scids {
+		metas[scid] = c.get(scid)
+	}
+
+	return
+}
+
+// Refresh the metadata of a smart contract from Gnomon and the daemon and store it
+func (c *AssetMetadataCache) refresh(scid string) {
+	c.Lock()
+	if c.workers == nil {
+		c.workers = make(chan struct{}, DEFAULT_ASSET_METADATA_WORKERS)
+	}
+	workers := c.workers
+	c.Unlock()
+
+	workers <- struct{}{}
+	defer func() {
+		<-workers
+		c.Lock()
+		delete(c.Pending, scid)
+		c.Unlock()
+	}()
+
+	if gnomon.Index == nil || engram.Disk == nil {
+		return
+	}
+
+	meta := readAssetMetadata(scid, true)
+	meta.Updated = time.Now().Unix()
+
+	if data, err := json.Marshal(meta); err == nil {
+		if err = StoreEncryptedValue("Asset Metadata", []byte(scid), data); err != nil {
+			logger.Errorf("[Assets] Failed to store metadata for %s: %s\n", scid, err)
+		}
+	}
+
+	c.Lock()
+	if c.Entries != nil {
+		c.Entries[scid] = meta
+	}
+	update := c.OnUpdate
+	c.Unlock()
+
+	if update != nil {
+		fyne.Do(update)
+	}
+}
+
+// Drop the in memory cache when an account is closed
+func (c *AssetMetadataCache) reset() {
+	c.Lock()
+	c.Entries = nil
+	c.Pending = nil
+	c.OnUpdate = nil
+	c.Unlock()
+}
+
+// Get the current state of an Artificer NFA contract from the daemon
+func getNFAListing(scid string) (nfa NFAListing, err error) {
+	var params = rpc.GetSC_Params{SCID: scid, Variables: true, Code: true}
+	var result rpc.GetSC_Result
+
+	// The NFA panel loads in the background, so it uses its own connection instead of rpc_client
+	ws, _, err := websocket.DefaultDialer.Dial("ws://"+session.Daemon+"/ws", nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	input_output := rwc.New(ws)
+	client := jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
+	defer client.Close()
+
+	err = client.CallResult(context.Background(), "DERO.GetSC", params, &result)
+	if err != nil {
+		logger.Errorf("[NFA] Error getting SC state: %s\n", err)
+		return
+	}
+
+	nfa.Contract, _, err = dvm.ParseSmartContract(result.Code)
+	if err != nil {
+		err = fmt.Errorf("could not parse contract code: %s", err)
+		return
+	}
+
+	// The daemon returns string values hex encoded and numbers as JSON numbers
+	text := func(key string) string {
+		if value, ok := result.VariableStringKeys[key].(string); ok {
+			if decoded, err := hex.DecodeString(value); err == nil {
+				return string(decoded)
+			}
+		}
+		return ""
+	}
+
+	number := func(key string) uint64 {
+		if value, ok := result.VariableStringKeys[key].(float64); ok {
+			return uint64(value)
+		}
+		return 0
+	}
+
+	// Addresses are stored raw by SIGNER()
+	address := func(key string) string {
+		value := text(key)
+		if len(value) == 33 {
+			if addr, err := rpc.NewAddressFromCompressedKeys([]byte(value)); err == nil {
+				addr.Mainnet = engram.Disk != nil && engram.Disk.GetNetwork()
+				return addr.String()
+			}
+		}
+		return value
+	}
+
+	nfa.SCID = scid
+	nfa.Owner = address("owner")
+	nfa.Creator = address("creatorAddr")
+	nfa.Collection = text("collection")
+	nfa.FileType = text("typeHdr")
+	nfa.File = text("fileURL")
+	nfa.Cover = text("coverURL")
+	nfa.Signature = text("signURL")
+	nfa.FileCheckC = text("fileCheckC")
+	nfa.FileCheckS = text("fileCheckS")
+	nfa.Royalty = number("royalty")
+	nfa.ArtificerFee = number("artificerFee")
+	nfa.ListType = text("listType")
+	nfa.StartPrice = number("startPrice")
+	nfa.CurrentBid = number("currBidPrice")
+	nfa.Bidder = address("currBidAddr")
+	nfa.BidCount = number("bidCount")
+	nfa.BuyCount = number("buyCount")
+	nfa.Start = number("startBlockTime")
+	nfa.End = number("endBlockTime")
+	nfa.Charity = address("charityDonateAddr")
+	nfa.CharityPerc = number("charityDonatePerc")
+	nfa.Escrow = result.Balances[scid]
+
+	return
+}
+
+// Check if the NFA is listed for sale or auction
+func (n NFAListing) listed() bool {
+	return n.ListType == NFA_LIST_SALE || n.ListType == NFA_LIST_AUCTION
+}
+
+// Check if the listing period of the NFA has passed
+func (n NFAListing) ended() bool {
+	return n.End > 0 && uint64(time.Now().Unix()) >= n.End
+}
+
+// Check if the NFA contract has an entrypoint
+func (n NFAListing) supports(action string) bool {
+	_, ok := n.Contract.Functions[action]
+	return ok
+}
+
+// Check if an entrypoint of the NFA contract calls a DVM function
+func (n NFAListing) uses(action string, call string) bool {
+	fn, ok := n.Contract.Functions[action]
+	if !ok {
+		return false
+	}
+
+	for l := range fn.Lines {
+		for i := range fn.Lines[l] {
+			if fn.Lines[l][i] == call && i+1 < len(fn.Lines[l]) && fn.Lines[l][i+1] == "(" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Get a copy of the parameters of an NFA contract entrypoint
+func (n NFAListing) params(action string) (params []dvm.Variable) {
+	if fn, ok := n.Contract.Functions[action]; ok {
+		params = append(params, fn.Params...)
+	}
+
+	return
+}
+
+// Call an NFA contract entrypoint, the NFA is deposited when the entrypoint expects it
+func executeNFAAction(nfa NFAListing, action string, dero_amount uint64, params []dvm.Variable) (storage uint64, err error) {
+	if !nfa.supports(action) {
+		err = fmt.Errorf("contract does not support %s", action)
+		return
+	}
+
+	var asset_amount uint64
+	if nfa.uses(action, "ASSETVALUE") {
+		asset_amount = 1
+	}
+
+	if !nfa.uses(action, "DEROVALUE") {
+		dero_amount = 0
+	}
+
+	// Entrypoints use SIGNER() to identify the owner or bidder
+	return executeContractFunction(crypto.HashHexToHash(nfa.SCID), 2, dero_amount, asset_amount, action, params)
+}
+
+// Load the asset watchlist of the open account, previously found assets are merged in so they are always tracked
+func (t *AssetTracker) load() {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.Loaded || engram.Disk == nil {
+		return
+	}
+
+	t.Height = 0
+	t.Balances = make(map[string]uint64)
+	t.Entries = make(map[string]int)
+	t.Hidden = make(map[string]bool)
+	t.Trusted = make(map[string]bool)
+
+	filters := getAssetFilters()
+	for _, scid := range filters.Hidden {
+		t.Hidden[scid] = true
+	}
+
+	for _, scid := range filters.Trusted {
+		t.Trusted[scid] = true
+	}
+
+	var watchlist AssetWatchlist
+	stored, err := GetEncryptedValue("Asset Tracker", []byte("Watchlist"))
+	if err == nil && len(stored) > 0 {
+		if err = json.Unmarshal(stored, &watchlist); err != nil {
+			logger.Errorf("[Assets] Failed to read watchlist: %s\n", err)
+		} else {
+			t.Height = watchlist.Height
+			for scid, bal := range watchlist.Balances {
+				if !t.Hidden[scid] {
+					t.Balances[scid] = bal
+				}
+			}
+		}
+	}
+
+	if last, err := GetEncryptedValue("Asset Scan", []byte("Last Scan")); err == nil {
+		t.Scan.Last = string(last)
+	}
+
+	keys, _ := GetKeys("My Assets")
+	for _, k := range keys {
+		if t.Hidden[string(k)] {
+			DeleteKey("My Assets", k)
+			continue
+		}
+
+		if _, ok := t.Balances[string(k)]; !ok {
+			t.Balances[string(k)] = 0
+		}
+	}
+
+	t.Loaded = true
+}
+
+// Store the asset watchlist, the caller must hold the lock
+func (t *AssetTracker) save() {
+	data, err := json.Marshal(AssetWatchlist{Height: t.Height, Balances: t.Balances})
+	if err != nil {
+		return
+	}
+
+	if err = StoreEncryptedValue("Asset Tracker", []byte("Watchlist"), data); err != nil {
+		logger.Errorf("[Assets] Failed to store watchlist: %s\n", err)
+	}
+}
+
+// Add a SCID the account has interacted with to the watchlist, its balance is picked up with the next block.
+// Watched assets are added to the wallet so it records their transfers
+func (t *AssetTracker) watch(scid string) {
+	hash := crypto.HashHexToHash(scid)
+	if hash.IsZero() {
+		return
+	}
+
+	t.load()
+
+	t.Lock()
+	defer t.Unlock()
+
+	if !t.Loaded || t.Hidden[scid] {
+		return
+	}
+
+	if disk := engram.Disk; disk != nil {
+		disk.TokenAdd(hash)
+	}
+
+	if _, ok := t.Balances[scid]; !ok {
+		t.Balances[scid] = 0
+		t.save()
+	}
+}
+
+// Get the number of transfers the wallet has recorded for each asset it tracks
+func walletAssetEntries() (entries map[string]int) {
+	entries = make(map[string]int)
+
+	disk := engram.Disk
+	if disk == nil {
+		return
+	}
+
+	disk.Lock()
+	defer disk.Unlock()
+
+	for scid, e := range disk.GetAccount().EntriesNative {
+		if !scid.IsZero() {
+			entries[scid.String()] = len(e)
+		}
+	}
+
+	return
+}
+
+// Set the balance of a watched asset and keep the My Assets tree in step, returns true if the balance changed
+func (t *AssetTracker) set(scid string, bal uint64) (changed bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.Balances == nil || t.Hidden[scid] {
+		return
+	}
+
+	last, ok := t.Balances[scid]
+	if ok && last == bal {
+		return
+	}
+
+	t.Balances[scid] = bal
+
+	if bal > 0 {
+		if err := StoreEncryptedValue("My Assets", []byte(scid), []byte(globals.FormatMoney(bal))); err != nil {
+			logger.Errorf("[Assets] Failed to store asset: %s\n", err)
+		}
+	} else {
+		DeleteKey("My Assets", []byte(scid))
+	}
+
+	return true
+}
+
+// Update the balances of the watched assets only, called as new blocks arrive. The wallet records a transfer
+// for every balance change of the assets it tracks, so only assets with new transfers since the last update are read
+func (t *AssetTracker) update() {
+	t.load()
+
+	t.Lock()
+	if t.Updating || !t.Loaded || engram.Disk == nil {
+		t.Unlock()
+		return
+	}
+	t.Updating = true
+	t.Unlock()
+
+	defer func() {
+		t.Lock()
+		t.Updating = false
+		t.Unlock()
+	}()
+
+	height := engram.Disk.Get_Height()
+	address := engram.Disk.GetAddress().String()
+	entries := walletAssetEntries()
+
+	// Assets the wallet has recorded transfers for, incoming included, are added to the watchlist
+	for scid, n := range entries {
+		if n > 0 {
+			t.watch(scid)
+		}
+	}
+
+	t.Lock()
+	var watched []string
+	for scid := range t.Balances {
+		if disk := engram.Disk; disk != nil {
+			if _, ok := entries[scid]; !ok {
+				// Watched before the wallet tracked it
+				disk.TokenAdd(crypto.HashHexToHash(scid))
+			}
+		}
+
+		if seen, ok := t.Entries[scid]; !ok || seen != entries[scid] {
+			watched = append(watched, scid)
+		}
+	}
+	t.Unlock()
+
+	changed := false
+	for _, scid := range watched {
+		if engram.Disk == nil {
+			return
+		}
+
+		bal, _, err := engram.Disk.GetDecryptedBalanceAtTopoHeight(crypto.HashHexToHash(scid), -1, address)
+		if err != nil {
+			continue
+		}
+
+		if t.set(scid, bal) {
+			changed = true
+		}
+
+		t.Lock()
+		if t.Entries != nil {
+			t.Entries[scid] = entries[scid]
+		}
+		t.Unlock()
+	}
+
+	t.Lock()
+	if t.Balances != nil {
+		t.Height = height
+		t.save()
+	}
+	t.Unlock()
+
+	if changed {
+		t.notify()
+	}
+}
+
+// Scan every indexed SCID for a balance, found assets are added to the watchlist. The scan can be stopped with cancel()
+func (t *AssetTracker) discover() {
+	if gnomon.Index == nil || engram.Disk == nil {
+		return
+	}
+
+	t.load()
+
+	t.Lock()
+	if t.Scan.Active || !t.Loaded {
+		t.Unlock()
+		return
+	}
+
+	cancel := make(chan struct{})
+	t.Scan.Active = true
+	t.Scan.Checked = 0
+	t.Scan.Total = 0
+	t.Scan.Found = 0
+	t.Scan.cancel = cancel
+	t.Unlock()
+	t.notify()
+
+	indexed := gnomon.GetAllOwnersAndSCIDs()
+
+	t.Lock()
+	var contracts []string
+	for scid := range indexed {
+		if !crypto.HashHexToHash(scid).IsZero() && !t.Hidden[scid] {
+			contracts = append(contracts, scid)
+		}
+	}
+	t.Unlock()
+
+	t.Lock()
+	t.Scan.Total = len(contracts)
+	t.Unlock()
+
+	logger.Printf("[Assets] Scanning %d smart contracts for assets\n", len(contracts))
+
+	address := engram.Disk.GetAddress().String()
+	jobs := make(chan string)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < DEFAULT_ASSET_SCAN_WORKERS; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for scid := range jobs {
+				disk := engram.Disk
+				if disk == nil {
+					continue
+				}
+
+				bal, _, err := disk.GetDecryptedBalanceAtTopoHeight(crypto.HashHexToHash(scid), -1, address)
+				if err == nil && bal > 0 {
+					t.Lock()
+					_, watched := t.Balances[scid]
+					t.Unlock()
+
+					if !watched {
+						logger.Printf("[Assets] Found asset: %s\n", scid)
+					}
+					t.set(scid, bal)
+				}
+
+				t.Lock()
+				t.Scan.Checked++
+				if err == nil && bal > 0 {
+					t.Scan.Found++
+				}
+				checked := t.Scan.Checked
+				t.Unlock()
+
+				if checked%DEFAULT_ASSET_SCAN_WORKERS == 0 {
+					t.notify()
+				}
+			}
+		}()
+	}
+
+	cancelled := false
+send:
+	for _, scid := range contracts {
+		select {
+		case <-cancel:
+			cancelled = true
+			break send
+		case jobs <- scid:
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	t.Lock()
+	t.Scan.Active = false
+	t.Scan.cancel = nil
+	if !cancelled && t.Balances != nil {
+		t.Scan.Last = time.Now().Format(time.RFC822)
+		t.save()
+	}
+	last := t.Scan.Last
+	t.Unlock()
+
+	if cancelled {
+		logger.Printf("[Assets] Asset scan cancelled\n")
+	} else if engram.Disk != nil {
+		StoreEncryptedValue("Asset Scan", []byte("Last Scan"), []byte(last))
+	}
+
+	t.notify()
+}
+
+// Hide an asset, hidden assets are dropped from the watchlist, the My Assets tree and discovery scans
+func (t *AssetTracker) hide(scid string) (err error) {
+	t.load()
+
+	t.Lock()
+	defer t.Unlock()
+
+	if !t.Loaded {
+		err = errors.New("error: no active account found")
+		return
+	}
+
+	t.Hidden[scid] = true
+	delete(t.Balances, scid)
+	DeleteKey("My Assets", []byte(scid))
+	t.save()
+
+	return t.saveFilters()
+}
+
+// Show a hidden asset again, it is added back to the watchlist
+func (t *AssetTracker) unhide(scid string) (err error) {
+	t.load()
+
+	t.Lock()
+	if !t.Loaded {
+		t.Unlock()
+		err = errors.New("error: no active account found")
+		return
+	}
+
+	delete(t.Hidden, scid)
+	err = t.saveFilters()
+	t.Unlock()
+
+	t.watch(scid)
+	go t.update()
+
+	return
+}
+
+// Add or remove an asset from the trusted list
+func (t *AssetTracker) trust(scid string, trusted bool) (err error) {
+	t.load()
+
+	t.Lock()
+	defer t.Unlock()
+
+	if !t.Loaded {
+		err = errors.New("error: no active account found")
+		return
+	}
+
+	if trusted {
+		t.Trusted[scid] = true
+	} else {
+		delete(t.Trusted, scid)
+	}
+
+	return t.saveFilters()
+}
+
+// Check if an asset is hidden or trusted
+func (t *AssetTracker) filtered(scid string) (hidden bool, trusted bool) {
+	t.load()
+
+	t.Lock()
+	defer t.Unlock()
+
+	return t.Hidden[scid], t.Trusted[scid]
+}
+
+// Get the hidden assets, sorted by SCID
+func (t *AssetTracker) hidden() (scids []string) {
+	t.load()
+
+	t.Lock()
+	defer t.Unlock()
+
+	for scid := range t.Hidden {
+		scids = append(scids, scid)
+	}
+
+	sort.Strings(scids)
+	return
+}
+
+// Store the hidden and trusted lists, the caller must hold the lock
+func (t *AssetTracker) saveFilters() error {
+	var filters AssetFilters
+	for scid := range t.Hidden {
+		filters.Hidden = append(filters.Hidden, scid)
+	}
+
+	for scid := range t.Trusted {
+		filters.Trusted = append(filters.Trusted, scid)
+	}
+
+	sort.Strings(filters.Hidden)
+	sort.Strings(filters.Trusted)
+
+	return setAssetFilters(filters)
+}
+
+// Get the hidden and trusted asset lists of the account
+func getAssetFilters() (filters AssetFilters) {
+	stored, err := GetEncryptedValue("Asset Tracker", []byte("Filters"))
+	if err != nil || len(stored) == 0 {
+		return
+	}
+
+	if err = json.Unmarshal(stored, &filters); err != nil {
+		logger.Errorf("[Assets] Loading filters: %s\n", err)
+		filters = AssetFilters{}
+	}
+
+	return
+}
+
+// Store the hidden and trusted asset lists of the account
+func setAssetFilters(filters AssetFilters) (err error) {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return
+	}
+
+	return StoreEncryptedValue("Asset Tracker", []byte("Filters"), data)
+}
+
+// Check if the metadata of a contract has no headers to identify it
+func (m AssetMetadata) unverified() bool {
+	return m.Name == "" && m.Description == "" && m.Icon == ""
+}
+
+// Stop an active discovery scan
+func (t *AssetTracker) cancel() {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.Scan.cancel != nil {
+		close(t.Scan.cancel)
+		t.Scan.cancel = nil
+	}
+}
+
+// Get the progress of the discovery scan
+func (t *AssetTracker) progress() (scan AssetScan) {
+	t.Lock()
+	defer t.Unlock()
+
+	scan = t.Scan
+	scan.cancel = nil
+	return
+}
+
+// Get the watched assets with a balance, sorted by SCID
+func (t *AssetTracker) owned() (scids []string, balances map[string]uint64) {
+	t.Lock()
+	defer t.Unlock()
+
+	balances = make(map[string]uint64)
+	for scid, bal := range t.Balances {
+		if bal > 0 {
+			scids = append(scids, scid)
+			balances[scid] = bal
+		}
+	}
+
+	sort.Strings(scids)
+	return
+}
+
+// Call the tracker update function on the UI thread
+func (t *AssetTracker) notify() {
+	t.Lock()
+	update := t.OnUpdate
+	t.Unlock()
+
+	if update != nil {
+		fyne.Do(update)
+	}
+}
+
+// Drop the watchlist when an account is closed, an active scan is cancelled
+func (t *AssetTracker) reset() {
+	t.Lock()
+	if t.Scan.cancel != nil {
+		close(t.Scan.cancel)
+	}
+	t.Loaded = false
+	t.Height = 0
+	t.Balances = nil
+	t.Entries = nil
+	t.Hidden = nil
+	t.Trusted = nil
+	t.Scan = AssetScan{}
+	t.OnUpdate = nil
+	t.Unlock()
+}
+
+// Parse airdrop recipients, one "address,amount" per line with amounts in the decimals of the asset. Usernames are resolved and lines starting with # are skipped
+func parseAirdropRecipients(data string, decimals uint64) (recipients []AirdropRecipient, total uint64, err error) {
+	for n, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ';' || r == '\t' || r == ' '
+		})
+
+		if len(fields) != 2 {
+			err = fmt.Errorf("line %d: expected an address and an amount", n+1)
+			return
+		}
+
+		address := fields[0]
+		if _, e := globals.ParseValidateAddress(address); e != nil {
+			resolved, e := checkUsername(address, -1)
+			if e != nil || resolved == "" {
+				err = fmt.Errorf("line %d: invalid username or address %s", n+1, address)
+				return
+			}
+			address = resolved
+		}
+
+		amount, e := parseAssetAmount(fields[1], decimals)
+		if e != nil || amount == 0 {
+			err = fmt.Errorf("line %d: invalid amount %s", n+1, fields[1])
+			return
+		}
+
+		if total+amount < total {
+			err = fmt.Errorf("line %d: total amount overflows", n+1)
+			return
+		}
+
+		total += amount
+		recipients = append(recipients, AirdropRecipient{Address: address, Amount: amount, Status: AIRDROP_STATUS_PENDING})
+	}
+
+	if len(recipients) == 0 {
+		err = errors.New("no recipients found")
+	}
+
+	return
+}
+
+// Get the stored airdrop job for an asset
+func getAirdrop(scid string) (job Airdrop, err error) {
+	stored, err := GetEncryptedValue("Airdrops", []byte(scid))
+	if err != nil {
+		return
+	}
+
+	if len(stored) == 0 {
+		err = errors.New("no airdrop found")
+		return
+	}
+
+	err = json.Unmarshal(stored, &job)
+	return
+}
+
+// Store an airdrop job so it can be resumed after a restart
+func storeAirdrop(job Airdrop) (err error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return
+	}
+
+	return StoreEncryptedValue("Airdrops", []byte(job.SCID), data)
+}
+
+// Delete the stored airdrop job for an asset
+func deleteAirdrop(scid string) (err error) {
+	return DeleteKey("Airdrops", []byte(scid))
+}
+
+// Count the airdrop recipients by status
+func (j Airdrop) count(status string) (count int) {
+	for _, r := range j.Recipients {
+		if r.Status == status {
+			count++
+		}
+	}
+
+	return
+}
+
+// Build a CSV report of the airdrop with the TXID of each recipient
+func (j Airdrop) report(decimals uint64) []byte {
+	var b strings.Builder
+	b.WriteString("address,amount,status,txid,error\n")
+	for _, r := range j.Recipients {
+		b.WriteString(fmt.Sprintf("%s,%s,%s,%s,%s\n", r.Address, formatAssetAmount(r.Amount, decimals), r.Status, r.TXID, strings.ReplaceAll(r.Error, ",", " ")))
+	}
+
+	return []byte(b.String())
+}
+
+// Start or resume the airdrop job of an asset, unfinished batches from a previous run are checked before sending more
+func (d *AirdropRunner) start(job Airdrop) (err error) {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.Active {
+		err = errors.New("an airdrop is already running")
+		return
+	}
+
+	if job.BatchSize < 1 {
+		job.BatchSize = DEFAULT_AIRDROP_BATCH_SIZE
+	}
+
+	if err = storeAirdrop(job); err != nil {
+		return
+	}
+
+	d.Active = true
+	d.Job = job
+	d.stop = make(chan struct{})
+	go d.process(d.stop)
+
+	return
+}
+
+// Pause the running airdrop after the current batch
+func (d *AirdropRunner) pause() {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+}
+
+// Get a copy of the current airdrop job and whether it is running
+func (d *AirdropRunner) status() (job Airdrop, active bool) {
+	d.Lock()
+	defer d.Unlock()
+
+	job = d.Job
+	job.Recipients = append([]AirdropRecipient{}, d.Job.Recipients...)
+	return job, d.Active
+}
+
+// Send the pending recipients in batches, each batch must be confirmed before the next one is built
+func (d *AirdropRunner) process(stop chan struct{}) {
+	defer func() {
+		d.Lock()
+		d.Active = false
+		d.Unlock()
+		d.notify()
+	}()
+
+	if err := d.recover(); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-stop:
+			logger.Printf("[Airdrop] Paused\n")
+			return
+		default:
+		}
+
+		if engram.Disk == nil {
+			return
+		}
+
+		d.Lock()
+		job := d.Job
+		var batch []int
+		for i, r := range job.Recipients {
+			if r.Status == AIRDROP_STATUS_PENDING {
+				batch = append(batch, i)
+				if len(batch) >= job.BatchSize {
+					break
+				}
+			}
+		}
+		d.Unlock()
+
+		if len(batch) == 0 {
+			logger.Printf("[Airdrop] Completed %s\n", job.SCID)
+			return
+		}
+
+		var transfers []rpc.Transfer
+		scid := crypto.HashHexToHash(job.SCID)
+		for _, i := range batch {
+			transfers = append(transfers, rpc.Transfer{SCID: scid, Amount: job.Recipients[i].Amount, Destination: job.Recipients[i].Address})
+		}
+
+		tx, err := engram.Disk.TransferPayload0(transfers, job.Ringsize, false, rpc.Arguments{}, 0, false)
+		if err != nil {
+			logger.Errorf("[Airdrop] Failed to build transaction: %s\n", err)
+			d.update(batch, AIRDROP_STATUS_FAILED, "", err.Error())
+			return
+		}
+
+		// The TXID is stored before sending so a restart can tell if the batch went out
+		txid := tx.GetHash().String()
+		d.update(batch, AIRDROP_STATUS_SENDING, txid, "")
+
+		if err = engram.Disk.SendTransaction(tx); err != nil {
+			logger.Errorf("[Airdrop] Failed to send transaction: %s\n", err)
+			d.update(batch, AIRDROP_STATUS_FAILED, txid, err.Error())
+			return
+		}
+
+		logger.Printf("[Airdrop] Sent batch of %d - TXID: %s\n", len(batch), txid)
+
+		if err = waitTxConfirmation(txid); err != nil {
+			logger.Errorf("[Airdrop] Batch %s: %s\n", txid, err)
+			d.update(batch, AIRDROP_STATUS_FAILED, txid, err.Error())
+			return
+		}
+
+		d.update(batch, AIRDROP_STATUS_SENT, txid, "")
+		assets.watch(job.SCID)
+	}
+}
+
+// Settle batches that were sending when the airdrop stopped, confirmed batches are marked sent and only batches the daemon
+// does not know are sent again. If the daemon cannot be asked the batch stays sending and the airdrop is stopped
+func (d *AirdropRunner) recover() (err error) {
+	d.Lock()
+	sending := make(map[string][]int)
+	for i, r := range d.Job.Recipients {
+		if r.Status == AIRDROP_STATUS_SENDING {
+			sending[r.TXID] = append(sending[r.TXID], i)
+		}
+	}
+	d.Unlock()
+
+	for txid, batch := range sending {
+		var unknown bool
+		if unknown, err = txUnknown(txid); err != nil {
+			logger.Errorf("[Airdrop] Checking batch %s: %s\n", txid, err)
+			return
+		}
+
+		if unknown {
+			d.update(batch, AIRDROP_STATUS_PENDING, "", "")
+			continue
+		}
+
+		if e := waitTxConfirmation(txid); e != nil {
+			d.update(batch, AIRDROP_STATUS_FAILED, txid, e.Error())
+		} else {
+			d.update(batch, AIRDROP_STATUS_SENT, txid, "")
+		}
+	}
+
+	return
+}
+
+// Set the status of a batch of recipients and store the job
+func (d *AirdropRunner) update(batch []int, status string, txid string, message string) {
+	d.Lock()
+	for _, i := range batch {
+		if i < len(d.Job.Recipients) {
+			d.Job.Recipients[i].Status = status
+			d.Job.Recipients[i].TXID = txid
+			d.Job.Recipients[i].Error = message
+		}
+	}
+
+	if engram.Disk != nil {
+		if err := storeAirdrop(d.Job); err != nil {
+			logger.Errorf("[Airdrop] Failed to store progress: %s\n", err)
+		}
+	}
+	d.Unlock()
+	d.notify()
+}
+
+// Call the airdrop update function on the UI thread
+func (d *AirdropRunner) notify() {
+	d.Lock()
+	update := d.OnUpdate
+	d.Unlock()
+
+	if update != nil {
+		fyne.Do(update)
+	}
+}
+
+// Stop the airdrop when an account is closed, the stored job is resumed from the asset manager
+func (d *AirdropRunner) reset() {
+	d.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.Job = Airdrop{}
+	d.OnUpdate = nil
+	d.Unlock()
+}
+
+// Parse an asset amount using the decimals of its contract
+func parseAssetAmount(s string, decimals uint64) (amount uint64, err error) {
+	decimals = min(decimals, MAX_ASSET_DECIMALS)
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if uint64(len(fraction)) > decimals {
+		err = fmt.Errorf("amount has more than %d decimals", decimals)
+		return
+	}
+
+	amount, err = strconv.ParseUint(whole+fraction+strings.Repeat("0", int(decimals)-len(fraction)), 10, 64)
+
+	return
+}
+
+// Format an asset amount using the decimals of its contract
+func formatAssetAmount(amount uint64, decimals uint64) string {
+	// A uint64 has at most 20 digits, contracts storing more decimals than that are not shown as such
+	decimals = min(decimals, MAX_ASSET_DECIMALS)
+	if decimals == 0 {
+		return strconv.FormatUint(amount, 10)
+	}
+
+	s := strconv.FormatUint(amount, 10)
+	if uint64(len(s)) <= decimals {
+		s = strings.Repeat("0", int(decimals)-len(s)+1) + s
+	}
+
+	return s[:uint64(len(s))-decimals] + "." + s[uint64(len(s))-decimals:]
+}
+
+// Get the incoming and outgoing transfers of an asset, DERO is used when scid is zero. Assets are added to the wallet so their history is synced
+func getTransferHistory(scid crypto.Hash) (entries []rpc.Entry) {
+	if engram.Disk == nil {
+		return
+	}
+
+	if !scid.IsZero() {
+		if err := engram.Disk.TokenAdd(scid); err == nil {
+			if err = engram.Disk.Sync_Wallet_Memory_With_Daemon_internal(scid); err != nil {
+				logger.Errorf("[History] Syncing %s: %s\n", scid, err)
+			}
+		}
+	}
+
+	for _, e := range engram.Disk.Show_Transfers(scid, false, true, true, 0, engram.Disk.Get_Height(), "", "", 0, 0) {
+		if !e.Coinbase {
+			entries = append(entries, e)
+		}
+	}
+
+	return
+}
+
+// Export the transfer history of an asset as CSV, amounts are formatted with the asset decimals
+func exportTransferHistory(scid crypto.Hash, decimals uint64) (result []byte, err error) {
+	if engram.Disk == nil {
+		err = errors.New("no account is open")
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("date,height,direction,amount,fees,txid,destination\n")
+	for _, e := range getTransferHistory(scid) {
+		direction := "Received"
+		if !e.Incoming {
+			direction = "Sent"
+		}
+
+		b.WriteString(fmt.Sprintf("%s,%d,%s,%s,%s,%s,%s\n", e.Time.Format(time.RFC3339), e.Height, direction, formatAssetAmount(e.Amount, decimals), globals.FormatMoney(e.Fees), e.TXID, e.Destination))
+	}
+
+	result = []byte(b.String())
+	return
+}
+
+// Send an asset from one account to another
+func transferAsset(scid crypto.Hash, ringsize uint64, address string, amount string) (txid crypto.Hash, err error) {
+	var amount_to_transfer uint64
+
+	if amount == "" {
+		amount = ".00001"
+	}
+
+	amount_to_transfer, err = globals.ParseAmount(amount)
+	if err != nil {
+		logger.Errorf("[Transfer] Failed parsing transfer amount: %s\n", err)
+		return
+	}
+
+	tx, err := engram.Disk.TransferPayload0([]rpc.Transfer{{SCID: scid, Amount: amount_to_transfer, Destination: address}}, ringsize, false, rpc.Arguments{}, 0, false)
+	if err != nil {
+		logger.Errorf("[Transfer] Failed to build transaction: %s\n", err)
+		return
+	}
+
+	if err = engram.Disk.SendTransaction(tx); err != nil {
+		logger.Errorf("[Transfer] Failed to send asset: %s - %s\n", scid, err)
+		return
+	}
+
+	txid = tx.GetHash()
+	assets.watch(scid.String())
+
+	logger.Printf("[Transfer] Successfully sent asset: %s - TXID: %s\n", scid, tx.GetHash().String())
+	return
+}
+
+// Transfer a username to another account
+func transferUsername(username string, address string) (storage uint64, err error) {
+	var args = rpc.Arguments{}
+	var dest string
+
+	scid := crypto.HashHexToHash("0000000000000000000000000000000000000000000000000000000000000001")
+
+	args = append(args, rpc.Argument{Name: "entrypoint", DataType: "S", Value: "TransferOwnership"})
+	args = append(args, rpc.Argument{Name: "SC_ID", DataType: "H", Value: scid})
+	args = append(args, rpc.Argument{Name: "SC_ACTION", DataType: "U", Value: uint64(rpc.SC_CALL)})
+	args = append(args, rpc.Argument{Name: "name", DataType: "S", Value: username})
+	args = append(args, rpc.Argument{Name: "newowner", DataType: "S", Value: address})
+
+	switch session.Network {
+	case NETWORK_MAINNET:
+		dest = "dero1qykyta6ntpd27nl0yq4xtzaf4ls6p5e9pqu0k2x4x3pqq5xavjsdxqgny8270"
+	case NETWORK_SIMULATOR:
+		dest = "deto1qyvyeyzrcm2fzf6kyq7egkes2ufgny5xn77y6typhfx9s7w3mvyd5qqynr5hx"
+	default:
+		dest = "deto1qy0ehnqjpr0wxqnknyc66du2fsxyktppkr8m8e6jvplp954klfjz2qqdzcd8p"
+	}
+
+	transfer := rpc.Transfer{
+		Destination: dest,
+		Amount:      0,
+		Burn:        0,
+	}
+
+	gasParams := rpc.GasEstimate_Params{
+		SC_RPC:    args,
+		SC_Value:  0,
+		Ringsize:  2,
+		Signer:    engram.Disk.GetAddress().String(),
+		Transfers: []rpc.Transfer{transfer},
+	}
+
+	storage, err = getGasEstimate(gasParams)
+	if err != nil {
+		logger.Errorf("[%s] Error estimating fees: %s\n", "TransferOwnership", err)
+		return
+	}
+
+	tx, err := engram.Disk.TransferPayload0([]rpc.Transfer{transfer}, 2, false, args, storage, false)
+	if err != nil {
+		logger.Errorf("[%s] Error while building transaction: %s\n", "TransferOwnership", err)
+		return
+	}
+
+	txid := tx.GetHash().String()
+
+	err = engram.Disk.SendTransaction(tx)
+	if err != nil {
+		logger.Errorf("[%s] Error while dispatching transaction: %s\n", "TransferOwnership", err)
+		return
+	}
+
+	walletapi.WaitNewHeightBlock()
+	logger.Printf("[%s] Username transfer successful - TXID:  %s\n", "TransferOwnership", txid)
+	_ = tx
+
+	return
+}
+
+// Derive the ABI of a contract, every exported function with its parameter types and whether it needs SIGNER, DEROVALUE or ASSETVALUE
+func getContractABI(code string) (abi []ContractFunction, err error) {
+	contract, pos, err := dvm.ParseSmartContract(code)
+	if err != nil {
+		err = fmt.Errorf("error parsing contract %s", pos)
+		return
+	}
+
+	for name, function := range contract.Functions {
+		if name == "Initialize" || name == "InitializePrivate" || !unicode.IsUpper(rune(name[0])) {
+			continue
+		}
+
+		f := ContractFunction{
+			Name:       name,
+			Signer:     contractFunctionCalls(contract, name, "SIGNER", map[string]bool{}),
+			DEROValue:  contractFunctionCalls(contract, name, "DEROVALUE", map[string]bool{}),
+			AssetValue: contractFunctionCalls(contract, name, "ASSETVALUE", map[string]bool{}),
+		}
+
+		for _, p := range function.Params {
+			// A Uint64 named value is always filled with DEROVALUE by the DVM
+			if p.Type == dvm.Uint64 && p.Name == "value" {
+				f.DEROValue = true
+				continue
+			}
+
+			f.Params = append(f.Params, ContractParam{Name: p.Name, Type: contractParamType(function, p)})
+		}
+
+		abi = append(abi, f)
+	}
+
+	sort.Slice(abi, func(i, j int) bool {
+		return abi[i].Name < abi[j].Name
+	})
+
+	return
+}
+
+// Check if a contract function calls a DVM function, directly or through the internal functions it calls
+func contractFunctionCalls(contract dvm.SmartContract, name string, call string, seen map[string]bool) bool {
+	if seen[name] {
+		return false
+	}
+	seen[name] = true
+
+	for _, line := range contract.Functions[name].Lines {
+		for i := 0; i+1 < len(line); i++ {
+			if line[i+1] != "(" {
+				continue
+			}
+
+			if line[i] == call {
+				return true
+			}
+
+			if _, ok := contract.Functions[line[i]]; ok && contractFunctionCalls(contract, line[i], call, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Get the ABI type of a contract function parameter. The DVM only knows Uint64 and String, so a string is only typed
+// as an address or hex when the code passes it straight to ADDRESS_RAW or HEXDECODE, anything else stays a String
+func contractParamType(function dvm.Function, param dvm.Variable) string {
+	if param.Type == dvm.Uint64 {
+		return CONTRACT_PARAM_UINT64
+	}
+
+	for _, line := range function.Lines {
+		for i := 0; i+2 < len(line); i++ {
+			if line[i+1] != "(" || line[i+2] != param.Name {
+				continue
+			}
+
+			switch line[i] {
+			case "ADDRESS_RAW":
+				return CONTRACT_PARAM_ADDRESS
+			case "HEXDECODE":
+				return CONTRACT_PARAM_HEX
+			}
+		}
+	}
+
+	return CONTRACT_PARAM_STRING
+}
+
+// Validate a contract call argument for its ABI type and convert it to a DVM variable, hashes and addresses are passed as strings
+func (p ContractParam) parse(value string) (v dvm.Variable, err error) {
+	v.Name = p.Name
+	v.Type = dvm.String
+
+	switch p.Type {
+	case CONTRACT_PARAM_UINT64:
+		v.Type = dvm.Uint64
+		if v.ValueUint64, err = strconv.ParseUint(value, 10, 64); err != nil {
+			err = fmt.Errorf("%s must be a whole number", p.Name)
+		}
+	case CONTRACT_PARAM_HEX:
+		if _, e := hex.DecodeString(value); e != nil {
+			err = fmt.Errorf("%s must be hex encoded", p.Name)
+		}
+		v.ValueString = value
+	case CONTRACT_PARAM_ADDRESS:
+		if _, e := globals.ParseValidateAddress(value); e != nil {
+			err = fmt.Errorf("%s must be a valid address", p.Name)
+		}
+		v.ValueString = value
+	default:
+		v.ValueString = value
+	}
+
+	return
+}
+
+// Get the saved call presets of a contract
+func getContractPresets(scid string) (presets []ContractPreset, err error) {
+	stored, err := GetEncryptedValue("Contract Presets", []byte(scid))
+	if err != nil || len(stored) == 0 {
+		return
+	}
+
+	err = json.Unmarshal(stored, &presets)
+	return
+}
+
+// Save a call preset for a contract, a preset with the same name for the function is replaced
+func storeContractPreset(scid string, preset ContractPreset) (err error) {
+	presets, err := getContractPresets(scid)
+	if err != nil {
+		return
+	}
+
+	replaced := false
+	for i := range presets {
+		if presets[i].Function == preset.Function && presets[i].Name == preset.Name {
+			presets[i] = preset
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		presets = append(presets, preset)
+	}
+
+	data, err := json.Marshal(presets)
+	if err != nil {
+		return
+	}
+
+	return StoreEncryptedValue("Contract Presets", []byte(scid), data)
+}
+
+// Delete a call preset of a contract function
+func deleteContractPreset(scid string, function string, name string) (err error) {
+	presets, err := getContractPresets(scid)
+	if err != nil {
+		return
+	}
+
+	var kept []ContractPreset
+	for _, p := range presets {
+		if p.Function != function || p.Name != name {
+			kept = append(kept, p)
+		}
+	}
+
+	if len(kept) == 0 {
+		return DeleteKey("Contract Presets", []byte(scid))
+	}
+
+	data, err := json.Marshal(kept)
+	if err != nil {
+		return
+	}
+
+	return StoreEncryptedValue("Contract Presets", []byte(scid), data)
+}
+
+// Execute arbitrary exportable smart contract functions
+func executeContractFunction(scid crypto.Hash, ringsize uint64, dero_amount uint64, asset_amount uint64, funcName string, params []dvm.Variable) (storage uint64, err error) {
+	var args = rpc.Arguments{}
+	var zero uint64
+	var dest string
+
+	args = append(args, rpc.Argument{Name: "entrypoint", DataType: "S", Value: funcName})
+	args = append(args, rpc.Argument{Name: "SC_ID", DataType: "H", Value: scid})
+	args = append(args, rpc.Argument{Name: "SC_ACTION", DataType: "U", Value: uint64(rpc.SC_CALL)})
+
+	for p := range params {
+		if params[p].Type == 0x4 {
+			args = append(args, rpc.Argument{Name: params[p].Name, DataType: "U", Value: params[p].ValueUint64})
+		} else {
+			args = append(args, rpc.Argument{Name: params[p].Name, DataType: "S", Value: params[p].ValueString})
+		}
+	}
+
+	switch session.Network {
+	case NETWORK_MAINNET:
+		dest = "dero1qykyta6ntpd27nl0yq4xtzaf4ls6p5e9pqu0k2x4x3pqq5xavjsdxqgny8270"
+	case NETWORK_SIMULATOR:
+		dest = "deto1qyvyeyzrcm2fzf6kyq7egkes2ufgny5xn77y6typhfx9s7w3mvyd5qqynr5hx"
+	default:
+		dest = "deto1qy0ehnqjpr0wxqnknyc66du2fsxyktppkr8m8e6jvplp954klfjz2qqdzcd8p"
+	}
+
+	var transfers []rpc.Transfer
+
+	if dero_amount != zero {
+		burn := dero_amount
+
+		transfer := rpc.Transfer{
+			Destination: dest,
+			Amount:      0,
+			Burn:        burn,
+		}
+
+		transfers = append(transfers, transfer)
+	}
+	if asset_amount != zero {
+		burn := asset_amount
+
+		transfer := rpc.Transfer{
+			SCID:        scid,
+			Destination: dest,
+			Amount:      0,
+			Burn:        burn,
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	if len(transfers) < 1 {
+		transfer := rpc.Transfer{
+			Destination: dest,
+			Amount:      0,
+			Burn:        0,
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	gasParams := rpc.GasEstimate_Params{
+		SC_RPC:    args,
+		SC_Value:  0,
+		Ringsize:  ringsize,
+		Signer:    engram.Disk.GetAddress().String(),
+		Transfers: transfers,
+	}
+
+	storage, err = getGasEstimate(gasParams)
+	if err != nil {
+		logger.Errorf("[%s] Error estimating fees: %s\n", funcName, err)
+		return
+	}
+
+	tx, err := engram.Disk.TransferPayload0(transfers, ringsize, false, args, storage, false)
+	if err != nil {
+		logger.Errorf("[%s] Error while building transaction: %s\n", funcName, err)
+		return
+	}
+
+	err = engram.Disk.SendTransaction(tx)
+	if err != nil {
+		logger.Errorf("[%s] Error while dispatching transaction: %s\n", funcName, err)
+		return
+	}
+
+	assets.watch(scid.String())
+
+	walletapi.WaitNewHeightBlock()
+	logger.Printf("[%s] Function execution successful - TXID:  %s\n", funcName, tx.GetHash().String())
+	_ = tx
+
+	return
+}
+
+// Find the exported entrypoint that calls UPDATE_SC_CODE and the String parameter that takes the new code
+func contractUpdateEntrypoint(code string) (entrypoint string, param string, ok bool) {
+	contract, _, err := dvm.ParseSmartContract(code)
+	if err != nil {
+		return
+	}
 
-	categorySelect.OnChanged = func(s string) {
-		if positiveSelect.SelectedIndex() > -1 && negativeSelect.SelectedIndex() > -1 {
-			btnConfirm.Enable()
+	var names []string
+	for name := range contract.Functions {
+		if name == "Initialize" || name == "InitializePrivate" || !unicode.IsUpper(rune(name[0])) {
+			continue
 		}
+		names = append(names, name)
+	}
 
-		if categorySelect.SelectedIndex() > 4 {
-			overlayCont.Objects[17] = container.NewCenter(
-				container.NewStack(
-					span,
-					container.NewBorder(
-						nil,
-						nil,
-						container.NewStack(
-							selectSpacer,
-							detailHeader,
-						),
-						nil,
+	// UpdateCode is the common name, so prefer it when several entrypoints qualify
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] == "UpdateCode" || (names[j] != "UpdateCode" && names[i] < names[j])
+	})
 
-						positiveSelect,
-					),
-				),
-			)
-			positiveSelect.SetSelectedIndex(0)
-			ratingText.ParseMarkdown(fmt.Sprintf("%d", (categorySelect.SelectedIndex()*10)+positiveSelect.SelectedIndex()))
-		} else {
-			overlayCont.Objects[17] = container.NewCenter(
-				container.NewStack(
-					span,
-					container.NewBorder(
-						nil,
-						nil,
-						container.NewStack(
-							selectSpacer,
-							detailHeader,
-						),
-						nil,
-						negativeSelect,
-					),
-				),
-			)
-			negativeSelect.SetSelectedIndex(0)
-			ratingText.ParseMarkdown(fmt.Sprintf("%d", (categorySelect.SelectedIndex()*10)+negativeSelect.SelectedIndex()))
+	for _, name := range names {
+		if !contractFunctionCalls(contract, name, "UPDATE_SC_CODE", map[string]bool{}) {
+			continue
+		}
+
+		for _, p := range contract.Functions[name].Params {
+			if p.Type == dvm.String {
+				return name, p.Name, true
+			}
 		}
 	}
 
-	positiveSelect.OnChanged = func(s string) {
-		if categorySelect.SelectedIndex() > -1 {
-			btnConfirm.Enable()
-			ratingText.ParseMarkdown(fmt.Sprintf("%d", (categorySelect.SelectedIndex()*10)+positiveSelect.SelectedIndex()))
+	return
+}
+
+// Line diff of two versions of contract code, lines are prefixed with "- " when removed, "+ " when added and "  " when unchanged
+func diffContractCode(current string, updated string) (diff []string) {
+	a := strings.Split(strings.ReplaceAll(current, "\r\n", "\n"), "\n")
+	b := strings.Split(strings.ReplaceAll(updated, "\r\n", "\n"), "\n")
+
+	// Longest common subsequence table of the remaining lines
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
 		}
 	}
 
-	negativeSelect.OnChanged = func(s string) {
-		if categorySelect.SelectedIndex() > -1 {
-			btnConfirm.Enable()
-			ratingText.ParseMarkdown(fmt.Sprintf("%d", (categorySelect.SelectedIndex()*10)+negativeSelect.SelectedIndex()))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
 		}
 	}
 
-	overlay.Add(
-		container.NewStack(
-			&iframe{},
-			canvas.NewRectangle(colors.DarkMatter),
-		),
-	)
+	for ; i < len(a); i++ {
+		diff = append(diff, "- "+a[i])
+	}
 
-	overlay.Add(
-		container.NewStack(
-			&iframe{},
-			container.NewCenter(
-				overlayCont,
-			),
-		),
-	)
+	for ; j < len(b); j++ {
+		diff = append(diff, "+ "+b[j])
+	}
+
+	return
 }
 
-// Install a new smart contract
-func installSC(code string, args []rpc.Argument) (txid string, err error) {
+// Build the arguments and transfer of a contract code update call
+func contractUpdateTransfer(scid crypto.Hash, entrypoint string, param string, code string) (args rpc.Arguments, transfers []rpc.Transfer) {
 	var dest string
 	switch session.Network {
 	case NETWORK_MAINNET:
 		dest = "dero1qykyta6ntpd27nl0yq4xtzaf4ls6p5e9pqu0k2x4x3pqq5xavjsdxqgny8270"
 	case NETWORK_SIMULATOR:
 		dest = "deto1qyvyeyzrcm2fzf6kyq7egkes2ufgny5xn77y6typhfx9s7w3mvyd5qqynr5hx"
-	case NETWORK_TESTNET:
+	default:
 		dest = "deto1qy0ehnqjpr0wxqnknyc66du2fsxyktppkr8m8e6jvplp954klfjz2qqdzcd8p"
 	}
 
-	transfer := rpc.Transfer{
-		Destination: dest,
-		Amount:      0,
-		Burn:        0,
-	}
+	args = append(args, rpc.Argument{Name: "entrypoint", DataType: "S", Value: entrypoint})
+	args = append(args, rpc.Argument{Name: "SC_ID", DataType: "H", Value: scid})
+	args = append(args, rpc.Argument{Name: "SC_ACTION", DataType: "U", Value: uint64(rpc.SC_CALL)})
+	args = append(args, rpc.Argument{Name: param, DataType: "S", Value: code})
 
-	_, err = transfer.Payload_RPC.CheckPack(transaction.PAYLOAD0_LIMIT)
-	if err != nil {
-		logger.Errorf("[Engram] Install arguments packing err: %s\n", err)
-		err = fmt.Errorf("contract install pack error")
-		return
-	}
+	transfers = append(transfers, rpc.Transfer{Destination: dest, Amount: 0, Burn: 0})
 
-	// decode SC from base64 if possible
-	if sc, err := base64.StdEncoding.DecodeString(code); err == nil {
-		code = string(sc)
+	return
+}
+
+// Estimate the storage gas of a contract code update, the owner has to sign so the ringsize is always 2
+func estimateContractUpdate(scid crypto.Hash, entrypoint string, param string, code string) (gas uint64, err error) {
+	if engram.Disk == nil {
+		err = errors.New("no account is open")
+		return
 	}
 
-	args = append(args, rpc.Argument{Name: rpc.SCACTION, DataType: rpc.DataUint64, Value: uint64(rpc.SC_INSTALL)})
-	args = append(args, rpc.Argument{Name: rpc.SCCODE, DataType: rpc.DataString, Value: code})
+	args, transfers := contractUpdateTransfer(scid, entrypoint, param, code)
 
-	fees := uint64(0)
-	gasParams := rpc.GasEstimate_Params{
-		Transfers: []rpc.Transfer{transfer},
-		SC_Code:   code,
-		SC_Value:  0,
+	return getGasEstimate(rpc.GasEstimate_Params{
 		SC_RPC:    args,
+		SC_Value:  0,
 		Ringsize:  2,
 		Signer:    engram.Disk.GetAddress().String(),
-	}
+		Transfers: transfers,
+	})
+}
 
-	if gas, err := getGasEstimate(gasParams); err == nil {
-		fees = gas
-		logger.Printf("[Engram] SC install fees: %d\n", fees)
-	} else {
-		// uses default fees
-		logger.Errorf("[Engram] Error estimating fees: %s\n", err)
+// Send a contract code update with the estimated storage gas, returns the update TXID
+func updateContractCode(scid crypto.Hash, entrypoint string, param string, code string, gas uint64) (txid string, err error) {
+	if engram.Disk == nil {
+		err = errors.New("no account is open")
+		return
 	}
 
-	tx, err := engram.Disk.TransferPayload0([]rpc.Transfer{transfer}, 2, false, args, fees, false)
+	args, transfers := contractUpdateTransfer(scid, entrypoint, param, code)
+
+	tx, err := engram.Disk.TransferPayload0(transfers, 2, false, args, gas, false)
 	if err != nil {
-		logger.Errorf("[Engram] Error while building install transaction: %s\n", err)
-		err = fmt.Errorf("contract install build error")
+		logger.Errorf("[%s] Error while building update transaction: %s\n", entrypoint, err)
+		err = fmt.Errorf("contract update build error")
 		return
 	}
 
 	if err = engram.Disk.SendTransaction(tx); err != nil {
-		logger.Errorf("[Engram] Error while dispatching install transaction: %s\n", err)
-		err = fmt.Errorf("contract install dispatch error")
+		logger.Errorf("[%s] Error while dispatching update transaction: %s\n", entrypoint, err)
+		err = fmt.Errorf("contract update dispatch error")
 		return
 	}
 
 	txid = tx.GetHash().String()
 
-	logger.Printf("[Engram] SC Installed: %s\n", txid)
+	logger.Printf("[%s] Contract code update sent - TXID: %s\n", entrypoint, txid)
 
 	return
 }
 
-// Set the Cyberdeck password
-func newRPCPassword() (s string) {
-	r := make([]byte, 20)
-	_, err := rand.Read(r)
+// Create a local DVM sandbox for contract code, calls run against in-memory state and nothing is sent to the network
+func newDVMSandbox(code string) (sandbox *DVMSandbox, err error) {
+	contract, pos, err := dvm.ParseSmartContract(code)
 	if err != nil {
-		panic(err)
+		err = fmt.Errorf("error parsing contract %s", pos)
+		return
 	}
 
-	s = base64.URLEncoding.EncodeToString(r)
-	cyberdeck.RPC.pass = s
+	sandbox = &DVMSandbox{
+		Contract: contract,
+		Height:   1,
+		Storage:  map[string][]byte{},
+		Balances: map[crypto.Hash]uint64{},
+	}
+
+	if _, err = rand.Read(sandbox.SCID[:]); err != nil {
+		return
+	}
+
+	// The code is stored as it would be on install so LOAD("C") works
+	sandbox.Storage[string(dvm.SC_Code_Key(sandbox.SCID))] = dvm.Variable{Type: dvm.String, ValueString: code}.MarshalBinaryPanic()
+
 	return
 }
 
-// Set the Cyberdeck username
-func newRPCUsername() (s string) {
-	r, _ := rand.Int(rand.Reader, big.NewInt(1600))
-	w := mnemonics.Key_To_Words(r, "english")
-	l := strings.Split(string(w), " ")
-	s = l[len(l)-2]
-	cyberdeck.RPC.user = s
+// Get the initializing entrypoint of the sandbox contract
+func (s *DVMSandbox) initializer() string {
+	if _, ok := s.Contract.Functions["InitializePrivate"]; ok {
+		return "InitializePrivate"
+	}
+
+	return "Initialize"
+}
+
+// Get the exported functions of the sandbox contract that can be called once it is initialized
+func (s *DVMSandbox) functions() (names []string) {
+	for name := range s.Contract.Functions {
+		if name == "Initialize" || name == "InitializePrivate" {
+			continue
+		}
+
+		if unicode.IsUpper(rune(name[0])) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
 	return
 }
 
-// Start an RPC server to allow decentralized application communication
-func toggleRPCServer(port string) {
-	var err error
-	if engram.Disk == nil {
+// Run an entrypoint of the sandbox contract with the given SIGNER, DEROVALUE and ASSETVALUE. Like on chain, storage changes and transfers are only kept when the function returns 0
+func (s *DVMSandbox) run(entrypoint string, signer string, dero uint64, asset crypto.Hash, assetValue uint64, params map[string]string) (result DVMResult) {
+	result.Entrypoint = entrypoint
+
+	function, ok := s.Contract.Functions[entrypoint]
+	if !ok {
+		result.Err = fmt.Errorf("function %s is not available in contract", entrypoint)
 		return
 	}
 
-	if cyberdeck.RPC.server != nil {
-		cyberdeck.RPC.server.RPCServer_Stop()
-		cyberdeck.RPC.server = nil
-		cyberdeck.RPC.status.Text = "Blocked"
-		cyberdeck.RPC.status.Color = colors.Gray
-		cyberdeck.RPC.status.Refresh()
-		cyberdeck.RPC.toggle.Text = "Turn On"
-		cyberdeck.RPC.toggle.Refresh()
-		status.Cyberdeck.FillColor = colors.Gray
-		status.Cyberdeck.StrokeColor = colors.Gray
-		status.Cyberdeck.Refresh()
-		cyberdeck.RPC.userText.Text = cyberdeck.RPC.user
-		cyberdeck.RPC.passText.Text = cyberdeck.RPC.pass
-		cyberdeck.RPC.userText.Enable()
-		cyberdeck.RPC.passText.Enable()
-		logger.Printf("[Engram] RPC server closed\n")
-	} else {
-		logger.Printf("[Engram] Starting RPC server %s\n", port)
+	initializing := entrypoint == "Initialize" || entrypoint == "InitializePrivate"
+	if !s.Installed && !initializing {
+		result.Err = fmt.Errorf("contract must be initialized with %s first", s.initializer())
+		return
+	} else if s.Installed && initializing {
+		result.Err = errors.New("contract is already initialized")
+		return
+	}
 
-		globals.Arguments["--rpc-bind"] = port
+	var signerKey [33]byte
+	if signer != "" {
+		addr, err := rpc.NewAddress(signer)
+		if err != nil {
+			result.Err = fmt.Errorf("invalid SIGNER address %s", signer)
+			return
+		}
+		copy(signerKey[:], addr.Compressed())
+	}
 
-		if cyberdeck.RPC.user == "" {
-			cyberdeck.RPC.user = newRPCUsername()
+	args := map[string]interface{}{}
+	for _, p := range function.Params {
+		if p.Type == dvm.Uint64 && p.Name == "value" {
+			args[p.Name] = strconv.FormatUint(dero, 10)
+			continue
 		}
 
-		if cyberdeck.RPC.pass == "" {
-			cyberdeck.RPC.pass = newRPCPassword()
+		value, ok := params[p.Name]
+		if !ok {
+			result.Err = fmt.Errorf("missing argument %s", p.Name)
+			return
+		}
+		args[p.Name] = value
+	}
+
+	// Work on copies so a failed call leaves the sandbox state untouched
+	storage := make(map[string][]byte, len(s.Storage))
+	for k, v := range s.Storage {
+		storage[k] = v
+	}
+
+	balances := make(map[crypto.Hash]uint64, len(s.Balances))
+	for k, v := range s.Balances {
+		balances[k] = v
+	}
+
+	var zerohash, blid, txid crypto.Hash
+	rand.Read(blid[:])
+	rand.Read(txid[:])
+
+	store := dvm.Initialize_TX_store()
+	state := &dvm.Shared_State{
+		Store:    store,
+		Assets:   map[crypto.Hash]uint64{},
+		RamStore: map[dvm.Variable]dvm.Variable{},
+		SCIDSELF: s.SCID,
+		Chain_inputs: &dvm.Blockchain_Input{
+			BL_HEIGHT:     s.Height,
+			BL_TOPOHEIGHT: s.Height,
+			BL_TIMESTAMP:  uint64(time.Now().Unix()),
+			SCID:          s.SCID,
+			BLID:          blid,
+			TXID:          txid,
+			Signer:        string(signerKey[:]),
+		},
+		GasComputeLimit: DVM_GAS_COMPUTE_LIMIT,
+		GasComputeCheck: true,
+	}
+
+	store.DiskLoader = func(key dvm.DataKey, found *uint64) (v dvm.Variable) {
+		if data, ok := storage[string(key.MarshalBinaryPanic())]; ok && len(data) > 0 {
+			if v.UnmarshalBinary(data) == nil {
+				*found = 1
+			}
 		}
+		return
+	}
+	store.DiskLoaderRaw = func(key []byte) ([]byte, bool) {
+		data, ok := storage[string(key)]
+		return data, ok && len(data) > 0
+	}
+	store.BalanceLoader = func(key dvm.DataKey) uint64 {
+		return balances[key.Asset]
+	}
+	store.SCID = s.SCID
+	store.State = state
 
-		globals.Arguments["--rpc-login"] = cyberdeck.RPC.user + ":" + cyberdeck.RPC.pass
+	if dero > 0 {
+		state.Assets[zerohash] += dero
+		balances[zerohash] += dero
+	}
 
-		cyberdeck.RPC.server, err = rpcserver.RPCServer_Start(engram.Disk, "Cyberdeck")
-		if err != nil {
-			cyberdeck.RPC.server = nil
-			cyberdeck.RPC.status.Text = "Blocked"
-			cyberdeck.RPC.status.Color = colors.Gray
-			cyberdeck.RPC.status.Refresh()
-			cyberdeck.RPC.toggle.Text = "Turn On"
-			cyberdeck.RPC.toggle.Refresh()
-			status.Cyberdeck.FillColor = colors.Gray
-			status.Cyberdeck.StrokeColor = colors.Gray
-			status.Cyberdeck.Refresh()
-			cyberdeck.RPC.userText.Text = cyberdeck.RPC.user
-			cyberdeck.RPC.passText.Text = cyberdeck.RPC.pass
-			cyberdeck.RPC.userText.Enable()
-			cyberdeck.RPC.passText.Enable()
-		} else {
-			cyberdeck.RPC.status.Text = "Allowed"
-			cyberdeck.RPC.status.Color = colors.Green
-			cyberdeck.RPC.status.Refresh()
-			cyberdeck.RPC.toggle.Text = "Turn Off"
-			cyberdeck.RPC.toggle.Refresh()
-			status.Cyberdeck.FillColor = colors.Green
-			status.Cyberdeck.StrokeColor = colors.Green
-			status.Cyberdeck.Refresh()
-			cyberdeck.RPC.userText.Text = cyberdeck.RPC.user
-			cyberdeck.RPC.passText.Text = cyberdeck.RPC.pass
-			cyberdeck.RPC.userText.Disable()
-			cyberdeck.RPC.passText.Disable()
-		}
+	if assetValue > 0 {
+		state.Assets[asset] += assetValue
+		balances[asset] += assetValue
 	}
-}
+	store.BalanceAtStart = balances[zerohash]
 
-// Get the latest smart contract header data (must follow the standard here: https://github.com/civilware/artificer-nfa-standard/blob/main/Headers/README.md)
-func getContractHeader(scid crypto.Hash) (name string, desc string, icon string, owner string, code string) {
-	var headerData []*structures.SCIDVariable
-	var found bool
+	value, err := dvm.RunSmartContract(&s.Contract, entrypoint, state, args)
+	if state.GasComputeUsed > 0 {
+		result.GasCompute = uint64(state.GasComputeUsed)
+	}
+	if state.GasStoreUsed > 0 {
+		result.GasStorage = uint64(state.GasStoreUsed)
+	}
 
-	switch gnomon.Index.DBType {
-	case "gravdb":
-		headerData = gnomon.Index.GravDBBackend.GetAllSCIDVariableDetails(scid.String())
-	case "boltdb":
-		headerData = gnomon.Index.BBSBackend.GetAllSCIDVariableDetails(scid.String())
+	if err != nil {
+		result.Err = err
+		return
+	}
+
+	if value.Type != dvm.Uint64 {
+		result.Err = errors.New("function did not return a number")
+		return
+	}
+
+	result.Return = value.ValueUint64
+	if result.Return != 0 {
+		// Non zero returns discard all changes
+		return
+	}
+
+	for k, v := range store.RawKeys {
+		if len(v) == 0 {
+			delete(storage, k)
+		} else {
+			storage[k] = v
+		}
 	}
-	if headerData == nil {
-		addIndex := make(map[string]*structures.FastSyncImport)
-		addIndex[scid.String()] = &structures.FastSyncImport{}
-		gnomon.Index.AddSCIDToIndex(addIndex, false, true)
-		switch gnomon.Index.DBType {
-		case "gravdb":
-			headerData = gnomon.Index.GravDBBackend.GetAllSCIDVariableDetails(scid.String())
-		case "boltdb":
-			headerData = gnomon.Index.BBSBackend.GetAllSCIDVariableDetails(scid.String())
+
+	for _, t := range store.Transfers[s.SCID].TransferE {
+		// A contract can send its own asset without holding it
+		if t.Asset != s.SCID {
+			if balances[t.Asset] < t.Amount {
+				result.Err = fmt.Errorf("contract sends %d of %s but holds %d", t.Amount, t.Asset, balances[t.Asset])
+				return
+			}
+			balances[t.Asset] -= t.Amount
 		}
+		result.Transfers = append(result.Transfers, t)
 	}
 
-	for _, h := range headerData {
-		switch key := h.Key.(type) {
-		case string:
-			if key == "var_header_name" {
-				found = true
-				name = h.Value.(string)
-			} else if name == "" && key == "nameHdr" {
-				found = true
-				name = h.Value.(string)
-			}
+	s.Storage = storage
+	s.Balances = balances
+	s.Installed = true
+	s.Height++
 
-			if key == "var_header_description" {
-				found = true
-				desc = h.Value.(string)
-			} else if desc == "" && key == "descrHdr" {
-				found = true
-				desc = h.Value.(string)
-			}
+	return
+}
 
-			if key == "var_header_icon" {
-				found = true
-				icon = h.Value.(string)
-			} else if icon == "" && key == "iconURLHdr" {
-				found = true
-				icon = h.Value.(string)
-			}
+// List the balances and storage of the sandbox contract as key = value lines
+func (s *DVMSandbox) storage() (lines []string) {
+	for asset, amount := range s.Balances {
+		name := "DERO"
+		if !asset.IsZero() {
+			name = asset.String()
+		}
 
-			if key == "owner" {
-				owner = h.Value.(string)
-			}
+		lines = append(lines, fmt.Sprintf("balance %s = %d", name, amount))
+	}
 
-			if key == "C" {
-				code = h.Value.(string)
-			}
+	sort.Strings(lines)
+
+	var variables []string
+	for k, v := range s.Storage {
+		var key, value dvm.Variable
+		if key.UnmarshalBinary([]byte(k)) != nil || value.UnmarshalBinary(v) != nil {
+			continue
 		}
-	}
 
-	// Secondary check for headers in Gnomon SC
-	if !found {
-		switch gnomon.Index.DBType {
-		case "gravdb":
-			headerData = gnomon.Index.GravDBBackend.GetAllSCIDVariableDetails(structures.MAINNET_GNOMON_SCID)
-		case "boltdb":
-			headerData = gnomon.Index.BBSBackend.GetAllSCIDVariableDetails(structures.MAINNET_GNOMON_SCID)
-		}
-		if headerData == nil {
-			addIndex := make(map[string]*structures.FastSyncImport)
-			addIndex[structures.MAINNET_GNOMON_SCID] = &structures.FastSyncImport{}
-			gnomon.Index.AddSCIDToIndex(addIndex, false, true)
-			switch gnomon.Index.DBType {
-			case "gravdb":
-				headerData = gnomon.Index.GravDBBackend.GetAllSCIDVariableDetails(structures.MAINNET_GNOMON_SCID)
-			case "boltdb":
-				headerData = gnomon.Index.BBSBackend.GetAllSCIDVariableDetails(structures.MAINNET_GNOMON_SCID)
-			}
+		// The contract code is already in the editor
+		if key.Type == dvm.String && key.ValueString == "C" {
+			continue
 		}
 
-		for _, h := range headerData {
-			if strings.Contains(h.Key.(string), scid.String()) {
-				switch key := h.Key.(type) {
-				case string:
-					if key == scid.String() {
-						query := h.Value.(string)
-						header := strings.Split(query, ";")
-
-						if len(header) > 2 {
-							name = header[0]
-							desc = header[1]
-							icon = header[2]
-						}
-					}
+		variables = append(variables, fmt.Sprintf("%s = %s", formatDVMVariable(key), formatDVMVariable(value)))
+	}
 
-					if key == scid.String()+"owner" {
-						owner = h.Value.(string)
-					}
+	sort.Strings(variables)
+
+	return append(lines, variables...)
+}
+
+// Format a DVM variable for display, raw addresses are shown as addresses and other binary strings as hex
+func formatDVMVariable(v dvm.Variable) string {
+	if v.Type == dvm.Uint64 {
+		return strconv.FormatUint(v.ValueUint64, 10)
+	}
+
+	for _, r := range v.ValueString {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			if len(v.ValueString) == 33 {
+				if addr, err := rpc.NewAddressFromCompressedKeys([]byte(v.ValueString)); err == nil {
+					addr.Mainnet = engram.Disk != nil && engram.Disk.GetNetwork()
+					return addr.String()
 				}
 			}
+
+			return hex.EncodeToString([]byte(v.ValueString))
 		}
 	}
 
-	return
+	return strconv.Quote(v.ValueString)
 }
 
-// Send an asset from one account to another
-func transferAsset(scid crypto.Hash, ringsize uint64, address string, amount string) (txid crypto.Hash, err error) {
-	var amount_to_transfer uint64
+// Get the last indexed height stored in the Gnomon DB of the current network, Gnomon must not be running
+func gnomonIndexHeight(backend string) (height int64, err error) {
+	path := gnomonPath()
+	if backend == "boltdb" {
+		var bbs *storage.BboltStore
+		if bbs, err = storage.NewBBoltDB(path, "gnomon"); err != nil {
+			return
+		}
+		defer bbs.DB.Close()
 
-	if amount == "" {
-		amount = ".00001"
+		return bbs.GetLastIndexHeight()
 	}
 
-	amount_to_transfer, err = globals.ParseAmount(amount)
+	grav, err := storage.NewGravDB(path, "25ms")
 	if err != nil {
-		logger.Errorf("[Transfer] Failed parsing transfer amount: %s\n", err)
 		return
 	}
+	defer grav.DB.Close()
 
-	tx, err := engram.Disk.TransferPayload0([]rpc.Transfer{{SCID: scid, Amount: amount_to_transfer, Destination: address}}, ringsize, false, rpc.Arguments{}, 0, false)
+	return grav.GetLastIndexHeight()
+}
+
+// Get the SHA-256 checksum of a file
+func fileChecksum(path string) (checksum string, err error) {
+	f, err := os.Open(path)
 	if err != nil {
-		logger.Errorf("[Transfer] Failed to build transaction: %s\n", err)
 		return
 	}
+	defer f.Close()
 
-	if err = engram.Disk.SendTransaction(tx); err != nil {
-		logger.Errorf("[Transfer] Failed to send asset: %s - %s\n", scid, err)
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
 		return
 	}
 
-	txid = tx.GetHash()
-
-	logger.Printf("[Transfer] Successfully sent asset: %s - TXID: %s\n", scid, tx.GetHash().String())
+	checksum = hex.EncodeToString(h.Sum(nil))
 	return
 }
 
-// Transfer a username to another account
-func transferUsername(username string, address string) (storage uint64, err error) {
-	var args = rpc.Arguments{}
-	var dest string
+// Export the Gnomon DB of the current network as a gzipped tar archive, the archive begins with a manifest of the index height
+// and the checksum of each file. The SHA-256 checksum of the whole archive is returned to verify copies of it
+func exportGnomonSnapshot(w io.Writer) (snapshot GnomonSnapshot, checksum string, err error) {
+	if gnomon.Index != nil {
+		err = errors.New("gnomon must be stopped to export a snapshot")
+		return
+	}
 
-	scid := crypto.HashHexToHash("0000000000000000000000000000000000000000000000000000000000000001")
+	closeGnomonBackends()
 
-	args = append(args, rpc.Argument{Name: "entrypoint", DataType: "S", Value: "TransferOwnership"})
-	args = append(args, rpc.Argument{Name: "SC_ID", DataType: "H", Value: scid})
-	args = append(args, rpc.Argument{Name: "SC_ACTION", DataType: "U", Value: uint64(rpc.SC_CALL)})
-	args = append(args, rpc.Argument{Name: "name", DataType: "S", Value: username})
-	args = append(args, rpc.Argument{Name: "newowner", DataType: "S", Value: address})
+	path := gnomonPath()
+	profile := getGnomonProfile(session.Network)
 
-	switch session.Network {
-	case NETWORK_MAINNET:
-		dest = "dero1qykyta6ntpd27nl0yq4xtzaf4ls6p5e9pqu0k2x4x3pqq5xavjsdxqgny8270"
-	case NETWORK_SIMULATOR:
-		dest = "deto1qyvyeyzrcm2fzf6kyq7egkes2ufgny5xn77y6typhfx9s7w3mvyd5qqynr5hx"
-	default:
-		dest = "deto1qy0ehnqjpr0wxqnknyc66du2fsxyktppkr8m8e6jvplp954klfjz2qqdzcd8p"
+	snapshot = GnomonSnapshot{
+		Version: GNOMON_SNAPSHOT_VERSION,
+		Network: session.Network,
+		Backend: profile.Backend,
+		Created: time.Now().UTC(),
 	}
 
-	transfer := rpc.Transfer{
-		Destination: dest,
-		Amount:      0,
-		Burn:        0,
+	if snapshot.Height, err = gnomonIndexHeight(profile.Backend); err != nil {
+		return
 	}
 
-	gasParams := rpc.GasEstimate_Params{
-		SC_RPC:    args,
-		SC_Value:  0,
-		Ringsize:  2,
-		Signer:    engram.Disk.GetAddress().String(),
-		Transfers: []rpc.Transfer{transfer},
+	if snapshot.Height < 1 {
+		err = errors.New("there is no indexed data to export")
+		return
 	}
 
-	storage, err = getGasEstimate(gasParams)
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+
+		sum, err := fileChecksum(p)
+		if err != nil {
+			return err
+		}
+
+		snapshot.Files = append(snapshot.Files, GnomonSnapshotFile{Path: filepath.ToSlash(rel), Size: info.Size(), SHA256: sum})
+
+		return nil
+	})
 	if err != nil {
-		logger.Errorf("[%s] Error estimating fees: %s\n", "TransferOwnership", err)
 		return
 	}
 
-	tx, err := engram.Disk.TransferPayload0([]rpc.Transfer{transfer}, 2, false, args, storage, false)
+	manifest, err := json.MarshalIndent(snapshot, "", "  ")
 	if err != nil {
-		logger.Errorf("[%s] Error while building transaction: %s\n", "TransferOwnership", err)
 		return
 	}
 
-	txid := tx.GetHash().String()
+	h := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(w, h))
+	tw := tar.NewWriter(gz)
 
-	err = engram.Disk.SendTransaction(tx)
-	if err != nil {
-		logger.Errorf("[%s] Error while dispatching transaction: %s\n", "TransferOwnership", err)
+	if err = tw.WriteHeader(&tar.Header{Name: GNOMON_SNAPSHOT_MANIFEST, Mode: 0600, Size: int64(len(manifest)), ModTime: snapshot.Created}); err != nil {
 		return
 	}
 
-	walletapi.WaitNewHeightBlock()
-	logger.Printf("[%s] Username transfer successful - TXID:  %s\n", "TransferOwnership", txid)
-	_ = tx
+	if _, err = tw.Write(manifest); err != nil {
+		return
+	}
+
+	for _, file := range snapshot.Files {
+		if err = tw.WriteHeader(&tar.Header{Name: "gnomon/" + file.Path, Mode: 0600, Size: file.Size, ModTime: snapshot.Created}); err != nil {
+			return
+		}
+
+		var f *os.File
+		if f, err = os.Open(filepath.Join(path, filepath.FromSlash(file.Path))); err != nil {
+			return
+		}
+
+		_, err = io.CopyN(tw, f, file.Size)
+		f.Close()
+		if err != nil {
+			return
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return
+	}
+
+	if err = gz.Close(); err != nil {
+		return
+	}
+
+	checksum = hex.EncodeToString(h.Sum(nil))
+	logger.Printf("[Gnomon] Exported %s snapshot at height %d (SHA-256: %s)\n", snapshot.Network, snapshot.Height, checksum)
 
 	return
 }
 
-// Execute arbitrary exportable smart contract functions
-func executeContractFunction(scid crypto.Hash, ringsize uint64, dero_amount uint64, asset_amount uint64, funcName string, params []dvm.Variable) (storage uint64, err error) {
-	var args = rpc.Arguments{}
-	var zero uint64
-	var dest string
+// Import a Gnomon DB snapshot for the current network, the archive must match the expected SHA-256 checksum and every
+// file is verified against the manifest before the local Gnomon data is replaced. The network's indexing profile is set
+// to the backend of the snapshot
+func importGnomonSnapshot(r io.Reader, checksum string) (snapshot GnomonSnapshot, err error) {
+	if gnomon.Index != nil {
+		err = errors.New("gnomon must be stopped to import a snapshot")
+		return
+	}
 
-	args = append(args, rpc.Argument{Name: "entrypoint", DataType: "S", Value: funcName})
-	args = append(args, rpc.Argument{Name: "SC_ID", DataType: "H", Value: scid})
-	args = append(args, rpc.Argument{Name: "SC_ACTION", DataType: "U", Value: uint64(rpc.SC_CALL)})
+	closeGnomonBackends()
 
-	for p := range params {
-		if params[p].Type == 0x4 {
-			args = append(args, rpc.Argument{Name: params[p].Name, DataType: "U", Value: params[p].ValueUint64})
-		} else {
-			args = append(args, rpc.Argument{Name: params[p].Name, DataType: "S", Value: params[p].ValueString})
-		}
+	archive := sha256.New()
+	r = io.TeeReader(r, archive)
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		err = errors.New("invalid snapshot archive")
+		return
 	}
+	defer gz.Close()
 
-	switch session.Network {
-	case NETWORK_MAINNET:
-		dest = "dero1qykyta6ntpd27nl0yq4xtzaf4ls6p5e9pqu0k2x4x3pqq5xavjsdxqgny8270"
-	case NETWORK_SIMULATOR:
-		dest = "deto1qyvyeyzrcm2fzf6kyq7egkes2ufgny5xn77y6typhfx9s7w3mvyd5qqynr5hx"
-	default:
-		dest = "deto1qy0ehnqjpr0wxqnknyc66du2fsxyktppkr8m8e6jvplp954klfjz2qqdzcd8p"
+	tr := tar.NewReader(gz)
+
+	header, err := tr.Next()
+	if err != nil || header.Name != GNOMON_SNAPSHOT_MANIFEST {
+		err = errors.New("snapshot manifest is missing")
+		return
 	}
 
-	var transfers []rpc.Transfer
+	if err = json.NewDecoder(tr).Decode(&snapshot); err != nil {
+		err = errors.New("invalid snapshot manifest")
+		return
+	}
 
-	if dero_amount != zero {
-		burn := dero_amount
+	if snapshot.Version > GNOMON_SNAPSHOT_VERSION {
+		err = fmt.Errorf("snapshot version %d is not supported", snapshot.Version)
+		return
+	}
 
-		transfer := rpc.Transfer{
-			Destination: dest,
-			Amount:      0,
-			Burn:        burn,
-		}
+	if snapshot.Network != session.Network {
+		err = fmt.Errorf("snapshot is for %s, not %s", snapshot.Network, session.Network)
+		return
+	}
 
-		transfers = append(transfers, transfer)
+	if snapshot.Backend != "gravdb" && snapshot.Backend != "boltdb" {
+		err = fmt.Errorf("invalid snapshot backend %q", snapshot.Backend)
+		return
 	}
-	if asset_amount != zero {
-		burn := asset_amount
 
-		transfer := rpc.Transfer{
-			SCID:        scid,
-			Destination: dest,
-			Amount:      0,
-			Burn:        burn,
+	expected := make(map[string]GnomonSnapshotFile)
+	for _, file := range snapshot.Files {
+		expected["gnomon/"+file.Path] = file
+	}
+
+	path := gnomonPath()
+	temp := path + "_import"
+	os.RemoveAll(temp)
+	defer os.RemoveAll(temp)
+
+	// Extract and verify into a temporary directory so a bad archive leaves the current data untouched
+	for {
+		header, err = tr.Next()
+		if err == io.EOF {
+			err = nil
+			break
 		}
 
-		transfers = append(transfers, transfer)
-	}
+		if err != nil {
+			return
+		}
 
-	if len(transfers) < 1 {
-		transfer := rpc.Transfer{
-			Destination: dest,
-			Amount:      0,
-			Burn:        0,
+		file, ok := expected[header.Name]
+		if !ok || header.Typeflag != tar.TypeReg {
+			err = fmt.Errorf("unexpected file in snapshot: %s", header.Name)
+			return
 		}
 
-		transfers = append(transfers, transfer)
+		delete(expected, header.Name)
+
+		dest := filepath.Join(temp, filepath.FromSlash(file.Path))
+		if !strings.HasPrefix(dest, filepath.Clean(temp)+string(os.PathSeparator)) {
+			err = fmt.Errorf("invalid file path in snapshot: %s", file.Path)
+			return
+		}
+
+		if err = os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+			return
+		}
+
+		var f *os.File
+		if f, err = os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600); err != nil {
+			return
+		}
+
+		h := sha256.New()
+		var n int64
+		n, err = io.Copy(io.MultiWriter(f, h), tr)
+		f.Close()
+		if err != nil {
+			return
+		}
+
+		if n != file.Size || hex.EncodeToString(h.Sum(nil)) != file.SHA256 {
+			err = fmt.Errorf("checksum mismatch for %s", file.Path)
+			return
+		}
 	}
 
-	gasParams := rpc.GasEstimate_Params{
-		SC_RPC:    args,
-		SC_Value:  0,
-		Ringsize:  ringsize,
-		Signer:    engram.Disk.GetAddress().String(),
-		Transfers: transfers,
+	if len(expected) > 0 {
+		err = fmt.Errorf("snapshot is missing %d files", len(expected))
+		return
 	}
 
-	storage, err = getGasEstimate(gasParams)
-	if err != nil {
-		logger.Errorf("[%s] Error estimating fees: %s\n", funcName, err)
+	// Read the rest of the archive so the checksum covers all of it
+	if _, err = io.Copy(io.Discard, r); err != nil {
 		return
 	}
 
-	tx, err := engram.Disk.TransferPayload0(transfers, ringsize, false, args, storage, false)
-	if err != nil {
-		logger.Errorf("[%s] Error while building transaction: %s\n", funcName, err)
+	if !strings.EqualFold(hex.EncodeToString(archive.Sum(nil)), strings.TrimSpace(checksum)) {
+		err = errors.New("snapshot checksum does not match")
 		return
 	}
 
-	err = engram.Disk.SendTransaction(tx)
-	if err != nil {
-		logger.Errorf("[%s] Error while dispatching transaction: %s\n", funcName, err)
+	if err = os.RemoveAll(path); err != nil {
 		return
 	}
 
-	walletapi.WaitNewHeightBlock()
-	logger.Printf("[%s] Function execution successful - TXID:  %s\n", funcName, tx.GetHash().String())
-	_ = tx
+	if err = os.Rename(temp, path); err != nil {
+		return
+	}
+
+	profile := getGnomonProfile(session.Network)
+	if profile.Backend != snapshot.Backend {
+		profile.Backend = snapshot.Backend
+		profile.Preset = GNOMON_PROFILE_CUSTOM
+		if err = setGnomonProfile(session.Network, profile); err != nil {
+			return
+		}
+	}
+
+	gnomon.Progress.clear()
+
+	logger.Printf("[Gnomon] Imported %s snapshot at height %d\n", snapshot.Network, snapshot.Height)
 
 	return
 }
 
 // Delete the Gnomon directory
 func cleanGnomonData() error {
-	path := filepath.Join(AppPath(), "datashards", "gnomon")
-	switch session.Network {
-	case NETWORK_TESTNET:
-		path = filepath.Join(AppPath(), "datashards", "gnomon_testnet")
-	case NETWORK_SIMULATOR:
-		path = filepath.Join(AppPath(), "datashards", "gnomon_simulator")
-	}
+	path := gnomonPath()
 
 	dir, err := os.ReadDir(path)
 	if err != nil {
@@ -3305,28 +8244,28 @@ func getTxData(txid string) (result rpc.GetTransaction_Result, err error) {
 
 	params.Tx_Hashes = append(params.Tx_Hashes, txid)
 
-	rpc_client.WS, _, err = websocket.DefaultDialer.Dial("ws://"+session.Daemon+"/ws", nil)
+	// Transactions are looked up from several goroutines at once, so each call uses its own connection instead of rpc_client
+	ws, _, err := websocket.DefaultDialer.Dial("ws://"+session.Daemon+"/ws", nil)
 	if err != nil {
 		return
 	}
+	defer ws.Close()
 
-	input_output := rwc.New(rpc_client.WS)
-	rpc_client.RPC = jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
+	input_output := rwc.New(ws)
+	client := jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
+	defer client.Close()
 
-	if err = rpc_client.RPC.CallResult(context.Background(), "DERO.GetTransaction", params, &result); err != nil {
+	if err = client.CallResult(context.Background(), "DERO.GetTransaction", params, &result); err != nil {
 		logger.Errorf("[Engram] getTxData TXID: %s (Failed: %s)\n", txid, err)
 		return
 	}
 
-	rpc_client.WS.Close()
-	rpc_client.RPC.Close()
-
 	if result.Status != "OK" {
 		logger.Errorf("[Engram] getTxData TXID: %s (Failed: %s)\n", txid, result.Status)
 		return
 	}
 
-	if len(result.Txs_as_hex[0]) < 50 {
+	if len(result.Txs_as_hex) < 1 || len(result.Txs_as_hex[0]) < 50 {
 		return
 	}
 
@@ -3506,6 +8445,8 @@ func toggleXSWD(endpoint string) {
 				cyberdeck.WS.server.SetCustomMethod(method, h)
 			}
 
+			cyberdeck.WS.server.SetCustomMethod("Subscribe", handler.New(Subscribe))
+
 			cyberdeck.WS.server.SetCustomMethod("HandleTELALinks", handler.New(HandleTELALinks))
 
 			cyberdeck.WS.server.SetCustomMethod("AttemptEPOCHWithAddr", handler.New(AttemptEPOCHWithAddr))
@@ -3976,6 +8917,39 @@ func AskPermissionForRequest(ad *xswd.ApplicationData, request *jrpc2.Request) (
 	textParams := widget.NewLabel(params)
 	textParams.Wrapping = fyne.TextWrapWord
 
+	labelUsernames := canvas.NewText("USERNAMES", colors.Gray)
+	labelUsernames.TextSize = 14
+	labelUsernames.Alignment = fyne.TextAlignLeading
+	labelUsernames.TextStyle = fyne.TextStyle{Bold: true}
+	labelUsernames.Hide()
+
+	textUsernames := widget.NewRichTextFromMarkdown("")
+	textUsernames.Wrapping = fyne.TextWrapBreak
+
+	// Show the registered usernames of any addresses in the request parameters
+	addresses := regexp.MustCompile(`\bde(ro|to)i?1[a-z0-9]{50,}`).FindAllString(params, -1)
+	var showUsernames func()
+	showUsernames = func() {
+		var known []string
+		seen := make(map[string]bool)
+		for _, address := range addresses {
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+
+			if username := addressUsername(address, showUsernames); username != "" {
+				known = append(known, "**"+username+"**\n\n"+address)
+			}
+		}
+
+		if len(known) > 0 {
+			textUsernames.ParseMarkdown(strings.Join(known, "\n\n"))
+			labelUsernames.Show()
+		}
+	}
+	showUsernames()
+
 	rectBox := canvas.NewRectangle(color.Transparent)
 	rectBox.SetMinSize(fyne.NewSize(ui.MaxWidth*0.90, ui.MaxHeight*0.48))
 
@@ -4020,6 +8994,9 @@ func AskPermissionForRequest(ad *xswd.ApplicationData, request *jrpc2.Request) (
 						rectSpacer,
 						labelParams,
 						textParams,
+						rectSpacer,
+						labelUsernames,
+						textUsernames,
 					),
 				),
 			),
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/deroproject/derohe/rpc"
+	"github.com/deroproject/derohe/transaction"
+)
+
+// Find the most runes of r that fit in a payload comment, single or as one part of a multipart message
+func maxMessageRunes(t *testing.T, arguments rpc.Arguments, r string, multipart bool) int {
+	t.Helper()
+
+	n := 0
+	for {
+		args := messagePartArguments(arguments, strings.Repeat(r, n+1), 0, 0, 0)
+		if multipart {
+			args = messagePartArguments(arguments, strings.Repeat(r, n+1), math.MaxUint64, DEFAULT_MESSAGE_MAX_PARTS, DEFAULT_MESSAGE_MAX_PARTS)
+		}
+
+		if _, err := args.CheckPack(transaction.PAYLOAD0_LIMIT); err != nil {
+			return n
+		}
+		n++
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	arguments := rpc.Arguments{
+		{Name: rpc.RPC_DESTINATION_PORT, DataType: rpc.DataUint64, Value: uint64(DEFAULT_MESSAGE_PORT)},
+		{Name: rpc.RPC_NEEDS_REPLYBACK_ADDRESS, DataType: rpc.DataString, Value: "dero1qyw4fl3dupcg5qlrcsvcedze507q9u67lxfpu8kgnzp04aq73yheqqg2ctjn4"},
+	}
+
+	single := maxMessageRunes(t, arguments, "a", false)
+	part := maxMessageRunes(t, arguments, "a", true)
+	if single < 1 || part < 1 || part > single {
+		t.Fatalf("unexpected payload capacity: single %d, part %d", single, part)
+	}
+
+	tests := []struct {
+		name  string
+		m     string
+		parts int
+		err   bool
+	}{
+		{name: "short", m: "hello", parts: 1},
+		{name: "single payload boundary", m: strings.Repeat("a", single), parts: 1},
+		{name: "one past single payload", m: strings.Repeat("a", single+1), parts: (single + part) / part},
+		{name: "multi-byte", m: strings.Repeat("é😀", part), parts: -1},
+		{name: "max parts", m: strings.Repeat("a", part*DEFAULT_MESSAGE_MAX_PARTS), parts: DEFAULT_MESSAGE_MAX_PARTS},
+		{name: "too many parts", m: strings.Repeat("a", part*DEFAULT_MESSAGE_MAX_PARTS+1), err: true},
+		{name: "too many multi-byte parts", m: strings.Repeat("😀", part*DEFAULT_MESSAGE_MAX_PARTS), err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := splitMessage(tt.m, arguments)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error, got %d parts", len(parts))
+				}
+				if parts != nil {
+					t.Fatalf("expected no parts with error, got %d", len(parts))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if tt.parts > 0 && len(parts) != tt.parts {
+				t.Fatalf("expected %d parts, got %d", tt.parts, len(parts))
+			}
+
+			if strings.Join(parts, "") != tt.m {
+				t.Fatalf("parts do not join to the message")
+			}
+
+			for i, p := range parts {
+				if !utf8.ValidString(p) {
+					t.Fatalf("part %d is not valid UTF-8", i+1)
+				}
+
+				args := messagePartArguments(arguments, p, 0, 0, 0)
+				if len(parts) > 1 {
+					args = messagePartArguments(arguments, p, math.MaxUint64, uint64(i+1), uint64(len(parts)))
+				}
+
+				if _, err := args.CheckPack(transaction.PAYLOAD0_LIMIT); err != nil {
+					t.Fatalf("part %d does not fit a payload: %s", i+1, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAssembleMessages(t *testing.T) {
+	part := func(txid string, incoming bool, id, p, parts uint64, comment string) IndexedMessage {
+		return IndexedMessage{TXID: txid, Height: p, Incoming: incoming, Comment: comment, Verified: true, ID: id, Part: p, Parts: parts}
+	}
+
+	unverified := part("c", true, 1, 3, 3, "three")
+	unverified.Verified = false
+
+	tests := []struct {
+		name     string
+		msgs     []IndexedMessage
+		comments []string
+		received []uint64
+		verified []bool
+	}{
+		{
+			name:     "single messages",
+			msgs:     []IndexedMessage{{TXID: "a", Comment: "one", Verified: true}, {TXID: "b", Comment: "two"}},
+			comments: []string{"one", "two"},
+			received: []uint64{0, 0},
+			verified: []bool{true, false},
+		},
+		{
+			name:     "out of order",
+			msgs:     []IndexedMessage{part("c", true, 1, 3, 3, "three"), part("a", true, 1, 1, 3, "one"), part("b", true, 1, 2, 3, "two")},
+			comments: []string{"onetwothree"},
+			received: []uint64{3},
+			verified: []bool{true},
+		},
+		{
+			name:     "missing middle part",
+			msgs:     []IndexedMessage{part("a", true, 1, 1, 3, "one"), part("c", true, 1, 3, 3, "three")},
+			comments: []string{"one [part 2 missing] three"},
+			received: []uint64{2},
+			verified: []bool{true},
+		},
+		{
+			name:     "missing first and last parts",
+			msgs:     []IndexedMessage{part("b", true, 1, 2, 3, "two")},
+			comments: []string{" [part 1 missing] two [part 3 missing]"},
+			received: []uint64{1},
+			verified: []bool{true},
+		},
+		{
+			name:     "duplicate part",
+			msgs:     []IndexedMessage{part("a", true, 1, 1, 2, "one"), part("a2", true, 1, 1, 2, "one"), part("b", true, 1, 2, 2, "two")},
+			comments: []string{"onetwo"},
+			received: []uint64{2},
+			verified: []bool{true},
+		},
+		{
+			name:     "unverified part",
+			msgs:     []IndexedMessage{part("a", true, 1, 1, 3, "one"), part("b", true, 1, 2, 3, "two"), unverified},
+			comments: []string{"onetwothree"},
+			received: []uint64{3},
+			verified: []bool{false},
+		},
+		{
+			name:     "same ID in both directions",
+			msgs:     []IndexedMessage{part("a", true, 1, 1, 2, "in1"), part("b", false, 1, 1, 2, "out1"), {TXID: "c", Comment: "single"}, part("d", true, 1, 2, 2, "in2"), part("e", false, 1, 2, 2, "out2")},
+			comments: []string{"in1in2", "out1out2", "single"},
+			received: []uint64{2, 2, 0},
+			verified: []bool{true, true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := assembleMessages(tt.msgs)
+			if len(result) != len(tt.comments) {
+				t.Fatalf("expected %d messages, got %d", len(tt.comments), len(result))
+			}
+
+			for i, m := range result {
+				if m.Comment != tt.comments[i] {
+					t.Errorf("message %d: expected comment %q, got %q", i, tt.comments[i], m.Comment)
+				}
+				if m.Received != tt.received[i] {
+					t.Errorf("message %d: expected %d parts received, got %d", i, tt.received[i], m.Received)
+				}
+				if m.Verified != tt.verified[i] {
+					t.Errorf("message %d: expected verified %t, got %t", i, tt.verified[i], m.Verified)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAssetAmount(t *testing.T) {
+	tests := []struct {
+		s        string
+		decimals uint64
+		amount   uint64
+		err      bool
+	}{
+		{s: "1", decimals: 0, amount: 1},
+		{s: "1", decimals: 5, amount: 100000},
+		{s: " 1.5 ", decimals: 5, amount: 150000},
+		{s: ".5", decimals: 2, amount: 50},
+		{s: "0.00001", decimals: 5, amount: 1},
+		{s: "0.000001", decimals: 5, err: true},
+		{s: "1.5", decimals: 0, err: true},
+		{s: "1.5", decimals: 30, amount: 1500000000000000000},
+		{s: "18446744073709551615", decimals: 0, amount: math.MaxUint64},
+		{s: "18446744073709551616", decimals: 0, err: true},
+		{s: "18446744073709551615", decimals: 1, err: true},
+		{s: "18.446744073709551615", decimals: 18, amount: math.MaxUint64},
+		{s: "19", decimals: 18, err: true},
+		{s: "", decimals: 0, err: true},
+		{s: "-1", decimals: 5, err: true},
+		{s: "1.2.3", decimals: 5, err: true},
+		{s: "1e5", decimals: 5, err: true},
+		{s: "abc", decimals: 5, err: true},
+	}
+
+	for _, tt := range tests {
+		amount, err := parseAssetAmount(tt.s, tt.decimals)
+		if tt.err {
+			if err == nil {
+				t.Errorf("parseAssetAmount(%q, %d): expected error, got %d", tt.s, tt.decimals, amount)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseAssetAmount(%q, %d): unexpected error: %s", tt.s, tt.decimals, err)
+			continue
+		}
+
+		if amount != tt.amount {
+			t.Errorf("parseAssetAmount(%q, %d): expected %d, got %d", tt.s, tt.decimals, tt.amount, amount)
+		}
+	}
+}
+
+func TestFormatAssetAmount(t *testing.T) {
+	tests := []struct {
+		amount   uint64
+		decimals uint64
+		s        string
+	}{
+		{amount: 0, decimals: 0, s: "0"},
+		{amount: 12345, decimals: 0, s: "12345"},
+		{amount: 0, decimals: 5, s: "0.00000"},
+		{amount: 1, decimals: 5, s: "0.00001"},
+		{amount: 100000, decimals: 5, s: "1.00000"},
+		{amount: 12345, decimals: 5, s: "0.12345"},
+		{amount: 123456, decimals: 5, s: "1.23456"},
+		{amount: 1, decimals: 30, s: "0.000000000000000001"},
+		{amount: math.MaxUint64, decimals: 0, s: "18446744073709551615"},
+		{amount: math.MaxUint64, decimals: 18, s: "18.446744073709551615"},
+		{amount: math.MaxUint64, decimals: math.MaxUint64, s: "18.446744073709551615"},
+	}
+
+	for _, tt := range tests {
+		if s := formatAssetAmount(tt.amount, tt.decimals); s != tt.s {
+			t.Errorf("formatAssetAmount(%d, %d): expected %q, got %q", tt.amount, tt.decimals, tt.s, s)
+		}
+
+		// Formatted amounts parse back to the same amount
+		if amount, err := parseAssetAmount(tt.s, tt.decimals); err != nil || amount != tt.amount {
+			t.Errorf("parseAssetAmount(%q, %d): expected %d, got %d (%v)", tt.s, tt.decimals, tt.amount, amount, err)
+		}
+	}
+}
+
+func TestParseAirdropRecipients(t *testing.T) {
+	// Usernames are not resolved with the daemon
+	session.Offline = true
+	defer func() { session.Offline = false }()
+
+	address := "dero1qyw4fl3dupcg5qlrcsvcedze507q9u67lxfpu8kgnzp04aq73yheqqg2ctjn4"
+
+	tests := []struct {
+		name    string
+		data    string
+		amounts []uint64
+		total   uint64
+		err     bool
+	}{
+		{name: "comma", data: address + ",1.5", amounts: []uint64{150}, total: 150},
+		{name: "separators", data: address + ";1\n" + address + "\t2\n" + address + " 3", amounts: []uint64{100, 200, 300}, total: 600},
+		{name: "comments and blank lines", data: "# address,amount\n\n  " + address + ", 1  \r\n\n", amounts: []uint64{100}, total: 100},
+		{name: "empty", data: "\n# nothing\n", err: true},
+		{name: "missing amount", data: address, err: true},
+		{name: "extra field", data: address + ",1,2", err: true},
+		{name: "invalid address", data: "dero1invalid,1", err: true},
+		{name: "unresolved username", data: "unknown-name,1", err: true},
+		{name: "zero amount", data: address + ",0", err: true},
+		{name: "negative amount", data: address + ",-1", err: true},
+		{name: "too many decimals", data: address + ",0.001", err: true},
+		{name: "amount overflow", data: address + ",184467440737095516.16", err: true},
+		{name: "total overflow", data: address + ",184467440737095516.15\n" + address + ",0.01", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipients, total, err := parseAirdropRecipients(tt.data, 2)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error, got %d recipients", len(recipients))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if total != tt.total {
+				t.Errorf("expected total %d, got %d", tt.total, total)
+			}
+
+			if len(recipients) != len(tt.amounts) {
+				t.Fatalf("expected %d recipients, got %d", len(tt.amounts), len(recipients))
+			}
+
+			for i, r := range recipients {
+				if r.Address != address || r.Amount != tt.amounts[i] || r.Status != AIRDROP_STATUS_PENDING {
+					t.Errorf("recipient %d: unexpected %+v", i, r)
+				}
+			}
+		})
+	}
+}
+
+func TestContractUpdateEntrypoint(t *testing.T) {
+	initialize := `Function Initialize() Uint64
+10 STORE("owner", SIGNER())
+20 RETURN 0
+End Function
+`
+
+	update := func(name string, params string) string {
+		return `Function ` + name + `(` + params + `) Uint64
+10 IF LOAD("owner") == SIGNER() THEN GOTO 30
+20 RETURN 1
+30 UPDATE_SC_CODE(code)
+40 RETURN 0
+End Function
+`
+	}
+
+	tests := []struct {
+		name       string
+		code       string
+		entrypoint string
+		param      string
+		ok         bool
+	}{
+		{name: "UpdateCode", code: initialize + update("UpdateCode", "code String"), entrypoint: "UpdateCode", param: "code", ok: true},
+		{name: "other name", code: initialize + update("Upgrade", "code String"), entrypoint: "Upgrade", param: "code", ok: true},
+		{name: "String param after Uint64", code: initialize + update("Upgrade", "version Uint64, code String"), entrypoint: "Upgrade", param: "code", ok: true},
+		{name: "UpdateCode preferred", code: initialize + update("Alpha", "code String") + update("UpdateCode", "code String"), entrypoint: "UpdateCode", param: "code", ok: true},
+		{name: "first by name", code: initialize + update("Zeta", "code String") + update("Beta", "code String"), entrypoint: "Beta", param: "code", ok: true},
+		{
+			name: "through internal function",
+			code: initialize + `Function Update(code String) Uint64
+10 RETURN update(code)
+End Function
+
+Function update(code String) Uint64
+10 UPDATE_SC_CODE(code)
+20 RETURN 0
+End Function
+`,
+			entrypoint: "Update", param: "code", ok: true,
+		},
+		{name: "internal function only", code: initialize + update("update", "code String")},
+		{
+			name: "only in Initialize",
+			code: `Function Initialize() Uint64
+10 UPDATE_SC_CODE("")
+20 RETURN 0
+End Function
+`,
+		},
+		{
+			name: "no UPDATE_SC_CODE",
+			code: initialize + `Function SetName(name String) Uint64
+10 STORE("name", name)
+20 RETURN 0
+End Function
+`,
+		},
+		{
+			name: "no String param",
+			code: initialize + `Function UpdateCode() Uint64
+10 UPDATE_SC_CODE(LOAD("next"))
+20 RETURN 0
+End Function
+`,
+		},
+		{name: "unparsable", code: "Function UpdateCode(code String) Uint64\n10 UPDATE_SC_CODE(code\n"},
+		{name: "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entrypoint, param, ok := contractUpdateEntrypoint(tt.code)
+			if ok != tt.ok || entrypoint != tt.entrypoint || param != tt.param {
+				t.Fatalf("expected (%q, %q, %t), got (%q, %q, %t)", tt.entrypoint, tt.param, tt.ok, entrypoint, param, ok)
+			}
+		})
+	}
+}
+
+func TestDiffContractCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		updated string
+		diff    []string
+	}{
+		{name: "identical", current: "a\nb\nc", updated: "a\nb\nc", diff: []string{"  a", "  b", "  c"}},
+		{name: "line endings", current: "a\r\nb", updated: "a\nb", diff: []string{"  a", "  b"}},
+		{name: "changed line", current: "a\nb\nc", updated: "a\nx\nc", diff: []string{"  a", "- b", "+ x", "  c"}},
+		{name: "added lines", current: "a\nc", updated: "a\nb\nc\nd", diff: []string{"  a", "+ b", "  c", "+ d"}},
+		{name: "removed lines", current: "a\nb\nc\nd", updated: "b\nd", diff: []string{"- a", "  b", "- c", "  d"}},
+		{name: "replaced", current: "a\nb", updated: "c\nd", diff: []string{"- a", "- b", "+ c", "+ d"}},
+		{name: "from empty", current: "", updated: "a", diff: []string{"- ", "+ a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffContractCode(tt.current, tt.updated)
+			if strings.Join(diff, "\n") != strings.Join(tt.diff, "\n") {
+				t.Fatalf("expected:\n%s\ngot:\n%s", strings.Join(tt.diff, "\n"), strings.Join(diff, "\n"))
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
+	"math"
 	"net"
 	"net/url"
 	"os"
@@ -33,7 +34,6 @@ import (
 	"sync"
 	"time"
 	"unicode"
-	"unicode/utf8"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -53,6 +53,7 @@ import (
 	"github.com/deroproject/derohe/dvm"
 	"github.com/deroproject/derohe/globals"
 	"github.com/deroproject/derohe/rpc"
+	"github.com/deroproject/derohe/transaction"
 	"github.com/deroproject/derohe/walletapi"
 	"github.com/deroproject/derohe/walletapi/mnemonics"
 	"github.com/deroproject/derohe/walletapi/xswd"
@@ -1185,8 +1186,15 @@ func layoutServiceAddress() fyne.CanvasObject {
 	wMessage := widget.NewEntry()
 	wMessage.SetPlaceHolder("Message")
 	wMessage.Validator = func(s string) (err error) {
-		bytes := []byte(s)
-		if len(bytes) <= 130 {
+		// Size the comment against the largest values of the other integrated address arguments
+		arguments := rpc.Arguments{
+			{Name: rpc.RPC_NEEDS_REPLYBACK_ADDRESS, DataType: rpc.DataUint64, Value: uint64(1)},
+			{Name: rpc.RPC_VALUE_TRANSFER, DataType: rpc.DataUint64, Value: uint64(math.MaxUint64)},
+			{Name: rpc.RPC_DESTINATION_PORT, DataType: rpc.DataUint64, Value: uint64(math.MaxUint64)},
+			{Name: rpc.RPC_COMMENT, DataType: rpc.DataString, Value: s},
+		}
+
+		if _, err = arguments.CheckPack(transaction.PAYLOAD0_LIMIT); err == nil {
 			tx.Comment = s
 		} else {
 			err = errors.New("message too long")
@@ -3797,6 +3805,28 @@ func layoutMyAssets() fyne.CanvasObject {
 	btnRescan := widget.NewButton("Rescan Blockchain", nil)
 	btnRescan.Disable()
 
+	linkHidden := widget.NewHyperlinkWithStyle("Hidden Assets", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkHidden.OnTapped = func() {
+		overlay := session.Window.Canvas().Overlays()
+		overlay.Add(
+			container.NewStack(
+				&iframe{},
+				canvas.NewRectangle(colors.DarkMatter),
+			),
+		)
+		overlay.Add(
+			container.NewStack(
+				&iframe{},
+				layoutHiddenAssets(func() {
+					overlay.Top().Hide()
+					overlay.Remove(overlay.Top())
+					overlay.Remove(overlay.Top())
+				}),
+			),
+		)
+		overlay.Top().Show()
+	}
+
 	layoutAssets := container.NewStack(
 		rectWidth,
 		container.NewHBox(
@@ -3817,6 +3847,10 @@ func layoutMyAssets() fyne.CanvasObject {
 				rectSpacer,
 				rectSpacer,
 				btnRescan,
+				rectSpacer,
+				container.NewCenter(
+					linkHidden,
+				),
 			),
 			layout.NewSpacer(),
 		),
@@ -3825,343 +3859,158 @@ func layoutMyAssets() fyne.CanvasObject {
 	listing := layoutAssets
 
 	var assetData []string
-	assetCount := 0
-	assetTotal := 0
-	owned := 0
-
-	owned = 0
-	assetData = nil
-	listData.Set(nil)
+	var render sync.Mutex
 
 	if session.Offline {
 		results.Text = "  Asset tracking is disabled in offline mode."
 		results.Color = colors.Gray
 		results.Refresh()
 	} else if gnomon.Index == nil {
-		results.Text = "  Asset tracking is disabled. Gnomon is inactive."
+		results.Text = "  Asset discovery is disabled. Gnomon is inactive."
 		results.Color = colors.Gray
 		results.Refresh()
 	}
 
-	go func() {
-		if engram.Disk != nil && gnomon.Index != nil {
-			if gnomon.Index.LastIndexedHeight < int64(engram.Disk.Get_Daemon_Height()) {
-				fyne.Do(func() {
-					btnRescan.Disable()
-				})
-			} else {
-				fyne.Do(func() {
-					btnRescan.Enable()
-				})
-			}
-
-			results.Text = "  Gathering an index of smart contracts... "
+	// Show the scan progress or the owned asset count
+	showStatus := func() {
+		scan := assets.progress()
+		if scan.Active {
+			results.Text = fmt.Sprintf("  Scanning... %d / %d  (Found: %d)", scan.Checked, scan.Total, scan.Found)
 			results.Color = colors.Yellow
-			results.Refresh()
+			btnRescan.SetText("Cancel Scan")
+		} else {
+			results.Text = fmt.Sprintf("  Owned Assets:  %d", len(assetData))
+			results.Color = colors.Green
+			btnRescan.SetText("Rescan Blockchain")
+		}
 
-			for gnomon.Index.LastIndexedHeight < int64(engram.Disk.Get_Daemon_Height()) {
-				results.Text = fmt.Sprintf("  Gnomon is syncing... [%d / %d]", gnomon.Index.LastIndexedHeight, int64(engram.Disk.Get_Daemon_Height()))
-				results.Color = colors.Yellow
+		if scan.Last != "" {
+			labelLastScan.Text = fmt.Sprintf("  %s", scan.Last)
+		} else {
+			labelLastScan.Text = ""
+		}
 
-				fyne.Do(func() {
-					results.Refresh()
-				})
+		if gnomon.Index != nil && !session.Offline {
+			btnRescan.Enable()
+		} else {
+			btnRescan.Disable()
+		}
 
-				time.Sleep(time.Second * 1)
-			}
+		results.Refresh()
+		labelLastScan.Refresh()
+	}
 
-			results.Text = "  Loading previous scan results..."
-			results.Color = colors.Yellow
+	// Build the owned asset list from the tracker and the asset metadata cache
+	refresh := func() {
+		render.Lock()
+		defer render.Unlock()
 
-			fyne.Do(func() {
-				results.Refresh()
-			})
+		scids, balances := assets.owned()
+		metas := assetMetadata.preload(scids)
 
-			var assetList map[string]string
-			var zerobal uint64
+		// Trusted assets are listed first
+		var data, untrusted []string
+		for _, scid := range scids {
+			meta := metas[scid]
+			_, trusted := assets.filtered(scid)
 
-			shard, err := GetShard()
-			if err != nil {
-				return
+			title := meta.Name
+			if title == "" {
+				title = scid
 			}
 
-			store, err := graviton.NewDiskStore(shard)
-			if err != nil {
-				return
+			if len(title) > 18 {
+				title = title[0:18] + "..."
 			}
 
-			ss, err := store.LoadSnapshot(0)
-
-			if err != nil {
-				return
+			if trusted {
+				title += "  (Trusted)"
+			} else if meta.unverified() {
+				title += "  (Unverified)"
 			}
 
-			tree, err := ss.GetTree("My Assets")
-			if err != nil {
-				return
+			desc := meta.Description
+			if desc == "" {
+				desc = "N/A"
 			}
 
-			c := tree.Cursor()
-
-			for k, _, err := c.First(); err == nil; k, _, err = c.Next() {
-				scid := string(k)
-
-				hash := crypto.HashHexToHash(scid)
-
-				bal, _, err := engram.Disk.GetDecryptedBalanceAtTopoHeight(hash, -1, engram.Disk.GetAddress().String())
-				if err != nil {
-					return
-				} else {
-					title, desc, _, _, _ := getContractHeader(hash)
-
-					if title == "" {
-						title = scid
-					}
-
-					if len(title) > 18 {
-						title = title[0:18] + "..."
-					}
-
-					if desc == "" {
-						desc = "N/A"
-					}
-
-					if len(desc) > 40 {
-						desc = desc[0:40] + "..."
-					}
-
-					balance := globals.FormatMoney(bal)
-					assetData = append(data, balance+";;;"+title+";;;"+desc+";;;;;;"+scid)
-					listData.Set(assetData)
-					owned += 1
-				}
+			if len(desc) > 40 {
+				desc = desc[0:40] + "..."
 			}
 
-			rescan := func() {
-				fyne.Do(func() {
-					btnRescan.Disable()
-				})
-				assetTotal = 0
-				assetCount = 0
-
-				t := time.Now()
-				timeNow := string(t.Format(time.RFC822))
-				StoreEncryptedValue("Asset Scan", []byte("Last Scan"), []byte(timeNow))
-
-				results.Text = "  Indexing..."
-				results.Color = colors.Yellow
-
-				fyne.Do(func() {
-					results.Refresh()
-				})
-
-				owned = 0
-
-				assetData = []string{}
-				listBox.UnselectAll()
-				listData.Set(assetData)
-
-				if gnomon.Index != nil {
-					switch gnomon.Index.DBType {
-					case "gravdb":
-						assetList = gnomon.Index.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						assetList = gnomon.Index.BBSBackend.GetAllOwnersAndSCIDs()
-					}
-
-					for len(assetList) < 5 {
-						logger.Printf("[Gnomon] Asset Scan Status: [%d / %d / %d]\n", gnomon.Index.LastIndexedHeight, engram.Disk.Get_Daemon_Height(), len(assetList))
-						results.Color = colors.Yellow
-						switch gnomon.Index.DBType {
-						case "gravdb":
-							assetList = gnomon.Index.GravDBBackend.GetAllOwnersAndSCIDs()
-						case "boltdb":
-							assetList = gnomon.Index.BBSBackend.GetAllOwnersAndSCIDs()
-						}
-						time.Sleep(time.Second * 5)
-					}
-				}
-
-				results.Text = "  Scanning results..."
-				results.Color = colors.Yellow
-
-				fyne.Do(func() {
-					results.Refresh()
-				})
-
-				if gnomon.Index != nil {
-					switch gnomon.Index.DBType {
-					case "gravdb":
-						assetList = gnomon.Index.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						assetList = gnomon.Index.BBSBackend.GetAllOwnersAndSCIDs()
-					}
-				}
-
-				contracts := []crypto.Hash{}
-
-				for sc := range assetList {
-					scid := crypto.HashHexToHash(sc)
-
-					if !scid.IsZero() {
-						assetCount += 1
-						contracts = append(contracts, scid)
-					}
-				}
-
-				wg := sync.WaitGroup{}
-				maxWorkers := 50
-				lastJob := 0
-
-			parse:
-
-				if lastJob+maxWorkers > len(contracts) {
-					maxWorkers = assetCount - lastJob
-				}
-
-				wg.Add(maxWorkers)
-
-				// Parse each smart contract ID and check for a balance
-				for i := 0; i < maxWorkers; i++ {
-					index := lastJob
-					go func(i int) {
-						defer wg.Done()
-
-						scid := contracts[index]
-
-						desc := ""
-						title := ""
-
-						assetTotal += 1
-
-						results.Text = "  Scanning... " + fmt.Sprintf("%d / %d", assetTotal, assetCount)
-						results.Color = colors.Yellow
-
-						fyne.Do(func() {
-							results.Refresh()
-						})
-
-						bal, _, err := engram.Disk.GetDecryptedBalanceAtTopoHeight(scid, -1, engram.Disk.GetAddress().String())
-						if err != nil {
-							return
-						} else {
-							balance := globals.FormatMoney(bal)
-
-							if bal != zerobal {
-								err = StoreEncryptedValue("My Assets", []byte(scid.String()), []byte(balance))
-								if err != nil {
-									logger.Errorf("[History] Failed to store asset: %s\n", err)
-								}
-
-								title, desc, _, _, _ = getContractHeader(scid)
-
-								if title == "" {
-									title = scid.String()
-								}
-
-								if len(title) > 20 {
-									title = title[0:20] + "..."
-								}
-
-								if desc == "" {
-									desc = "N/A"
-								}
-
-								if len(desc) > 40 {
-									desc = desc[0:40] + "..."
-								}
-
-								owned += 1
-								assetData = append(assetData, balance+";;;"+title+";;;"+desc+";;;;;;"+scid.String())
-								listData.Set(assetData)
-								logger.Printf("[Assets] Found asset: %s\n", scid.String())
-							}
-						}
-					}(i)
-
-					lastJob += 1
-				}
-
-				wg.Wait()
-
-				if lastJob < len(contracts) {
-					goto parse
-				}
-
-				results.Text = fmt.Sprintf("  Owned Assets:  %d", owned)
-				results.Color = colors.Green
+			header := title + ";;;" + desc
+			if trusted {
+				data = append(data, globals.FormatMoney(balances[scid])+";;;"+header+";;;;;;"+scid)
+			} else {
+				untrusted = append(untrusted, globals.FormatMoney(balances[scid])+";;;"+header+";;;;;;"+scid)
+			}
+		}
 
-				labelLastScan.Text = fmt.Sprintf("  %s", timeNow)
-				labelLastScan.Color = colors.Green
+		data = append(data, untrusted...)
 
-				listData.Set(assetData)
-				fyne.Do(func() {
-					btnRescan.Enable()
+		fyne.Do(func() {
+			assetData = data
+			listBox.UnselectAll()
+			listData.Set(assetData)
+			showStatus()
+		})
+	}
 
-					results.Refresh()
-					labelLastScan.Refresh()
-				})
-			}
+	btnRescan.OnTapped = func() {
+		if assets.progress().Active {
+			assets.cancel()
+			return
+		}
 
-			btnRescan.OnTapped = func() {
-				go rescan()
-			}
+		if gnomon.Index == nil || engram.Disk == nil {
+			return
+		}
 
-			lastScan, _ := GetEncryptedValue("Asset Scan", []byte("Last Scan"))
+		if gnomon.Index.LastIndexedHeight < int64(engram.Disk.Get_Daemon_Height()) {
+			results.Text = fmt.Sprintf("  Gnomon is syncing... [%d / %d]", gnomon.Index.LastIndexedHeight, engram.Disk.Get_Daemon_Height())
+			results.Color = colors.Yellow
+			results.Refresh()
+			return
+		}
 
-			if len(assetData) == 0 && len(lastScan) == 0 {
-				rescan()
-			}
+		go assets.discover()
+	}
 
-			if len(lastScan) > 0 {
-				results.Text = fmt.Sprintf("  Owned Assets:  %d", owned)
-				labelLastScan.Text = fmt.Sprintf("  %s", lastScan)
-			} else {
-				results.Text = fmt.Sprintf("  Owned Assets:  %d", owned)
-				labelLastScan.Text = ""
-			}
+	listBox.OnSelected = func(id widget.ListItemID) {
+		if id >= len(assetData) {
+			return
+		}
 
-			results.Color = colors.Green
+		split := strings.Split(assetData[id], ";;;")
 
-			fyne.Do(func() {
-				results.Refresh()
-				labelLastScan.Refresh()
-			})
+		listBox.UnselectAll()
+		session.LastDomain = session.Window.Content()
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutAssetManager(split[4]))
+	}
 
-			listData.Set(assetData)
+	go func() {
+		if engram.Disk == nil || session.Offline {
+			return
+		}
 
-			listBox.OnSelected = func(id widget.ListItemID) {
-				split := strings.Split(assetData[id], ";;;")
+		fyne.Do(func() {
+			results.Text = "  Loading tracked assets..."
+			results.Color = colors.Yellow
+			results.Refresh()
+		})
 
-				/*
-					overlay := session.Window.Canvas().Overlays()
-					overlay.Add(
-						container.NewStack(
-							&iframe{},
-							canvas.NewRectangle(colors.DarkMatter),
-						),
-					)
-					overlay.Add(
-						container.NewStack(
-							&iframe{},
-							layoutAssetManager(split[4]),
-						),
-					)
-					overlay.Top().Show()
-					listBox.UnselectAll()
-				*/
+		assets.load()
+		assets.update()
+		refresh()
 
-				listBox.UnselectAll()
-				session.LastDomain = session.Window.Content()
-				session.Window.SetContent(layoutTransition())
-				session.Window.SetContent(layoutAssetManager(split[4]))
+		// Only run a full discovery scan automatically the first time, later balances are updated block by block
+		scan := assets.progress()
+		scids, _ := assets.owned()
+		if scan.Last == "" && len(scids) == 0 && !scan.Active && gnomon.Index != nil {
+			if gnomon.Index.LastIndexedHeight >= int64(engram.Disk.Get_Daemon_Height()) {
+				go assets.discover()
 			}
-
-			fyne.Do(func() {
-				listBox.Refresh()
-
-				btnRescan.Enable()
-			})
 		}
 	}()
 
@@ -4217,26 +4066,139 @@ func layoutMyAssets() fyne.CanvasObject {
 		),
 	)
 
-	return NewVScroll(layout)
-}
+	scroll := NewVScroll(layout)
 
-func layoutAssetManager(scid string) fyne.CanvasObject {
-	captureDomain := session.Domain
-	session.Domain = "app.manager"
+	update := func() {
+		if session.Window.Content() != scroll {
+			return
+		}
 
-	wSpacer := widget.NewLabel(" ")
+		go refresh()
+	}
 
-	frame := &iframe{}
+	assets.Lock()
+	assets.OnUpdate = update
+	assets.Unlock()
 
-	rectBox := canvas.NewRectangle(color.Transparent)
-	rectBox.SetMinSize(fyne.NewSize(ui.MaxWidth*0.99, ui.MaxHeight*0.58))
-	rectWidth90 := canvas.NewRectangle(color.Transparent)
-	rectWidth90.SetMinSize(fyne.NewSize(ui.Width, 10))
+	assetMetadata.Lock()
+	assetMetadata.OnUpdate = update
+	assetMetadata.Unlock()
 
-	heading := canvas.NewText("Asset Manager", colors.Green)
-	heading.TextSize = 22
-	heading.Alignment = fyne.TextAlignCenter
-	heading.TextStyle = fyne.TextStyle{Bold: true}
+	return scroll
+}
+
+// List of the hidden assets, selecting one opens it in the asset manager where it can be unhidden
+func layoutHiddenAssets(done func()) *fyne.Container {
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
+
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.4))
+
+	header := canvas.NewText("HIDDEN  ASSETS", colors.Gray)
+	header.TextSize = 14
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
+
+	hidden := assets.hidden()
+	titles := make([]string, len(hidden))
+
+	summary := canvas.NewText(fmt.Sprintf("%d hidden assets", len(hidden)), colors.Gray)
+	summary.TextSize = 13
+	summary.Alignment = fyne.TextAlignCenter
+
+	listHidden := widget.NewList(
+		func() int {
+			return len(hidden)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			title := titles[id]
+			if title == "" {
+				title = hidden[id]
+			}
+
+			if len(title) > 36 {
+				title = title[0:36] + "..."
+			}
+
+			co.(*widget.Label).SetText(title)
+		},
+	)
+
+	// Load the asset names before they are shown, the list shows SCIDs until then
+	go func() {
+		metas := assetMetadata.preload(hidden)
+
+		fyne.Do(func() {
+			for i, scid := range hidden {
+				titles[i] = metas[scid].Name
+			}
+			listHidden.Refresh()
+		})
+	}()
+	listHidden.OnSelected = func(id widget.ListItemID) {
+		listHidden.UnselectAll()
+		removeOverlays()
+		session.LastDomain = session.Window.Content()
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutAssetManager(hidden[id]))
+	}
+
+	linkClose := widget.NewHyperlinkWithStyle("Close", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClose.OnTapped = func() {
+		done()
+	}
+
+	return container.NewCenter(
+		container.NewVBox(
+			span,
+			container.NewCenter(
+				header,
+			),
+			rectSpacer,
+			rectSpacer,
+			summary,
+			rectSpacer,
+			container.NewStack(
+				rectList,
+				listHidden,
+			),
+			rectSpacer,
+			rectSpacer,
+			container.NewHBox(
+				layout.NewSpacer(),
+				linkClose,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			rectSpacer,
+		),
+	)
+}
+
+func layoutAssetManager(scid string) fyne.CanvasObject {
+	captureDomain := session.Domain
+	session.Domain = "app.manager"
+
+	wSpacer := widget.NewLabel(" ")
+
+	frame := &iframe{}
+
+	rectBox := canvas.NewRectangle(color.Transparent)
+	rectBox.SetMinSize(fyne.NewSize(ui.MaxWidth*0.99, ui.MaxHeight*0.58))
+	rectWidth90 := canvas.NewRectangle(color.Transparent)
+	rectWidth90.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	heading := canvas.NewText("Asset Manager", colors.Green)
+	heading.TextSize = 22
+	heading.Alignment = fyne.TextAlignCenter
+	heading.TextStyle = fyne.TextStyle{Bold: true}
 
 	rectSpacer := canvas.NewRectangle(color.Transparent)
 	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
@@ -4320,6 +4282,11 @@ func layoutAssetManager(scid string) fyne.CanvasObject {
 	hash := crypto.HashHexToHash(scid)
 	name, desc, icon, owner, code := getContractHeader(hash)
 
+	nfaPanel := container.NewVBox()
+	if assetMetadata.get(scid).Standard == ASSET_STANDARD_NFA {
+		nfaPanel = layoutNFA(scid)
+	}
+
 	image := canvas.NewImageFromResource(resourceBlankPng)
 	image.SetMinSize(fyne.NewSize(ui.Width*0.3, ui.Width*0.3))
 	image.FillMode = canvas.ImageFillContain
@@ -4394,11 +4361,11 @@ func layoutAssetManager(scid string) fyne.CanvasObject {
 
 	textSigner := widget.NewRichTextFromMarkdown(owner)
 	textSigner.Wrapping = fyne.TextWrapWord
-	textSigner.ParseMarkdown(signer)
+	setAddressMarkdown(textSigner, signer)
 
 	textOwner := widget.NewRichTextFromMarkdown(owner)
 	textOwner.Wrapping = fyne.TextWrapWord
-	textOwner.ParseMarkdown(owner)
+	setAddressMarkdown(textOwner, owner)
 
 	btnSend := widget.NewButton("Send Asset", nil)
 
@@ -4525,10 +4492,7 @@ func layoutAssetManager(scid string) fyne.CanvasObject {
 
 				bal, _, err := engram.Disk.GetDecryptedBalanceAtTopoHeight(hash, -1, engram.Disk.GetAddress().String())
 				if err == nil {
-					err = StoreEncryptedValue("My Assets", []byte(hash.String()), []byte(globals.FormatMoney(bal)))
-					if err != nil {
-						logger.Errorf("[Asset] Error storing new asset balance for: %s\n", hash)
-					}
+					assets.set(hash.String(), bal)
 					balance.Text = "  " + globals.FormatMoney(bal)
 					balance.Refresh()
 				}
@@ -4657,378 +4621,228 @@ func layoutAssetManager(scid string) fyne.CanvasObject {
 		}
 	}
 
-	linkCopySCID := widget.NewHyperlinkWithStyle("Copy SCID", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
-	linkCopySCID.OnTapped = func() {
-		a.Clipboard().SetContent(scid)
+	linkAirdrop := widget.NewHyperlinkWithStyle("Airdrop to Many Recipients", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	linkAirdrop.OnTapped = func() {
+		overlay := session.Window.Canvas().Overlays()
+		overlay.Add(
+			container.NewStack(
+				&iframe{},
+				canvas.NewRectangle(colors.DarkMatter),
+			),
+		)
+		overlay.Add(
+			container.NewStack(
+				&iframe{},
+				layoutAirdrop(scid, name, func() {
+					overlay.Top().Hide()
+					overlay.Remove(overlay.Top())
+					overlay.Remove(overlay.Top())
+				}),
+			),
+		)
+		overlay.Top().Show()
 	}
 
-	linkView := widget.NewHyperlinkWithStyle("View in Explorer", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
-	linkView.OnTapped = func() {
-		if engram.Disk.GetNetwork() {
-			link, _ := url.Parse("https://explorer.derofoundation.org/tx/" + scid)
-			_ = fyne.CurrentApp().OpenURL(link)
-		} else {
-			link, _ := url.Parse("https://testnetexplorer.derofoundation.org/tx/" + scid)
-			_ = fyne.CurrentApp().OpenURL(link)
-		}
+	linkHistory := widget.NewHyperlinkWithStyle("View Asset History", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	linkHistory.OnTapped = func() {
+		overlay := session.Window.Canvas().Overlays()
+		overlay.Add(
+			container.NewStack(
+				&iframe{},
+				canvas.NewRectangle(colors.DarkMatter),
+			),
+		)
+		overlay.Add(
+			container.NewStack(
+				&iframe{},
+				layoutAssetHistory(scid, name, func() {
+					overlay.Top().Hide()
+					overlay.Remove(overlay.Top())
+					overlay.Remove(overlay.Top())
+				}),
+			),
+		)
+		overlay.Top().Show()
 	}
 
-	// Now let's parse the smart contract code for exported functions
-
-	var contract dvm.SmartContract
-	var signerFunctions []string
-	var deroFunctions []string
-	var assetFunctions []string
-
-	contract, _, err = dvm.ParseSmartContract(code)
-	if err != nil {
-		contract = dvm.SmartContract{}
+	// Owners can airdrop, and an unfinished airdrop stays reachable once the balance is spent
+	if _, err := getAirdrop(scid); bal == zerobal && err != nil {
+		linkAirdrop.Hide()
 	}
 
-	data := []string{}
+	linkCopySCID := widget.NewHyperlinkWithStyle("Copy SCID", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	linkCopySCID.OnTapped = func() {
+		a.Clipboard().SetContent(scid)
+	}
 
-	for f := range contract.Functions {
-		r, _ := utf8.DecodeRuneInString(contract.Functions[f].Name)
+	hidden, trusted := assets.filtered(scid)
+
+	labelBadge := canvas.NewText("", colors.Yellow)
+	labelBadge.TextSize = 13
+	labelBadge.Alignment = fyne.TextAlignCenter
+	labelBadge.TextStyle = fyne.TextStyle{Bold: true}
+
+	linkHide := widget.NewHyperlinkWithStyle("", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	linkTrust := widget.NewHyperlinkWithStyle("", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	// Show the hidden and trusted state of the asset, contracts without headers are unverified unless trusted
+	showFilters := func() {
+		switch {
+		case hidden:
+			labelBadge.Text = "HIDDEN ASSET"
+			labelBadge.Color = colors.Gray
+		case trusted:
+			labelBadge.Text = "TRUSTED ASSET"
+			labelBadge.Color = colors.Green
+		case assetMetadata.get(scid).unverified():
+			labelBadge.Text = "UNVERIFIED CONTRACT"
+			labelBadge.Color = colors.Yellow
+		default:
+			labelBadge.Text = ""
+		}
+		labelBadge.Refresh()
 
-		if !unicode.IsUpper(r) {
-			logger.Debugf("[DVM] Function %s is not an exported function - skipping it\n", contract.Functions[f].Name)
-		} else if contract.Functions[f].Name == "Initialize" || contract.Functions[f].Name == "InitializePrivate" {
-			logger.Debugf("[DVM] Function %s is an initialization function - skipping it\n", contract.Functions[f].Name)
+		if hidden {
+			linkHide.SetText("Unhide Asset")
 		} else {
-			data = append(data, contract.Functions[f].Name)
+			linkHide.SetText("Hide Asset")
 		}
 
-		for l := range contract.Functions[f].Lines {
-			for i := range contract.Functions[f].Lines[l] {
-				if contract.Functions[f].Lines[l][i] == "SIGNER" && contract.Functions[f].Lines[l][i+1] == "(" {
-					signerFunctions = append(signerFunctions, contract.Functions[f].Name)
-				}
-
-				if contract.Functions[f].Lines[l][i] == "DEROVALUE" && contract.Functions[f].Lines[l][i+1] == "(" {
-					deroFunctions = append(deroFunctions, contract.Functions[f].Name)
-				}
-
-				if contract.Functions[f].Lines[l][i] == "ASSETVALUE" && contract.Functions[f].Lines[l][i+1] == "(" {
-					assetFunctions = append(assetFunctions, contract.Functions[f].Name)
-				}
-			}
+		if trusted {
+			linkTrust.SetText("Remove from Trusted")
+		} else {
+			linkTrust.SetText("Mark as Trusted")
 		}
 	}
 
-	sort.Strings(data)
-	data = append(data, " ")
-
-	var paramList []fyne.Widget
-	var dero_amount uint64
-	var asset_amount uint64
+	linkHide.OnTapped = func() {
+		var err error
+		if hidden {
+			err = assets.unhide(scid)
+		} else {
+			err = assets.hide(scid)
+		}
 
-	functionList := widget.NewSelect(data, nil)
-	functionList.OnChanged = func(s string) {
-		if s == " " {
-			functionList.ClearSelected()
+		if err != nil {
+			logger.Errorf("[Assets] Updating hidden assets: %s\n", err)
 			return
 		}
 
-		var params []dvm.Variable
-
-		overlay := session.Window.Canvas().Overlays()
+		hidden = !hidden
+		showFilters()
+	}
 
-		options := []string{"Anonymity Set:   2  (None)", "Anonymity Set:   4  (Low)", "Anonymity Set:   8  (Low)", "Anonymity Set:   16  (Recommended)", "Anonymity Set:   32  (Medium)", "Anonymity Set:   64  (High)", "Anonymity Set:   128  (High)"}
+	linkTrust.OnTapped = func() {
+		if err := assets.trust(scid, !trusted); err != nil {
+			logger.Errorf("[Assets] Updating trusted assets: %s\n", err)
+			return
+		}
 
-		var ringsize uint64
+		trusted = !trusted
+		showFilters()
+	}
 
-		signerRequired := false
+	showFilters()
 
-		selectRingMembers := widget.NewSelect(options, nil)
-		selectRingMembers.PlaceHolder = "(Select Anonymity Set)"
+	linkUpdate := widget.NewHyperlinkWithStyle("Update Contract Code", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	loadingCode := false
+	linkUpdate.OnTapped = func() {
+		if loadingCode {
+			return
+		}
 
-		for f := range contract.Functions {
-			if contract.Functions[f].Name == s {
-				params = contract.Functions[f].Params
+		loadingCode = true
+		linkUpdate.SetText("Loading contract code...")
+		capture := session.Window.Content()
 
-				header := canvas.NewText("EXECUTE  CONTRACT  FUNCTION", colors.Gray)
-				header.TextSize = 14
-				header.Alignment = fyne.TextAlignCenter
-				header.TextStyle = fyne.TextStyle{Bold: true}
+		go func() {
+			current, err := getContractCode(scid)
 
-				funcName := canvas.NewText(s, colors.Account)
-				funcName.TextSize = 22
-				funcName.Alignment = fyne.TextAlignCenter
-				funcName.TextStyle = fyne.TextStyle{Bold: true}
+			fyne.Do(func() {
+				loadingCode = false
 
-				linkClose := widget.NewHyperlinkWithStyle("Close", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-				linkClose.OnTapped = func() {
-					dero_amount = 0
-					asset_amount = 0
-					overlay.Top().Hide()
-					overlay.Remove(overlay.Top())
-					overlay.Remove(overlay.Top())
+				// The asset was closed while the code loaded
+				if session.Window.Content() != capture {
+					return
 				}
 
-				span := canvas.NewRectangle(color.Transparent)
-				span.SetMinSize(fyne.NewSize(ui.Width, 10))
-
-				overlay.Add(
-					container.NewStack(
-						&iframe{},
-						canvas.NewRectangle(colors.DarkMatter),
-					),
-				)
-
-				entryDEROValue := widget.NewEntry()
-				entryDEROValue.PlaceHolder = "DERO Amount (Numbers Only)"
-				entryDEROValue.Validator = func(s string) error {
-					dero_amount, err = globals.ParseAmount(s)
-					if err != nil {
-						entryDEROValue.SetValidationError(err)
-						return err
-					}
-
-					return nil
+				if err != nil || current == "" {
+					logger.Errorf("[Engram] Update SC: cannot get contract code %v\n", err)
+					linkUpdate.SetText("Could not load contract code")
+					return
 				}
 
-				entryAssetValue := widget.NewEntry()
-				entryAssetValue.PlaceHolder = "Asset Amount (Numbers Only)"
-				entryAssetValue.Validator = func(s string) error {
-					asset_amount, err = globals.ParseAmount(s)
-					if err != nil {
-						entryAssetValue.SetValidationError(err)
-						return err
-					}
-
-					return nil
-				}
+				removeOverlays()
+				session.Window.SetContent(layoutTransition())
+				session.Window.SetContent(layoutContractEditor(name, current, scid))
+				session.LastDomain = capture
+			})
+		}()
+	}
 
-				a := container.NewStack(
-					span,
-					entryAssetValue,
-				)
+	// Only the owner can call the update entrypoint of an upgradeable contract
+	if _, _, ok := contractUpdateEntrypoint(code); !ok || owner != engram.Disk.GetAddress().String() {
+		linkUpdate.Hide()
+	}
 
-				d := container.NewStack(
-					span,
-					entryDEROValue,
-				)
+	linkView := widget.NewHyperlinkWithStyle("View in Explorer", nil, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	linkView.OnTapped = func() {
+		if engram.Disk.GetNetwork() {
+			link, _ := url.Parse("https://explorer.derofoundation.org/tx/" + scid)
+			_ = fyne.CurrentApp().OpenURL(link)
+		} else {
+			link, _ := url.Parse("https://testnetexplorer.derofoundation.org/tx/" + scid)
+			_ = fyne.CurrentApp().OpenURL(link)
+		}
+	}
 
-				paramsContainer := container.NewVBox()
+	// Now let's parse the smart contract code for exported functions
 
-				existsDEROValue := false
-				existsAssetValue := false
+	abi, err := getContractABI(code)
+	if err != nil {
+		logger.Debugf("[DVM] %s: %s\n", scid, err)
+	}
 
-				// Scan code for ASSETVALUE and DEROVALUE
-				for l := range contract.Functions[f].Lines {
-					for i := range contract.Functions[f].Lines[l] {
+	data := []string{}
+	for _, f := range abi {
+		data = append(data, f.Name)
+	}
+	data = append(data, " ")
 
-						for v := range paramList {
-							if paramList[v] == entryDEROValue {
-								existsDEROValue = true
-							} else if paramList[v] == entryAssetValue {
-								existsAssetValue = true
-							}
-						}
+	functionList := widget.NewSelect(data, nil)
+	functionList.OnChanged = func(s string) {
+		if s == " " {
+			functionList.ClearSelected()
+			return
+		}
 
-						if contract.Functions[f].Lines[l][i] == "DEROVALUE" && contract.Functions[f].Lines[l][i+1] == "(" && !existsDEROValue {
-							paramList = append(paramList, entryDEROValue)
-							paramsContainer.Add(d)
-							paramsContainer.Refresh()
-							existsDEROValue = true
-							logger.Debugf("[DVM] Added DEROVALUE: %s\n", contract.Functions[f].Lines[l][i])
-						} else if len(deroFunctions) > 0 {
-							for df := range deroFunctions {
-								if contract.Functions[f].Lines[l][i] == deroFunctions[df] && contract.Functions[f].Lines[l][i+1] == "(" && !existsDEROValue {
-									paramList = append(paramList, entryDEROValue)
-									paramsContainer.Add(d)
-									paramsContainer.Refresh()
-									existsDEROValue = true
-									logger.Debugf("[DVM] Added DEROVALUE: %s - Func: %s\n", contract.Functions[f].Lines[l][i], deroFunctions[df])
-								}
-							}
-						}
+		for _, f := range abi {
+			if f.Name != s {
+				continue
+			}
 
-						if contract.Functions[f].Lines[l][i] == "ASSETVALUE" && contract.Functions[f].Lines[l][i+1] == "(" && !existsAssetValue {
-							paramList = append(paramList, entryAssetValue)
-							paramsContainer.Add(a)
-							paramsContainer.Refresh()
-							existsAssetValue = true
-							logger.Debugf("[DVM] Added ASSETVALUE: %s\n", contract.Functions[f].Lines[l][i])
-						} else if len(assetFunctions) > 0 {
-							for af := range assetFunctions {
-								if contract.Functions[f].Lines[l][i] == assetFunctions[af] && contract.Functions[f].Lines[l][i+1] == "(" && !existsAssetValue {
-									paramList = append(paramList, entryAssetValue)
-									paramsContainer.Add(a)
-									paramsContainer.Refresh()
-									existsAssetValue = true
-									logger.Debugf("[DVM] Added ASSETVALUE: %s\n", contract.Functions[f].Lines[l][i])
-								}
-							}
-						}
+			overlay := session.Window.Canvas().Overlays()
+			overlay.Add(
+				container.NewStack(
+					&iframe{},
+					canvas.NewRectangle(colors.DarkMatter),
+				),
+			)
+			overlay.Add(
+				container.NewStack(
+					&iframe{},
+					layoutContractCall(scid, f, func() {
+						overlay.Top().Hide()
+						overlay.Remove(overlay.Top())
+						overlay.Remove(overlay.Top())
+					}),
+				),
+			)
+			overlay.Top().Show()
+		}
 
-						for si := range signerFunctions {
-							if contract.Functions[f].Lines[l][i] == "SIGNER" && contract.Functions[f].Lines[l][i+1] == "(" {
-								signerRequired = true
-							} else if contract.Functions[f].Lines[l][i] == signerFunctions[si] && contract.Functions[f].Lines[l][i+1] == "(" {
-								signerRequired = true
-							}
-						}
-					}
-				}
-
-				selectRingMembers.OnChanged = func(s string) {
-					if signerRequired {
-						ringsize = 2
-					} else {
-						regex := regexp.MustCompile("[0-9]+")
-						result := regex.FindAllString(selectRingMembers.Selected, -1)
-						ringsize, err = strconv.ParseUint(result[0], 10, 64)
-						if err != nil {
-							ringsize = 2
-						}
-					}
-				}
-
-				if signerRequired {
-					selectRingMembers.SetSelectedIndex(0)
-				} else {
-					selectRingMembers.SetSelectedIndex(3)
-				}
-
-				btnExecute := widget.NewButton("Execute", nil)
-
-				overlay.Add(
-					container.NewStack(
-						&iframe{},
-						container.NewCenter(
-							container.NewVBox(
-								span,
-								container.NewCenter(
-									header,
-								),
-								rectSpacer,
-								rectSpacer,
-								container.NewCenter(
-									funcName,
-								),
-								wSpacer,
-								selectRingMembers,
-								rectSpacer,
-								rectSpacer,
-								paramsContainer,
-								rectSpacer,
-								rectSpacer,
-								btnExecute,
-								rectSpacer,
-								rectSpacer,
-								container.NewHBox(
-									layout.NewSpacer(),
-									linkClose,
-									layout.NewSpacer(),
-								),
-								rectSpacer,
-								rectSpacer,
-							),
-						),
-					),
-				)
-
-				for p := range params {
-					entry := widget.NewEntry()
-					entry.PlaceHolder = params[p].Name
-					if params[p].Type == 0x4 {
-						entry.PlaceHolder = params[p].Name + " (Numbers Only)"
-					}
-					entry.Validator = func(s string) error {
-						for p := range params {
-							if params[p].Type == 0x5 {
-								if params[p].Name == entry.PlaceHolder {
-									logger.Debugf("[%s] String: %s\n", params[p].Name, s)
-									params[p].ValueString = s
-								}
-							} else if params[p].Type == 0x4 {
-								if params[p].Name+" (Numbers Only)" == entry.PlaceHolder {
-									amount, err := globals.ParseAmount(s)
-									if err != nil {
-										logger.Debugf("[%s] Param error: %s\n", params[p].Name, err)
-										entry.SetValidationError(err)
-										return err
-									} else {
-										logger.Debugf("[%s] Amount: %d\n", params[p].Name, amount)
-										params[p].ValueUint64 = amount
-									}
-								}
-							}
-						}
-
-						return nil
-					}
-
-					c := container.NewStack(
-						span,
-						entry,
-					)
-
-					paramList = append(paramList, entry)
-					paramsContainer.Add(c)
-					paramsContainer.Refresh()
-
-				}
-
-				btnExecute.OnTapped = func() {
-					for f := range contract.Functions {
-						if contract.Functions[f].Name == funcName.Text {
-							params = contract.Functions[f].Params
-						}
-					}
-
-					var err error
-
-					if signerRequired {
-						ringsize = 2
-					} else {
-						regex := regexp.MustCompile("[0-9]+")
-						result := regex.FindAllString(selectRingMembers.Selected, -1)
-						ringsize, err = strconv.ParseUint(result[0], 10, 64)
-						if err != nil {
-							ringsize = 2
-							selectRingMembers.SetSelected(options[3])
-						}
-					}
-
-					logger.Printf("[Engram] Ringsize: %d\n", ringsize)
-
-					btnExecute.Text = "Executing..."
-					btnExecute.Disable()
-					btnExecute.Refresh()
-
-					storage, err := executeContractFunction(hash, ringsize, dero_amount, asset_amount, funcName.Text, params)
-					if err != nil {
-						if strings.Contains(err.Error(), "somehow the tx could not be built") {
-							btnExecute.Text = fmt.Sprintf("Insufficient Balance: Need %v", globals.FormatMoney(storage))
-						} else if strings.Contains(err.Error(), "Discarded knowingly") {
-							btnExecute.Text = "Error... discarded knowingly"
-						} else if strings.Contains(err.Error(), "Recovered in function") {
-							btnExecute.Text = "Error... invalid input"
-						} else {
-							btnExecute.Text = "Error executing function..."
-						}
-						btnExecute.Disable()
-						btnExecute.Refresh()
-					} else {
-						btnExecute.Text = "Function executed successfully!"
-						btnExecute.Disable()
-						btnExecute.Refresh()
-					}
-				}
-
-				if signerRequired {
-					selectRingMembers.SetSelectedIndex(0)
-					selectRingMembers.Disable()
-				}
-
-				paramsContainer.Refresh()
-				overlay.Top().Show()
-				functionList.ClearSelected()
-			}
-		}
-	}
+		functionList.ClearSelected()
+	}
 
 	center := container.NewStack(
 		rectBox,
@@ -5074,6 +4888,10 @@ func layoutAssetManager(scid string) fyne.CanvasObject {
 							),
 							layout.NewSpacer(),
 						),
+						container.NewCenter(
+							labelBadge,
+						),
+						nfaPanel,
 						rectSpacer,
 						rectSpacer,
 						labelSeparator,
@@ -5125,6 +4943,18 @@ func layoutAssetManager(scid string) fyne.CanvasObject {
 							linkCopySCID,
 							layout.NewSpacer(),
 						),
+						container.NewHBox(
+							linkHide,
+							layout.NewSpacer(),
+						),
+						container.NewHBox(
+							linkTrust,
+							layout.NewSpacer(),
+						),
+						container.NewHBox(
+							linkUpdate,
+							layout.NewSpacer(),
+						),
 						rectSpacer,
 						rectSpacer,
 						labelSeparator6,
@@ -5158,6 +4988,15 @@ func layoutAssetManager(scid string) fyne.CanvasObject {
 						entryAmount,
 						rectSpacer,
 						btnSend,
+						rectSpacer,
+						container.NewHBox(
+							linkAirdrop,
+							layout.NewSpacer(),
+						),
+						container.NewHBox(
+							linkHistory,
+							layout.NewSpacer(),
+						),
 						wSpacer,
 					),
 					layout.NewSpacer(),
@@ -5215,1401 +5054,3952 @@ func layoutAssetManager(scid string) fyne.CanvasObject {
 	return NewVScroll(layout)
 }
 
-func layoutTransfers() fyne.CanvasObject {
-	session.Domain = "app.transfers"
+// Call form for an exported contract function, arguments are validated by their ABI type and calls can be saved as presets
+func layoutContractCall(scid string, function ContractFunction, done func()) *fyne.Container {
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
 
 	wSpacer := widget.NewLabel(" ")
 
-	sendTitle := canvas.NewText("T R A N S F E R S", colors.Gray)
-	sendTitle.TextStyle = fyne.TextStyle{Bold: true}
-	sendTitle.TextSize = 16
+	header := canvas.NewText("EXECUTE  CONTRACT  FUNCTION", colors.Gray)
+	header.TextSize = 14
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
 
-	sendDesc := canvas.NewText("", colors.Gray)
-	sendDesc.TextSize = 18
-	sendDesc.Alignment = fyne.TextAlignCenter
-	sendDesc.TextStyle = fyne.TextStyle{Bold: true}
+	funcName := canvas.NewText(function.Name, colors.Account)
+	funcName.TextSize = 22
+	funcName.Alignment = fyne.TextAlignCenter
+	funcName.TextStyle = fyne.TextStyle{Bold: true}
 
-	sendHeading := canvas.NewText("S A V E D    T R A N S F E R S", colors.Gray)
-	sendHeading.TextSize = 16
-	sendHeading.Alignment = fyne.TextAlignCenter
-	sendHeading.TextStyle = fyne.TextStyle{Bold: true}
+	errorText := canvas.NewText("", colors.Red)
+	errorText.TextSize = 12
+	errorText.Alignment = fyne.TextAlignCenter
 
-	rectStatus := canvas.NewRectangle(color.Transparent)
-	rectStatus.SetMinSize(fyne.NewSize(10, 10))
-	rect := canvas.NewRectangle(color.Transparent)
-	rect.SetMinSize(fyne.NewSize(ui.Width, 20))
-	frame := &iframe{}
-	rect.SetMinSize(fyne.NewSize(ui.Width, 30))
-	rectSpacer := canvas.NewRectangle(color.Transparent)
-	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
-	rect.SetMinSize(fyne.NewSize(10, 10))
-	rectEmpty := canvas.NewRectangle(color.Transparent)
-	rectEmpty.SetMinSize(fyne.NewSize(10, 10))
-	rectList := canvas.NewRectangle(color.Transparent)
-	rectList.SetMinSize(fyne.NewSize(ui.Width, 35))
-	rectListBox := canvas.NewRectangle(color.Transparent)
-	rectListBox.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.53))
+	options := []string{"Anonymity Set:   2  (None)", "Anonymity Set:   4  (Low)", "Anonymity Set:   8  (Low)", "Anonymity Set:   16  (Recommended)", "Anonymity Set:   32  (Medium)", "Anonymity Set:   64  (High)", "Anonymity Set:   128  (High)"}
+	selectRingMembers := widget.NewSelect(options, nil)
+	selectRingMembers.PlaceHolder = "(Select Anonymity Set)"
 
-	menuLabel := canvas.NewText("  M O R E   O P T I O N S  ", colors.Gray)
-	menuLabel.TextSize = 11
-	menuLabel.Alignment = fyne.TextAlignCenter
-	menuLabel.TextStyle = fyne.TextStyle{Bold: true}
+	// Functions using SIGNER() need to know who is calling them
+	if function.Signer {
+		selectRingMembers.SetSelectedIndex(0)
+		selectRingMembers.Disable()
+	} else {
+		selectRingMembers.SetSelectedIndex(3)
+	}
 
-	sep := canvas.NewRectangle(colors.Gray)
-	sep.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+	paramsContainer := container.NewVBox()
 
-	line1 := container.NewVBox(
-		layout.NewSpacer(),
-		sep,
-		layout.NewSpacer(),
-	)
+	entryDEROValue := widget.NewEntry()
+	entryDEROValue.PlaceHolder = "DERO Amount (Numbers Only)"
+	entryDEROValue.Validator = func(s string) error {
+		_, err := globals.ParseAmount(s)
+		return err
+	}
 
-	sep2 := canvas.NewRectangle(colors.Gray)
-	sep2.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+	entryAssetValue := widget.NewEntry()
+	entryAssetValue.PlaceHolder = "Asset Amount (Numbers Only)"
+	entryAssetValue.Validator = func(s string) error {
+		_, err := globals.ParseAmount(s)
+		return err
+	}
 
-	line2 := container.NewVBox(
-		layout.NewSpacer(),
-		sep2,
-		layout.NewSpacer(),
-	)
+	if function.DEROValue {
+		paramsContainer.Add(container.NewStack(span, entryDEROValue))
+	}
 
-	linkBack := widget.NewHyperlinkWithStyle("Back to Dashboard", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-	linkBack.OnTapped = func() {
-		session.LastDomain = session.Window.Content()
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutDashboard())
-		removeOverlays()
+	if function.AssetValue {
+		paramsContainer.Add(container.NewStack(span, entryAssetValue))
 	}
 
-	var pendingList []string
+	// Address and hex types are read from the code, they can be passed as a String instead
+	params := append([]ContractParam{}, function.Params...)
+	entries := make([]*widget.Entry, len(params))
+	for i := range params {
+		param := &params[i]
+		entry := widget.NewEntry()
+		entry.PlaceHolder = fmt.Sprintf("%s (%s)", param.Name, param.Type)
+		if param.Type == CONTRACT_PARAM_UINT64 {
+			entry.PlaceHolder = param.Name + " (Numbers Only)"
+		}
+		entry.Validator = func(s string) error {
+			_, err := param.parse(s)
+			return err
+		}
 
-	for i := 0; i < len(tx.Pending); i++ {
-		pendingList = append(pendingList, strconv.Itoa(i)+","+globals.FormatMoney(tx.Pending[i].Amount)+","+tx.Pending[i].Destination)
-	}
+		entries[i] = entry
 
-	data := binding.BindStringList(&pendingList)
+		if param.Type == CONTRACT_PARAM_UINT64 || param.Type == CONTRACT_PARAM_STRING {
+			paramsContainer.Add(container.NewStack(span, entry))
+			continue
+		}
 
-	scrollBox := widget.NewListWithData(data,
-		func() fyne.CanvasObject {
-			c := container.NewStack(
-				rectList,
-				container.NewHBox(
-					canvas.NewText("", colors.Account),
-					layout.NewSpacer(),
-					canvas.NewText("", colors.Account),
-				),
-			)
-			return c
-		},
-		func(di binding.DataItem, co fyne.CanvasObject) {
-			dat := di.(binding.String)
-			str, err := dat.Get()
-			if err != nil {
-				return
+		typed := param.Type
+		checkString := widget.NewCheck(CONTRACT_PARAM_STRING, func(b bool) {
+			param.Type = typed
+			if b {
+				param.Type = CONTRACT_PARAM_STRING
 			}
-			dataItem := strings.SplitN(str, ",", 3)
-			dest := dataItem[2]
-			dest = "   " + dest[0:4] + " ... " + dest[len(dataItem[2])-10:]
-			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[0].(*canvas.Text).Text = dest
-			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[0].(*canvas.Text).TextSize = 17
-			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[0].(*canvas.Text).TextStyle.Bold = true
-			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[2].(*canvas.Text).Text = dataItem[1] + "   "
-			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[2].(*canvas.Text).TextSize = 17
-			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[2].(*canvas.Text).TextStyle.Bold = true
+			entry.PlaceHolder = fmt.Sprintf("%s (%s)", param.Name, param.Type)
+			entry.Refresh()
+			entry.Validate()
 		})
 
-	scrollBox.OnSelected = func(id widget.ListItemID) {
-		session.LastDomain = session.Window.Content()
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutTransfersDetail(id))
+		paramsContainer.Add(container.NewStack(span, container.NewBorder(nil, nil, nil, checkString, entry)))
 	}
 
-	btnSend := widget.NewButton("Send Transfers", nil)
-
-	btnClear := widget.NewButton("Clear", func() {
-		pendingList = pendingList[:0]
-		tx = Transfers{}
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutTransfers())
-	})
+	selectPreset := widget.NewSelect(nil, nil)
+	selectPreset.PlaceHolder = "(Select Preset)"
 
-	if len(pendingList) > 0 {
-		btnClear.Enable()
-		btnSend.Enable()
-	} else {
-		btnClear.Disable()
-		btnSend.Disable()
-	}
+	entryPresetName := widget.NewEntry()
+	entryPresetName.PlaceHolder = "Preset Name"
 
-	if session.Offline {
-		btnSend.Text = "Disabled in Offline Mode"
-		btnSend.Disable()
-	}
+	linkSavePreset := widget.NewHyperlinkWithStyle("Save Preset", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkDeletePreset := widget.NewHyperlinkWithStyle("Delete Preset", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkDeletePreset.Hide()
 
-	btnSend.OnTapped = func() {
-		overlay := session.Window.Canvas().Overlays()
+	var presets []ContractPreset
 
-		header := canvas.NewText("ACCOUNT  VERIFICATION  REQUIRED", colors.Gray)
-		header.TextSize = 14
-		header.Alignment = fyne.TextAlignCenter
-		header.TextStyle = fyne.TextStyle{Bold: true}
+	loadPresets := func() {
+		stored, err := getContractPresets(scid)
+		if err != nil {
+			logger.Errorf("[Engram] Loading contract presets: %s\n", err)
+		}
 
-		subHeader := canvas.NewText("Confirm Password", colors.Account)
-		subHeader.TextSize = 22
-		subHeader.Alignment = fyne.TextAlignCenter
-		subHeader.TextStyle = fyne.TextStyle{Bold: true}
+		presets = nil
+		var names []string
+		for _, p := range stored {
+			if p.Function == function.Name {
+				presets = append(presets, p)
+				names = append(names, p.Name)
+			}
+		}
 
-		linkClose := widget.NewHyperlinkWithStyle("Cancel", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-		linkClose.OnTapped = func() {
-			overlay := session.Window.Canvas().Overlays()
-			overlay.Top().Hide()
-			overlay.Remove(overlay.Top())
-			overlay.Remove(overlay.Top())
+		selectPreset.SetOptions(names)
+		selectPreset.ClearSelected()
+		if len(names) > 0 {
+			selectPreset.Show()
+		} else {
+			selectPreset.Hide()
 		}
+		linkDeletePreset.Hide()
+	}
 
-		btnSubmit := widget.NewButton("Submit", nil)
+	selectPreset.OnChanged = func(s string) {
+		for _, p := range presets {
+			if p.Name != s {
+				continue
+			}
 
-		entryPassword := NewReturnEntry()
-		entryPassword.Password = true
-		entryPassword.PlaceHolder = "Password"
-		entryPassword.OnChanged = func(s string) {
-			if s == "" {
-				btnSubmit.Text = "Submit"
-				btnSubmit.Disable()
-				btnSubmit.Refresh()
-			} else {
-				btnSubmit.Text = "Submit"
-				btnSubmit.Enable()
-				btnSubmit.Refresh()
+			entryPresetName.SetText(p.Name)
+			entryDEROValue.SetText(p.DEROValue)
+			entryAssetValue.SetText(p.AssetValue)
+			for i, param := range function.Params {
+				entries[i].SetText(p.Args[param.Name])
 			}
-		}
 
-		btnSubmit.OnTapped = func() {
-			if engram.Disk.Check_Password(entryPassword.Text) {
-				removeOverlays()
-				if len(tx.Pending) == 0 {
-					return
-				} else {
-					btnSend.Text = "Setting up transfer..."
-					btnSend.Disable()
-					btnSend.Refresh()
-					txid, err := sendTransfers()
-					if err != nil {
-						btnSend.Text = "Send Transfers"
-						btnSend.Enable()
-						btnSend.Refresh()
-						return
+			if !function.Signer {
+				for _, o := range options {
+					if regexp.MustCompile("[0-9]+").FindString(o) == strconv.FormatUint(p.Ringsize, 10) {
+						selectRingMembers.SetSelected(o)
 					}
+				}
+			}
 
-					go func() {
-						btnClear.Disable()
-						btnSend.Text = "Confirming..."
-						btnSend.Refresh()
-
-						walletapi.WaitNewHeightBlock()
-						sHeight := walletapi.Get_Daemon_Height()
-
-						for session.Domain == "app.transfers" {
-							var zeroscid crypto.Hash
-							_, result := engram.Disk.Get_Payments_TXID(zeroscid, txid.String())
-
-							if result.TXID == txid.String() {
-								btnSend.Text = "Transfer Successful!"
-								btnSend.Refresh()
+			linkDeletePreset.Show()
+		}
+	}
 
-								break
-							}
+	ringsize := func() uint64 {
+		if function.Signer {
+			return 2
+		}
 
-							// If we go DEFAULT_CONFIRMATION_TIMEOUT blocks without exiting 'Confirming...' loop, display failed to transfer and break
-							if walletapi.Get_Daemon_Height() > sHeight+int64(DEFAULT_CONFIRMATION_TIMEOUT) {
-								btnSend.Text = "Transfer failed..."
-								btnSend.Disable()
-								btnSend.Refresh()
-								break
-							}
+		size, err := strconv.ParseUint(regexp.MustCompile("[0-9]+").FindString(selectRingMembers.Selected), 10, 64)
+		if err != nil {
+			return 2
+		}
 
-							// If daemon height has incremented, print retry counters into button space
-							if walletapi.Get_Daemon_Height()-sHeight > 0 {
-								btnSend.Text = fmt.Sprintf("Confirming... (%d/%d)", walletapi.Get_Daemon_Height()-sHeight, DEFAULT_CONFIRMATION_TIMEOUT)
-								btnSend.Refresh()
-							}
+		return size
+	}
 
-							time.Sleep(time.Second * 1)
-						}
-					}()
+	linkSavePreset.OnTapped = func() {
+		errorText.Color = colors.Red
+		if entryPresetName.Text == "" {
+			errorText.Text = "enter a preset name"
+			errorText.Refresh()
+			return
+		}
 
-					pendingList = pendingList[:0]
-					data.Reload()
-					btnSend.Disable()
-					btnClear.Disable()
-				}
-			} else {
-				btnSubmit.Text = "Invalid Password..."
-				btnSubmit.Disable()
-				btnSubmit.Refresh()
-			}
+		preset := ContractPreset{
+			Name:       entryPresetName.Text,
+			Function:   function.Name,
+			Ringsize:   ringsize(),
+			DEROValue:  entryDEROValue.Text,
+			AssetValue: entryAssetValue.Text,
+			Args:       map[string]string{},
 		}
 
-		btnSubmit.Disable()
+		for i, param := range function.Params {
+			preset.Args[param.Name] = entries[i].Text
+		}
 
-		entryPassword.OnReturn = btnSubmit.OnTapped
+		if err := storeContractPreset(scid, preset); err != nil {
+			logger.Errorf("[Engram] Saving contract preset: %s\n", err)
+			errorText.Text = "could not save preset"
+			errorText.Refresh()
+			return
+		}
 
-		span := canvas.NewRectangle(color.Transparent)
-		span.SetMinSize(fyne.NewSize(ui.Width, 10))
+		loadPresets()
+		selectPreset.SetSelected(preset.Name)
+		errorText.Text = "preset saved"
+		errorText.Color = colors.Green
+		errorText.Refresh()
+	}
 
-		overlay.Add(
-			container.NewStack(
-				&iframe{},
-				canvas.NewRectangle(colors.DarkMatter),
-			),
-		)
+	linkDeletePreset.OnTapped = func() {
+		if selectPreset.Selected == "" {
+			return
+		}
 
-		overlay.Add(
-			container.NewStack(
-				&iframe{},
-				container.NewCenter(
-					container.NewVBox(
-						span,
-						container.NewCenter(
-							header,
-						),
-						rectSpacer,
-						rectSpacer,
-						subHeader,
-						widget.NewLabel(""),
-						container.NewCenter(
-							container.NewStack(
-								span,
-								entryPassword,
-							),
-						),
-						rectSpacer,
-						rectSpacer,
-						btnSubmit,
-						rectSpacer,
-						rectSpacer,
-						container.NewHBox(
-							layout.NewSpacer(),
-							linkClose,
-							layout.NewSpacer(),
-						),
-						rectSpacer,
-						rectSpacer,
-					),
-				),
-			),
-		)
+		if err := deleteContractPreset(scid, function.Name, selectPreset.Selected); err != nil {
+			logger.Errorf("[Engram] Deleting contract preset: %s\n", err)
+			errorText.Text = "could not delete preset"
+			errorText.Color = colors.Red
+			errorText.Refresh()
+			return
+		}
 
-		session.Window.Canvas().Focus(entryPassword)
+		entryPresetName.SetText("")
+		loadPresets()
+		errorText.Text = "preset deleted"
+		errorText.Color = colors.Green
+		errorText.Refresh()
 	}
 
-	session.Window.Canvas().SetOnTypedKey(func(k *fyne.KeyEvent) {
-		if session.Domain != "app.transfers" {
-			return
-		}
+	btnExecute := widget.NewButton("Execute", nil)
+	btnExecute.OnTapped = func() {
+		errorText.Text = ""
+		errorText.Color = colors.Red
+		errorText.Refresh()
 
-		if k.Name == fyne.KeyDown {
-			session.Dashboard = "main"
-			session.Window.SetContent(layoutTransition())
-			session.Window.SetContent(layoutDashboard())
-			removeOverlays()
+		var dero_amount, asset_amount uint64
+		var err error
+		if function.DEROValue && entryDEROValue.Text != "" {
+			if dero_amount, err = globals.ParseAmount(entryDEROValue.Text); err != nil {
+				errorText.Text = "invalid DERO amount"
+				errorText.Refresh()
+				return
+			}
 		}
-	})
 
-	sendForm := container.NewVBox(
-		rectSpacer,
-		rectSpacer,
-		sendHeading,
-		rectSpacer,
-		rectSpacer,
-		container.NewStack(
-			rectListBox,
-			scrollBox,
-		),
-		wSpacer,
-		btnSend,
-		rectSpacer,
-		btnClear,
-		rectSpacer,
-		rectSpacer,
-	)
+		if function.AssetValue && entryAssetValue.Text != "" {
+			if asset_amount, err = globals.ParseAmount(entryAssetValue.Text); err != nil {
+				errorText.Text = "invalid asset amount"
+				errorText.Refresh()
+				return
+			}
+		}
 
-	gridItem1 := container.NewCenter(
-		sendForm,
-	)
+		var args []dvm.Variable
+		for i, param := range params {
+			v, err := param.parse(entries[i].Text)
+			if err != nil {
+				errorText.Text = err.Error()
+				errorText.Refresh()
+				return
+			}
+			args = append(args, v)
+		}
 
-	gridItem2 := container.NewCenter()
+		size := ringsize()
+		logger.Printf("[Engram] Ringsize: %d\n", size)
 
-	gridItem3 := container.NewCenter()
+		btnExecute.Text = "Executing..."
+		btnExecute.Disable()
+		btnExecute.Refresh()
 
-	gridItem4 := container.NewCenter()
+		go func() {
+			storage, err := executeContractFunction(crypto.HashHexToHash(scid), size, dero_amount, asset_amount, function.Name, args)
+			fyne.Do(func() {
+				if err != nil {
+					if strings.Contains(err.Error(), "somehow the tx could not be built") {
+						btnExecute.Text = fmt.Sprintf("Insufficient Balance: Need %v", globals.FormatMoney(storage))
+					} else if strings.Contains(err.Error(), "Discarded knowingly") {
+						btnExecute.Text = "Error... discarded knowingly"
+					} else if strings.Contains(err.Error(), "Recovered in function") {
+						btnExecute.Text = "Error... invalid input"
+					} else {
+						btnExecute.Text = "Error executing function..."
+					}
+				} else {
+					btnExecute.Text = "Function executed successfully!"
+				}
+				btnExecute.Refresh()
+			})
+		}()
+	}
 
-	gridItem1.Hidden = false
-	gridItem2.Hidden = true
-	gridItem3.Hidden = true
-	gridItem4.Hidden = true
+	linkClose := widget.NewHyperlinkWithStyle("Close", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClose.OnTapped = func() {
+		done()
+	}
 
-	features := container.NewCenter(
-		layout.NewSpacer(),
-		gridItem1,
-		layout.NewSpacer(),
-		gridItem2,
-		layout.NewSpacer(),
-		gridItem3,
-		layout.NewSpacer(),
-		gridItem4,
-		layout.NewSpacer(),
-	)
+	loadPresets()
 
-	bottom := container.NewStack(
+	return container.NewCenter(
 		container.NewVBox(
-			container.NewStack(
-				container.NewHBox(
-					layout.NewSpacer(),
-					line1,
-					layout.NewSpacer(),
-					menuLabel,
-					layout.NewSpacer(),
-					line2,
-					layout.NewSpacer(),
-				),
+			span,
+			container.NewCenter(
+				header,
 			),
 			rectSpacer,
 			rectSpacer,
 			container.NewCenter(
+				funcName,
+			),
+			wSpacer,
+			selectPreset,
+			rectSpacer,
+			selectRingMembers,
+			rectSpacer,
+			rectSpacer,
+			paramsContainer,
+			rectSpacer,
+			errorText,
+			rectSpacer,
+			btnExecute,
+			rectSpacer,
+			rectSpacer,
+			container.NewStack(
+				span,
+				entryPresetName,
+			),
+			container.NewHBox(
 				layout.NewSpacer(),
-				linkBack,
+				linkSavePreset,
+				layout.NewSpacer(),
+				linkDeletePreset,
 				layout.NewSpacer(),
 			),
 			rectSpacer,
-			rectSpacer,
+			container.NewHBox(
+				layout.NewSpacer(),
+				linkClose,
+				layout.NewSpacer(),
+			),
 			rectSpacer,
 			rectSpacer,
 		),
 	)
+}
 
-	c := container.NewBorder(
-		features,
-		bottom,
-		nil,
-		nil,
-	)
+// Artificer NFA panel for the asset manager with the file metadata, listing state and the actions available to the account
+func layoutNFA(scid string) *fyne.Container {
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
+	rectWidth90 := canvas.NewRectangle(color.Transparent)
+	rectWidth90.SetMinSize(fyne.NewSize(ui.Width, 10))
 
-	layout := container.NewStack(
-		frame,
-		c,
+	labelNFA := canvas.NewText("   ARTIFICER  NFA", colors.Gray)
+	labelNFA.TextSize = 14
+	labelNFA.Alignment = fyne.TextAlignLeading
+	labelNFA.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelSeparator := widget.NewRichTextFromMarkdown("")
+	labelSeparator.Wrapping = fyne.TextWrapOff
+	labelSeparator.ParseMarkdown("---")
+
+	status := canvas.NewText("   Loading NFA details...", colors.Yellow)
+	status.TextSize = 13
+
+	cover := container.NewHBox()
+
+	textDetails := widget.NewRichTextFromMarkdown("")
+	textDetails.Wrapping = fyne.TextWrapWord
+
+	textListing := widget.NewRichTextFromMarkdown("")
+	textListing.Wrapping = fyne.TextWrapWord
+
+	actions := container.NewVBox()
+
+	panel := container.NewVBox(
+		rectSpacer,
+		rectSpacer,
+		labelSeparator,
+		rectSpacer,
+		rectSpacer,
+		labelNFA,
+		rectSpacer,
+		status,
+		cover,
+		container.NewStack(
+			rectWidth90,
+			textDetails,
+		),
+		container.NewStack(
+			rectWidth90,
+			textListing,
+		),
+		actions,
 	)
 
-	return NewVScroll(layout)
-}
+	var load func()
+	load = func() {
+		nfa, err := getNFAListing(scid)
+		if err != nil {
+			fyne.Do(func() {
+				status.Text = "   Could not load the NFA details."
+				status.Color = colors.Red
+				status.Refresh()
+			})
+			return
+		}
 
-func layoutTransfersDetail(index int) fyne.CanvasObject {
-	wSpacer := widget.NewLabel(" ")
+		var image *canvas.Image
+		if nfa.Cover != "" {
+			if img, err := handleImageURL(scid, nfa.Cover, fyne.NewSize(ui.Width*0.5, ui.Width*0.5)); err == nil {
+				image = img
+			} else {
+				logger.Errorf("[NFA] Could not validate cover image: %s\n", err)
+			}
+		}
 
-	rectWidth := canvas.NewRectangle(color.Transparent)
-	rectWidth.SetMinSize(fyne.NewSize(ui.MaxWidth*0.99, 10))
+		var bal uint64
+		address := ""
+		if engram.Disk != nil {
+			address = engram.Disk.GetAddress().String()
+			bal, _, _ = engram.Disk.GetDecryptedBalanceAtTopoHeight(crypto.HashHexToHash(scid), -1, address)
+		}
 
-	rectWidth90 := canvas.NewRectangle(color.Transparent)
-	rectWidth90.SetMinSize(fyne.NewSize(ui.Width, 10))
+		details := ""
+		if nfa.Collection != "" {
+			details += "**Collection:**  " + nfa.Collection + "\n\n"
+		}
+		if nfa.FileType != "" {
+			details += "**Type:**  " + nfa.FileType + "\n\n"
+		}
+		if nfa.File != "" {
+			details += "**File:**  [" + nfa.File + "](" + nfa.File + ")\n\n"
+		}
+		if nfa.Signature != "" {
+			details += "**Signature:**  [" + nfa.Signature + "](" + nfa.Signature + ")\n\n"
+		}
+		if nfa.FileCheckC != "" {
+			details += "**File Check C:**  " + nfa.FileCheckC + "\n\n"
+		}
+		if nfa.FileCheckS != "" {
+			details += "**File Check S:**  " + nfa.FileCheckS + "\n\n"
+		}
+		details += fmt.Sprintf("**Royalty:**  %d%%\n\n**Artificer Fee:**  %d%%\n\n", nfa.Royalty, nfa.ArtificerFee)
+		if nfa.Creator != "" {
+			details += "**Creator:**  " + nfa.Creator + "\n\n"
+		}
+		if nfa.Owner != "" {
+			details += "**Owner:**  " + nfa.Owner
+		}
 
-	frame := &iframe{}
+		listing := "**Status:**  Not listed"
+		switch nfa.ListType {
+		case NFA_LIST_SALE:
+			listing = fmt.Sprintf("**Status:**  For Sale\n\n**Price:**  %s DERO", globals.FormatMoney(nfa.StartPrice))
+		case NFA_LIST_AUCTION:
+			listing = fmt.Sprintf("**Status:**  Auction\n\n**Starting Price:**  %s DERO\n\n**Current Bid:**  %s DERO\n\n**Bids:**  %d", globals.FormatMoney(nfa.StartPrice), globals.FormatMoney(nfa.CurrentBid), nfa.BidCount)
+		}
 
-	heading := canvas.NewText("T R A N S F E R    D E T A I L", colors.Gray)
-	heading.TextSize = 16
-	heading.Alignment = fyne.TextAlignCenter
-	heading.TextStyle = fyne.TextStyle{Bold: true}
+		if nfa.listed() && nfa.End > 0 {
+			end := time.Unix(int64(nfa.End), 0).Format(time.RFC822)
+			if nfa.ended() {
+				listing += "\n\n**Ended:**  " + end
+			} else {
+				listing += "\n\n**Ends:**  " + end
+			}
+		}
 
-	rectSpacer := canvas.NewRectangle(color.Transparent)
-	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
+		if nfa.Charity != "" && nfa.CharityPerc > 0 {
+			listing += fmt.Sprintf("\n\n**Charity:**  %d%% to %s", nfa.CharityPerc, nfa.Charity)
+		}
 
-	labelDestination := canvas.NewText("   RECEIVER  ADDRESS", colors.Gray)
-	labelDestination.TextSize = 14
-	labelDestination.Alignment = fyne.TextAlignLeading
-	labelDestination.TextStyle = fyne.TextStyle{Bold: true}
+		isOwner := address != "" && nfa.Owner == address
 
-	labelAmount := canvas.NewText("   AMOUNT", colors.Gray)
-	labelAmount.TextSize = 14
-	labelAmount.Alignment = fyne.TextAlignLeading
-	labelAmount.TextStyle = fyne.TextStyle{Bold: true}
+		var buttons []fyne.CanvasObject
+		addAction := func(label string, action string, listType string) {
+			if !nfa.supports(action) {
+				return
+			}
 
-	labelService := canvas.NewText("   SERVICE  ADDRESS", colors.Gray)
-	labelService.TextSize = 14
-	labelService.Alignment = fyne.TextAlignLeading
-	labelService.TextStyle = fyne.TextStyle{Bold: true}
+			btn := widget.NewButton(label, nil)
+			btn.OnTapped = func() {
+				overlay := session.Window.Canvas().Overlays()
+				overlay.Add(
+					container.NewStack(
+						&iframe{},
+						canvas.NewRectangle(colors.DarkMatter),
+					),
+				)
+				overlay.Add(
+					container.NewStack(
+						&iframe{},
+						layoutNFAAction(nfa, label, action, listType, func(executed bool) {
+							overlay.Top().Hide()
+							overlay.Remove(overlay.Top())
+							overlay.Remove(overlay.Top())
+							if executed {
+								go load()
+							}
+						}),
+					),
+				)
+				overlay.Top().Show()
+			}
 
-	labelDestPort := canvas.NewText("   DESTINATION  PORT", colors.Gray)
-	labelDestPort.TextSize = 14
-	labelDestPort.TextStyle = fyne.TextStyle{Bold: true}
+			buttons = append(buttons, btn, rectSpacer)
+		}
 
-	labelSourcePort := canvas.NewText("   SOURCE  PORT", colors.Gray)
-	labelSourcePort.TextSize = 14
-	labelSourcePort.TextStyle = fyne.TextStyle{Bold: true}
+		if !nfa.listed() {
+			if bal > 0 && !isOwner {
+				addAction("Claim Ownership", NFA_ACTION_CLAIM, "")
+			}
 
-	labelFees := canvas.NewText("   TRANSACTION  FEES", colors.Gray)
-	labelFees.TextSize = 14
-	labelFees.TextStyle = fyne.TextStyle{Bold: true}
+			if isOwner && (bal > 0 || !nfa.uses(NFA_ACTION_START, "ASSETVALUE")) {
+				addAction("List for Sale", NFA_ACTION_START, NFA_LIST_SALE)
+				addAction("Start Auction", NFA_ACTION_START, NFA_LIST_AUCTION)
+			}
+		} else {
+			if !isOwner && !nfa.ended() {
+				// The standard has one entrypoint for both, the contract branches on its listType
+				if nfa.ListType == NFA_LIST_SALE {
+					addAction("Buy Now", NFA_ACTION_BID_OR_BUY, "")
+				} else {
+					addAction("Place Bid", NFA_ACTION_BID_OR_BUY, "")
+				}
+			}
 
-	labelPayload := canvas.NewText("   PAYLOAD", colors.Gray)
-	labelPayload.TextSize = 14
-	labelPayload.TextStyle = fyne.TextStyle{Bold: true}
+			if isOwner && !nfa.ended() {
+				addAction("Cancel Listing", NFA_ACTION_CANCEL, "")
+			}
 
-	labelReply := canvas.NewText("   REPLY  ADDRESS", colors.Gray)
-	labelReply.TextSize = 14
-	labelReply.TextStyle = fyne.TextStyle{Bold: true}
+			if nfa.ended() {
+				addAction("Close Listing", NFA_ACTION_CLOSE, "")
+			}
+		}
 
-	labelSeparator := widget.NewRichTextFromMarkdown("")
-	labelSeparator.Wrapping = fyne.TextWrapOff
-	labelSeparator.ParseMarkdown("---")
+		fyne.Do(func() {
+			status.Text = ""
+			status.Refresh()
 
-	labelSeparator2 := widget.NewRichTextFromMarkdown("")
-	labelSeparator2.Wrapping = fyne.TextWrapOff
-	labelSeparator2.ParseMarkdown("---")
+			cover.Objects = nil
+			if image != nil {
+				cover.Objects = []fyne.CanvasObject{layout.NewSpacer(), image, layout.NewSpacer()}
+			}
+			cover.Refresh()
 
-	labelSeparator3 := widget.NewRichTextFromMarkdown("")
-	labelSeparator3.Wrapping = fyne.TextWrapOff
-	labelSeparator3.ParseMarkdown("---")
+			textDetails.ParseMarkdown(details)
+			textListing.ParseMarkdown(listing)
 
-	labelSeparator4 := widget.NewRichTextFromMarkdown("")
-	labelSeparator4.Wrapping = fyne.TextWrapOff
-	labelSeparator4.ParseMarkdown("---")
+			actions.Objects = buttons
+			actions.Refresh()
+		})
+	}
 
-	labelSeparator5 := widget.NewRichTextFromMarkdown("")
-	labelSeparator5.Wrapping = fyne.TextWrapOff
-	labelSeparator5.ParseMarkdown("---")
+	go load()
 
-	menuLabel := canvas.NewText("  M O R E   O P T I O N S  ", colors.Gray)
-	menuLabel.TextSize = 11
-	menuLabel.Alignment = fyne.TextAlignCenter
-	menuLabel.TextStyle = fyne.TextStyle{Bold: true}
+	return panel
+}
 
-	sep := canvas.NewRectangle(colors.Gray)
-	sep.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+// Form for an Artificer NFA action, the parameters are taken from the contract entrypoint. done is called with whether the action was sent
+func layoutNFAAction(nfa NFAListing, label string, action string, listType string, done func(executed bool)) *fyne.Container {
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
 
-	line1 := container.NewVBox(
-		layout.NewSpacer(),
-		sep,
-		layout.NewSpacer(),
-	)
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
 
-	sep2 := canvas.NewRectangle(colors.Gray)
-	sep2.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+	header := canvas.NewText("ARTIFICER  NFA", colors.Gray)
+	header.TextSize = 14
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
 
-	line2 := container.NewVBox(
-		layout.NewSpacer(),
-		sep2,
-		layout.NewSpacer(),
-	)
+	title := canvas.NewText(label, colors.Account)
+	title.TextSize = 22
+	title.Alignment = fyne.TextAlignCenter
+	title.TextStyle = fyne.TextStyle{Bold: true}
 
-	details := tx.Pending[index]
+	errorText := canvas.NewText("", colors.Red)
+	errorText.TextSize = 12
+	errorText.Alignment = fyne.TextAlignCenter
 
-	valueDestination := widget.NewRichTextFromMarkdown("--")
-	valueDestination.Wrapping = fyne.TextWrapBreak
+	btnExecute := widget.NewButton(label, nil)
+	executed := false
 
-	valueType := widget.NewRichTextFromMarkdown("--")
-	valueType.Wrapping = fyne.TextWrapOff
+	linkClose := widget.NewHyperlinkWithStyle("Close", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClose.OnTapped = func() {
+		done(executed)
+	}
 
-	if details.Destination != "" {
-		address, _ := globals.ParseValidateAddress(details.Destination)
-		if address.IsIntegratedAddress() {
-			valueDestination.ParseMarkdown(address.BaseAddress().String())
-			valueType.ParseMarkdown("### SERVICE")
-		} else {
-			valueDestination.ParseMarkdown(details.Destination)
-			valueType.ParseMarkdown("### NORMAL")
-		}
+	// DERO amounts are entered for the price parameters, other numbers are taken as is
+	isPrice := func(name string) bool {
+		return strings.Contains(strings.ToLower(name), "price")
 	}
 
-	valueReply := widget.NewRichTextFromMarkdown("--")
-	valueReply.Wrapping = fyne.TextWrapBreak
+	form := container.NewVBox()
+	params := nfa.params(action)
+	values := make([]string, len(params))
 
-	if details.Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
-		if details.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string) != "" {
-			valueReply.ParseMarkdown("" + details.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string))
-		}
-	}
+	bidding := action == NFA_ACTION_BID_OR_BUY && nfa.ListType == NFA_LIST_AUCTION
 
-	valuePayload := widget.NewRichTextFromMarkdown("--")
-	valuePayload.Wrapping = fyne.TextWrapBreak
+	var dero_amount uint64
+	switch {
+	case action == NFA_ACTION_BID_OR_BUY && nfa.ListType == NFA_LIST_SALE:
+		dero_amount = nfa.StartPrice
+		text := widget.NewRichTextFromMarkdown(fmt.Sprintf("Buy this NFA for **%s DERO**", globals.FormatMoney(nfa.StartPrice)))
+		text.Wrapping = fyne.TextWrapWord
+		form.Add(container.NewStack(span, text))
+	case bidding:
+		minimum := nfa.StartPrice
+		if nfa.CurrentBid >= minimum {
+			minimum = nfa.CurrentBid + 1
+		}
+
+		entryBid := widget.NewEntry()
+		entryBid.PlaceHolder = fmt.Sprintf("Bid Amount (Minimum %s DERO)", globals.FormatMoney(minimum))
+		entryBid.Validator = func(s string) error {
+			amount, err := globals.ParseAmount(s)
+			if err != nil {
+				dero_amount = 0
+				return errors.New("invalid amount")
+			}
 
-	if details.Payload_RPC.HasValue(rpc.RPC_COMMENT, rpc.DataString) {
-		if details.Payload_RPC.Value(rpc.RPC_COMMENT, rpc.DataString).(string) != "" {
-			valuePayload.ParseMarkdown("" + details.Payload_RPC.Value(rpc.RPC_COMMENT, rpc.DataString).(string))
+			if amount < minimum {
+				dero_amount = 0
+				return errors.New("bid is too low")
+			}
+
+			dero_amount = amount
+			return nil
+		}
+		form.Add(container.NewStack(span, entryBid))
+	default:
+		if nfa.uses(action, "ASSETVALUE") {
+			text := widget.NewRichTextFromMarkdown("The NFA will be deposited into its contract.")
+			text.Wrapping = fyne.TextWrapWord
+			form.Add(container.NewStack(span, text))
 		}
 	}
 
-	valueAmount := canvas.NewText("", colors.Account)
-	valueAmount.TextSize = 22
-	valueAmount.TextStyle = fyne.TextStyle{Bold: true}
-	valueAmount.Text = "  " + globals.FormatMoney(details.Amount)
+	for p := range params {
+		p := p
+		name := params[p].Name
 
-	valueDestPort := canvas.NewText("", colors.Account)
-	valueDestPort.TextSize = 22
-	valueDestPort.TextStyle = fyne.TextStyle{Bold: true}
+		// The listing type is set by the chosen action
+		if name == "listType" && listType != "" {
+			values[p] = listType
+			continue
+		}
 
-	if details.Payload_RPC.HasValue(rpc.RPC_DESTINATION_PORT, rpc.DataUint64) {
-		port := fmt.Sprintf("%d", details.Payload_RPC.Value(rpc.RPC_DESTINATION_PORT, rpc.DataUint64))
-		valueDestPort.Text = "  " + port
-	} else {
-		valueDestPort.Text = "  0"
-	}
+		entry := widget.NewEntry()
+		entry.PlaceHolder = name
+		if params[p].Type == dvm.Uint64 {
+			if isPrice(name) {
+				entry.PlaceHolder = name + " (DERO)"
+			} else {
+				entry.PlaceHolder = name + " (Numbers Only)"
+			}
+		}
 
-	linkBack := widget.NewHyperlinkWithStyle("Back to Transfers", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-	linkBack.OnTapped = func() {
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutTransfers())
-	}
+		entry.Validator = func(s string) error {
+			values[p] = s
+			if params[p].Type == dvm.Uint64 && s != "" {
+				if isPrice(name) {
+					if _, err := globals.ParseAmount(s); err != nil {
+						return errors.New("invalid amount")
+					}
+				} else if _, err := strconv.ParseUint(s, 10, 64); err != nil {
+					return errors.New("invalid number")
+				}
+			}
 
-	btnDelete := widget.NewButton("Cancel Transfer", nil)
-	btnDelete.OnTapped = func() {
-		if len(tx.Pending) > index+1 {
-			tx.Pending = append(tx.Pending[:index], tx.Pending[index+1:]...)
-		} else if len(tx.Pending) == 1 {
-			tx = Transfers{}
-		} else {
-			tx.Pending = tx.Pending[:index]
+			return nil
 		}
 
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutTransfers())
+		form.Add(container.NewStack(span, entry))
 	}
 
-	top := container.NewVBox(
-		rectSpacer,
-		rectSpacer,
-		container.NewCenter(
-			heading,
-		),
-		rectSpacer,
-		container.NewCenter(
-			valueType,
-		),
-		rectSpacer,
-		rectSpacer,
-	)
+	btnExecute.OnTapped = func() {
+		for p := range params {
+			switch params[p].Type {
+			case dvm.Uint64:
+				var err error
+				if values[p] == "" {
+					params[p].ValueUint64 = 0
+				} else if isPrice(params[p].Name) {
+					params[p].ValueUint64, err = globals.ParseAmount(values[p])
+				} else {
+					params[p].ValueUint64, err = strconv.ParseUint(values[p], 10, 64)
+				}
 
-	center := container.NewStack(
-		container.NewVScroll(
-			container.NewStack(
-				rectWidth,
-				container.NewHBox(
-					layout.NewSpacer(),
-					container.NewVBox(
-						rectSpacer,
-						rectSpacer,
-						labelDestination,
-						rectSpacer,
-						valueDestination,
-						rectSpacer,
-						rectSpacer,
-						labelSeparator,
-						rectSpacer,
-						rectSpacer,
-						labelAmount,
-						rectSpacer,
-						container.NewStack(
-							rectWidth90,
-							valueAmount,
-						),
-						rectSpacer,
-						rectSpacer,
-						labelSeparator2,
-						rectSpacer,
-						rectSpacer,
-						labelReply,
-						rectSpacer,
-						valueReply,
-						rectSpacer,
-						rectSpacer,
-						labelSeparator3,
-						rectSpacer,
-						rectSpacer,
-						labelPayload,
-						rectSpacer,
-						container.NewStack(
-							rectWidth90,
-							valuePayload,
-						),
-						rectSpacer,
-						rectSpacer,
-						labelSeparator4,
-						rectSpacer,
-						rectSpacer,
-						labelDestPort,
-						rectSpacer,
-						container.NewStack(
-							rectWidth90,
-							valueDestPort,
-						),
-						wSpacer,
-					),
-					layout.NewSpacer(),
-				),
-			),
-		),
-	)
+				if err != nil {
+					errorText.Text = "invalid value for " + params[p].Name
+					errorText.Refresh()
+					return
+				}
+			default:
+				params[p].ValueString = values[p]
+			}
+		}
 
-	bottom := container.NewStack(
+		if bidding && dero_amount == 0 {
+			errorText.Text = "enter a valid bid amount"
+			errorText.Refresh()
+			return
+		}
+
+		errorText.Text = ""
+		errorText.Refresh()
+		btnExecute.SetText("Executing...")
+		btnExecute.Disable()
+
+		go func() {
+			storage, err := executeNFAAction(nfa, action, dero_amount, params)
+			fyne.Do(func() {
+				if err != nil {
+					logger.Errorf("[NFA] %s failed: %s\n", action, err)
+					if strings.Contains(err.Error(), "somehow the tx could not be built") {
+						btnExecute.SetText(fmt.Sprintf("Insufficient Balance: Need %v", globals.FormatMoney(storage)))
+					} else {
+						btnExecute.SetText("Error executing function...")
+					}
+					return
+				}
+
+				executed = true
+				btnExecute.SetText("Function executed successfully!")
+			})
+		}()
+	}
+
+	return container.NewCenter(
 		container.NewVBox(
+			span,
+			container.NewCenter(
+				header,
+			),
 			rectSpacer,
 			rectSpacer,
-			container.NewStack(
-				rectWidth,
-				container.NewHBox(
-					layout.NewSpacer(),
-					container.NewStack(
-						rectWidth90,
-						btnDelete,
-					),
-					layout.NewSpacer(),
-				),
+			container.NewCenter(
+				title,
 			),
 			rectSpacer,
 			rectSpacer,
+			form,
 			rectSpacer,
-			container.NewStack(
-				container.NewHBox(
-					layout.NewSpacer(),
-					line1,
-					layout.NewSpacer(),
-					menuLabel,
-					layout.NewSpacer(),
-					line2,
-					layout.NewSpacer(),
-				),
-			),
+			errorText,
 			rectSpacer,
+			btnExecute,
 			rectSpacer,
-			container.NewCenter(
+			rectSpacer,
+			container.NewHBox(
 				layout.NewSpacer(),
-				linkBack,
+				linkClose,
 				layout.NewSpacer(),
 			),
 			rectSpacer,
 			rectSpacer,
-			rectSpacer,
-			rectSpacer,
-		),
-	)
-
-	layout := container.NewStack(
-		frame,
-		container.NewBorder(
-			top,
-			bottom,
-			nil,
-			center,
 		),
 	)
-
-	return NewVScroll(layout)
 }
 
-func layoutTransition() fyne.CanvasObject {
-	frame := &iframe{}
-	resizeWindow(ui.MaxWidth, ui.MaxHeight)
+// Token airdrop form for the asset manager, recipients are sent in batched transactions and a stored job can be resumed
+func layoutAirdrop(scid string, name string, done func()) *fyne.Container {
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
 
-	rect := canvas.NewRectangle(color.Transparent)
-	rect.SetMinSize(fyne.NewSize(ui.Width*0.45, ui.Width*0.45))
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
 
-	if res.loading == nil {
-		res.loading, _ = x.NewAnimatedGifFromResource(resourceLoadingGif)
-		res.loading.SetMinSize(fyne.NewSize(ui.Width*0.45, ui.Width*0.45))
-		res.loading.Resize(fyne.NewSize(ui.Width*0.45, ui.Width*0.45))
-	}
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.2))
 
-	res.loading.Start()
+	header := canvas.NewText("TOKEN  AIRDROP", colors.Gray)
+	header.TextSize = 14
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
 
-	layout := container.NewStack(
-		frame,
-		container.NewCenter(
-			rect,
-			res.loading,
-		),
-	)
+	title := canvas.NewText(name, colors.Account)
+	title.TextSize = 22
+	title.Alignment = fyne.TextAlignCenter
+	title.TextStyle = fyne.TextStyle{Bold: true}
 
-	return NewVScroll(layout)
-}
+	summary := canvas.NewText("", colors.Gray)
+	summary.TextSize = 13
+	summary.Alignment = fyne.TextAlignCenter
 
-func layoutSettings() fyne.CanvasObject {
-	stopGnomon()
-	rect := canvas.NewRectangle(color.Transparent)
-	rect.SetMinSize(fyne.NewSize(ui.Width, 10))
-	rectScroll := canvas.NewRectangle(color.Transparent)
-	rectScroll.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.65))
-	frame := &iframe{}
-	rectSpacer := canvas.NewRectangle(color.Transparent)
-	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+	errorText := canvas.NewText("", colors.Red)
+	errorText.TextSize = 12
+	errorText.Alignment = fyne.TextAlignCenter
 
-	heading := canvas.NewText("My Settings", colors.Green)
-	heading.TextSize = 22
-	heading.Alignment = fyne.TextAlignCenter
-	heading.TextStyle = fyne.TextStyle{Bold: true}
+	progress := widget.NewProgressBar()
+	progress.Hide()
 
-	labelNetwork := canvas.NewText("NETWORK", colors.Gray)
-	labelNetwork.TextStyle = fyne.TextStyle{Bold: true}
-	labelNetwork.TextSize = 14
+	entryRecipients := widget.NewMultiLineEntry()
+	entryRecipients.PlaceHolder = "address,amount (one recipient per line)"
+	entryRecipients.Wrapping = fyne.TextWrapOff
+	entryRecipients.SetMinRowsVisible(6)
 
-	labelNode := canvas.NewText("CONNECTION", colors.Gray)
-	labelNode.TextStyle = fyne.TextStyle{Bold: true}
-	labelNode.TextSize = 14
+	options := []string{"Anonymity Set:   2  (None)", "Anonymity Set:   4  (Low)", "Anonymity Set:   8  (Low)", "Anonymity Set:   16  (Recommended)", "Anonymity Set:   32  (Medium)", "Anonymity Set:   64  (High)", "Anonymity Set:   128  (High)"}
+	selectRingSize := widget.NewSelect(options, nil)
+	selectRingSize.SetSelectedIndex(3)
 
-	labelSecurity := canvas.NewText("SECURITY", colors.Gray)
-	labelSecurity.TextStyle = fyne.TextStyle{Bold: true}
-	labelSecurity.TextSize = 14
+	selectBatch := widget.NewSelect([]string{"Batch Size:   1", "Batch Size:   2", "Batch Size:   4", "Batch Size:   8"}, nil)
+	selectBatch.SetSelected(fmt.Sprintf("Batch Size:   %d", DEFAULT_AIRDROP_BATCH_SIZE))
 
-	labelGnomon := canvas.NewText("GNOMON", colors.Gray)
-	labelGnomon.TextStyle = fyne.TextStyle{Bold: true}
-	labelGnomon.TextSize = 14
+	decimals := assetMetadata.get(scid).Decimals
 
-	textGnomon := widget.NewRichTextWithText("Gnomon scans and indexes blockchain data in order to unlock more features, like native asset tracking.")
-	textGnomon.Wrapping = fyne.TextWrapWord
+	btnStart := widget.NewButton("Start Airdrop", nil)
+	linkLoad := widget.NewHyperlinkWithStyle("Load from File", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkExport := widget.NewHyperlinkWithStyle("Export Report", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClear := widget.NewHyperlinkWithStyle("Clear Airdrop", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClose := widget.NewHyperlinkWithStyle("Close", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 
-	textCyberdeck := widget.NewRichTextWithText("A username and password is required in order to allow application connectivity.")
-	textCyberdeck.Wrapping = fyne.TextWrapWord
+	var job Airdrop
+	var active bool
 
-	btnRestore := widget.NewButton("Restore Defaults", nil)
-	btnDelete := widget.NewButton("Clear Local Data", nil)
+	listResults := widget.NewList(
+		func() int {
+			return len(job.Recipients)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id >= len(job.Recipients) {
+				return
+			}
 
-	entryAddress := widget.NewEntry()
-	entryAddress.Validator = func(s string) (err error) {
-		/*
-			_, err := net.ResolveTCPAddr("tcp", s)
-		*/
-		regex := `^(?:[a-zA-Z0-9]{1,62}(?:[-\.][a-zA-Z0-9]{1,62})+)(:\d+)?$`
-		test := regexp.MustCompile(regex)
+			r := job.Recipients[id]
+			text := fmt.Sprintf("%s  %s  %s", r.Status, formatAssetAmount(r.Amount, decimals), r.Address[len(r.Address)-DEFAULT_USERADDR_SHORTEN_LENGTH:])
+			if r.TXID != "" {
+				text += "  " + r.TXID[0:8] + "..."
+			}
+			co.(*widget.Label).SetText(text)
+		},
+	)
+	listResults.OnSelected = func(id widget.ListItemID) {
+		listResults.UnselectAll()
+		if id < len(job.Recipients) && job.Recipients[id].TXID != "" {
+			a.Clipboard().SetContent(job.Recipients[id].TXID)
+			errorText.Text = "TXID copied to clipboard"
+			errorText.Color = colors.Green
+			errorText.Refresh()
+		}
+	}
 
-		// Trim off http, https, wss, ws to validate regex on 'actual' uri for connection. If none match, s is just s as normal
-		var ssplit string
-		if strings.HasPrefix(s, "https") {
-			ssplit = strings.TrimPrefix(strings.ToLower(s), "https://")
-		} else if strings.HasPrefix(s, "http") {
-			ssplit = strings.TrimPrefix(strings.ToLower(s), "http://")
-		} else if strings.HasPrefix(s, "wss") {
-			ssplit = strings.TrimPrefix(strings.ToLower(s), "wss://")
-		} else if strings.HasPrefix(s, "ws") {
-			ssplit = strings.TrimPrefix(strings.ToLower(s), "ws://")
+	results := container.NewStack(rectList, listResults)
+
+	// Show the state of the stored or running airdrop job
+	refresh := func() {
+		if current, running := airdrop.status(); current.SCID == scid {
+			job, active = current, running
+		} else if stored, err := getAirdrop(scid); err == nil {
+			job, active = stored, false
 		} else {
-			// s is s
-			ssplit = s
+			job, active = Airdrop{}, false
+		}
+
+		if len(job.Recipients) == 0 {
+			entryRecipients.Enable()
+			selectRingSize.Enable()
+			selectBatch.Enable()
+			linkLoad.Show()
+			progress.Hide()
+			results.Hide()
+			linkExport.Hide()
+			linkClear.Hide()
+			summary.Text = ""
+			summary.Refresh()
+			btnStart.SetText("Start Airdrop")
+			btnStart.Enable()
+			return
 		}
 
-		if test.MatchString(ssplit) {
-			entryAddress.SetValidationError(nil)
-			setDaemon(s)
-		} else {
-			err = errors.New("invalid host name")
-			entryAddress.SetValidationError(err)
+		sent := job.count(AIRDROP_STATUS_SENT)
+		failed := job.count(AIRDROP_STATUS_FAILED)
+		total := len(job.Recipients)
+
+		entryRecipients.Disable()
+		selectRingSize.Disable()
+		selectBatch.Disable()
+		linkLoad.Hide()
+		progress.Show()
+		progress.SetValue(float64(sent) / float64(total))
+		results.Show()
+		listResults.Refresh()
+		linkExport.Show()
+
+		summary.Text = fmt.Sprintf("Sent: %d / %d   Failed: %d", sent, total, failed)
+		summary.Refresh()
+
+		switch {
+		case active:
+			btnStart.SetText("Pause Airdrop")
+			linkClear.Hide()
+		case sent == total:
+			btnStart.SetText("Airdrop Complete")
+			btnStart.Disable()
+			linkClear.Show()
+			return
+		case failed > 0:
+			btnStart.SetText("Retry Failed")
+			linkClear.Show()
+		default:
+			btnStart.SetText("Resume Airdrop")
+			linkClear.Show()
 		}
 
-		return
+		btnStart.Enable()
 	}
-	entryAddress.PlaceHolder = "0.0.0.0:10102"
-	entryAddress.SetText(getDaemon())
-	entryAddress.Refresh()
 
-	selectNodes := widget.NewSelect(nil, nil)
-	selectNodes.PlaceHolder = "Select Public Node ..."
-	switch session.Network {
-	case NETWORK_TESTNET:
-		selectNodes.Options = []string{"testnetexplorer.derofoundation.org:40402", "127.0.0.1:40402"}
-	case NETWORK_SIMULATOR:
-		selectNodes.Options = []string{"127.0.0.1:20000"}
-		selectNodes.PlaceHolder = "Select Simulator Node ..."
-	default:
-		selectNodes.Options = []string{"node.derofoundation.org:11012", "127.0.0.1:10102"}
-	}
-	selectNodes.OnChanged = func(s string) {
-		if s != "" {
-			err := setDaemon(s)
-			if err == nil {
-				entryAddress.Text = s
-				entryAddress.Refresh()
-			}
-			selectNodes.ClearSelected()
-		}
+	entryRecipients.OnChanged = func(s string) {
+		errorText.Text = ""
+		errorText.Refresh()
+		summary.Text = ""
+		summary.Refresh()
 	}
 
-	labelScan := widget.NewRichTextFromMarkdown("Enter the number of past blocks that the wallet should scan:")
-	labelScan.Wrapping = fyne.TextWrapWord
+	btnStart.OnTapped = func() {
+		errorText.Text = ""
+		errorText.Color = colors.Red
+		errorText.Refresh()
 
-	entryScan := widget.NewEntry()
-	entryScan.PlaceHolder = "# of Latest Blocks (Optional)"
-	entryScan.Validator = func(s string) (err error) {
-		blocks, err := strconv.ParseInt(s, 10, 64)
-		if err != nil {
-			entryScan.SetValidationError(err)
-		} else {
-			entryScan.SetValidationError(nil)
-			if blocks > 0 {
-				session.TrackRecentBlocks = blocks
+		if active {
+			airdrop.pause()
+			btnStart.SetText("Pausing...")
+			btnStart.Disable()
+			return
+		}
+
+		if len(job.Recipients) > 0 {
+			// Failed batches that have a TXID may still be mined, they are checked again like an interrupted batch before anything is resent
+			for i := range job.Recipients {
+				if job.Recipients[i].Status == AIRDROP_STATUS_FAILED {
+					if job.Recipients[i].TXID != "" {
+						job.Recipients[i].Status = AIRDROP_STATUS_SENDING
+					} else {
+						job.Recipients[i].Status = AIRDROP_STATUS_PENDING
+					}
+					job.Recipients[i].Error = ""
+				}
+			}
+
+			if err := airdrop.start(job); err != nil {
+				errorText.Text = err.Error()
+				errorText.Refresh()
+			}
+			refresh()
+			return
+		}
+
+		btnStart.SetText("Checking recipients...")
+		btnStart.Disable()
+
+		text := entryRecipients.Text
+		ringsize := uint64(16)
+		if result := regexp.MustCompile("[0-9]+").FindString(selectRingSize.Selected); result != "" {
+			ringsize, _ = strconv.ParseUint(result, 10, 64)
+		}
+		batchSize := DEFAULT_AIRDROP_BATCH_SIZE
+		if result := regexp.MustCompile("[0-9]+").FindString(selectBatch.Selected); result != "" {
+			batchSize, _ = strconv.Atoi(result)
+		}
+
+		// Usernames are resolved while parsing, so keep it off the UI thread
+		go func() {
+			recipients, total, err := parseAirdropRecipients(text, decimals)
+			if err == nil {
+				bal, _, e := engram.Disk.GetDecryptedBalanceAtTopoHeight(crypto.HashHexToHash(scid), -1, engram.Disk.GetAddress().String())
+				if e != nil {
+					err = e
+				} else if total > bal {
+					err = fmt.Errorf("total of %s exceeds the balance of %s", formatAssetAmount(total, decimals), formatAssetAmount(bal, decimals))
+				}
+			}
+
+			if err == nil {
+				err = airdrop.start(Airdrop{
+					SCID:       scid,
+					Ringsize:   ringsize,
+					BatchSize:  batchSize,
+					Created:    time.Now().Format(time.RFC822),
+					Recipients: recipients,
+				})
+			}
+
+			fyne.Do(func() {
+				if err != nil {
+					errorText.Text = err.Error()
+					errorText.Refresh()
+				}
+				refresh()
+			})
+		}()
+	}
+
+	linkLoad.OnTapped = func() {
+		dialogFileOpen := dialog.NewFileOpen(func(uri fyne.URIReadCloser, err error) {
+			if err != nil {
+				logger.Errorf("[Engram] File dialog: %s\n", err)
+				return
+			}
+
+			if uri == nil {
+				return // Canceled
+			}
+
+			data, err := readFromURI(uri)
+			if err != nil {
+				errorText.Text = "cannot read file data"
+				errorText.Refresh()
+				return
+			}
+
+			entryRecipients.SetText(string(data))
+		}, session.Window)
+
+		if !a.Driver().Device().IsMobile() {
+			// Open file browser in current directory
+			uri, err := storage.ListerForURI(storage.NewFileURI(AppPath()))
+			if err == nil {
+				dialogFileOpen.SetLocation(uri)
 			} else {
-				session.TrackRecentBlocks = 0
+				logger.Errorf("[Engram] Could not open current directory %s\n", err)
 			}
 		}
 
-		return
+		dialogFileOpen.SetView(dialog.ListView)
+		dialogFileOpen.Resize(fyne.NewSize(ui.Width, ui.Height))
+		dialogFileOpen.Show()
 	}
 
-	if session.TrackRecentBlocks > 0 {
-		blocks := strconv.FormatInt(session.TrackRecentBlocks, 10)
-		entryScan.Text = blocks
-		entryScan.Refresh()
+	linkExport.OnTapped = func() {
+		report := job.report(decimals)
+		dialogFileSave := dialog.NewFileSave(func(uri fyne.URIWriteCloser, err error) {
+			if err != nil {
+				logger.Errorf("[Engram] File dialog: %s\n", err)
+				return
+			}
+
+			if uri == nil {
+				return // Canceled
+			}
+
+			if _, err = writeToURI(report, uri); err != nil {
+				logger.Errorf("[Airdrop] Exporting report: %s\n", err)
+				errorText.Text = "error exporting report"
+				errorText.Color = colors.Red
+			} else {
+				errorText.Text = "exported report successfully"
+				errorText.Color = colors.Green
+			}
+			errorText.Refresh()
+		}, session.Window)
+
+		if !a.Driver().Device().IsMobile() {
+			// Open file browser in current directory
+			uri, err := storage.ListerForURI(storage.NewFileURI(AppPath()))
+			if err == nil {
+				dialogFileSave.SetLocation(uri)
+			} else {
+				logger.Errorf("[Engram] Could not open current directory %s\n", err)
+			}
+		}
+
+		dialogFileSave.SetView(dialog.ListView)
+		dialogFileSave.SetFileName("airdrop-" + scid[0:8] + "-" + time.Now().Format("2006-01-02") + ".csv")
+		dialogFileSave.Resize(fyne.NewSize(ui.Width, ui.Height))
+		dialogFileSave.Show()
 	}
 
-	radioNetwork := widget.NewRadioGroup([]string{NETWORK_MAINNET, NETWORK_TESTNET, NETWORK_SIMULATOR}, nil)
-	radioNetwork.Required = true
-	radioNetwork.Horizontal = false
-	radioNetwork.OnChanged = func(s string) {
-		if s == NETWORK_TESTNET {
-			setNetwork(s)
-			selectNodes.Options = []string{"testnetexplorer.derofoundation.org:40402", "127.0.0.1:40402"}
-			selectNodes.PlaceHolder = "Select Public Node ..."
-		} else if s == NETWORK_SIMULATOR {
-			setNetwork(s)
-			selectNodes.Options = []string{"127.0.0.1:20000"}
-			selectNodes.PlaceHolder = "Select Simulator Node ..."
+	linkClear.OnTapped = func() {
+		if active {
+			return
+		}
+
+		if err := deleteAirdrop(scid); err != nil {
+			logger.Errorf("[Airdrop] Clearing airdrop: %s\n", err)
+		}
+
+		airdrop.Lock()
+		if airdrop.Job.SCID == scid && !airdrop.Active {
+			airdrop.Job = Airdrop{}
+		}
+		airdrop.Unlock()
+
+		entryRecipients.SetText("")
+		refresh()
+	}
+
+	linkClose.OnTapped = func() {
+		airdrop.Lock()
+		airdrop.OnUpdate = nil
+		airdrop.Unlock()
+		done()
+	}
+
+	airdrop.Lock()
+	airdrop.OnUpdate = refresh
+	airdrop.Unlock()
+
+	refresh()
+
+	return container.NewCenter(
+		container.NewVBox(
+			span,
+			container.NewCenter(
+				header,
+			),
+			rectSpacer,
+			rectSpacer,
+			container.NewCenter(
+				title,
+			),
+			rectSpacer,
+			rectSpacer,
+			entryRecipients,
+			container.NewHBox(
+				layout.NewSpacer(),
+				linkLoad,
+				layout.NewSpacer(),
+			),
+			selectRingSize,
+			rectSpacer,
+			selectBatch,
+			rectSpacer,
+			rectSpacer,
+			progress,
+			summary,
+			results,
+			rectSpacer,
+			errorText,
+			rectSpacer,
+			btnStart,
+			rectSpacer,
+			container.NewHBox(
+				layout.NewSpacer(),
+				linkExport,
+				layout.NewSpacer(),
+				linkClear,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			container.NewHBox(
+				layout.NewSpacer(),
+				linkClose,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			rectSpacer,
+		),
+	)
+}
+
+// Export the transfer history of DERO or an asset as CSV
+func showHistoryExport(scid crypto.Hash, decimals uint64, status *canvas.Text) {
+	dialogFileSave := dialog.NewFileSave(func(uri fyne.URIWriteCloser, err error) {
+		if err != nil {
+			logger.Errorf("[Engram] File dialog: %s\n", err)
+			status.Text = "could not export history"
+			status.Color = colors.Red
+			status.Refresh()
+			return
+		}
+
+		if uri == nil {
+			return // Canceled
+		}
+
+		status.Text = "exporting history..."
+		status.Color = colors.Gray
+		status.Refresh()
+
+		// Assets are synced with the daemon before their history is read, so keep it off the UI thread
+		go func() {
+			data, err := exportTransferHistory(scid, decimals)
+			if err == nil {
+				_, err = writeToURI(data, uri)
+			} else {
+				uri.Close()
+			}
+
+			fyne.Do(func() {
+				if err != nil {
+					logger.Errorf("[History] Exporting history: %s\n", err)
+					status.Text = "error exporting history"
+					status.Color = colors.Red
+					status.Refresh()
+					return
+				}
+
+				status.Text = "exported history successfully"
+				status.Color = colors.Green
+				status.Refresh()
+			})
+		}()
+	}, session.Window)
+
+	if !a.Driver().Device().IsMobile() {
+		// Open file browser in current directory
+		uri, err := storage.ListerForURI(storage.NewFileURI(AppPath()))
+		if err == nil {
+			dialogFileSave.SetLocation(uri)
 		} else {
-			setNetwork(NETWORK_MAINNET)
-			selectNodes.Options = []string{"node.derofoundation.org:11012", "127.0.0.1:10102"}
-			selectNodes.PlaceHolder = "Select Public Node ..."
+			logger.Errorf("[Engram] Could not open current directory %s\n", err)
+		}
+	}
+
+	name := "history"
+	if !scid.IsZero() {
+		name += "-" + scid.String()[0:8]
+	}
+	name += "-" + time.Now().Format("2006-01-02") + ".csv"
+
+	dialogFileSave.SetView(dialog.ListView)
+	dialogFileSave.SetFileName(name)
+	dialogFileSave.Resize(fyne.NewSize(ui.Width, ui.Height))
+	dialogFileSave.Show()
+}
+
+// Transfer history of an asset for the asset manager, amounts are shown with the asset decimals
+func layoutAssetHistory(scid string, name string, done func()) *fyne.Container {
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
+
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.5))
+
+	rect := canvas.NewRectangle(color.Transparent)
+	rect.SetMinSize(fyne.NewSize(ui.Width*0.3, 35))
+
+	rectMid := canvas.NewRectangle(color.Transparent)
+	rectMid.SetMinSize(fyne.NewSize(ui.Width*0.35, 35))
+
+	header := canvas.NewText("ASSET  HISTORY", colors.Gray)
+	header.TextSize = 14
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
+
+	title := canvas.NewText(name, colors.Account)
+	title.TextSize = 22
+	title.Alignment = fyne.TextAlignCenter
+	title.TextStyle = fyne.TextStyle{Bold: true}
+
+	results := canvas.NewText("  Scanning...", colors.Green)
+	results.TextSize = 13
+
+	errorText := canvas.NewText("", colors.Green)
+	errorText.TextSize = 12
+	errorText.Alignment = fyne.TextAlignCenter
+
+	linkExport := widget.NewHyperlinkWithStyle("Export History", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClose := widget.NewHyperlinkWithStyle("Close", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	hash := crypto.HashHexToHash(scid)
+	decimals := assetMetadata.get(scid).Decimals
+
+	var entries []rpc.Entry
+
+	listBox := widget.NewList(
+		func() int {
+			return len(entries)
+		},
+		func() fyne.CanvasObject {
+			return container.NewHBox(
+				container.NewStack(
+					rect,
+					widget.NewLabel(""),
+				),
+				container.NewStack(
+					rectMid,
+					widget.NewLabel(""),
+				),
+				container.NewStack(
+					rect,
+					widget.NewLabel(""),
+				),
+			)
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id >= len(entries) {
+				return
+			}
+
+			e := entries[id]
+			direction := "Received"
+			amount := formatAssetAmount(e.Amount, decimals)
+			if !e.Incoming {
+				direction = "Sent"
+				amount = "(" + amount + ")"
+			}
+
+			co.(*fyne.Container).Objects[0].(*fyne.Container).Objects[1].(*widget.Label).SetText(direction)
+			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[1].(*widget.Label).SetText(amount)
+			co.(*fyne.Container).Objects[2].(*fyne.Container).Objects[1].(*widget.Label).SetText(e.Time.Format("2006-01-02"))
+		},
+	)
+	listBox.OnSelected = func(id widget.ListItemID) {
+		listBox.UnselectAll()
+		if id < len(entries) {
+			a.Clipboard().SetContent(entries[id].TXID)
+			errorText.Text = "TXID copied to clipboard"
+			errorText.Color = colors.Green
+			errorText.Refresh()
+		}
+	}
+
+	linkExport.OnTapped = func() {
+		showHistoryExport(hash, decimals, errorText)
+	}
+
+	linkClose.OnTapped = func() {
+		done()
+	}
+
+	// Adding the asset to the wallet can sync its history from the daemon, so keep it off the UI thread
+	go func() {
+		history := getTransferHistory(hash)
+		fyne.Do(func() {
+			entries = history
+			results.Text = fmt.Sprintf("  Results:  %d", len(entries))
+			results.Refresh()
+			listBox.Refresh()
+			listBox.ScrollToBottom()
+		})
+	}()
+
+	return container.NewCenter(
+		container.NewVBox(
+			span,
+			container.NewCenter(
+				header,
+			),
+			rectSpacer,
+			rectSpacer,
+			container.NewCenter(
+				title,
+			),
+			rectSpacer,
+			rectSpacer,
+			results,
+			rectSpacer,
+			container.NewStack(
+				rectList,
+				listBox,
+			),
+			rectSpacer,
+			errorText,
+			rectSpacer,
+			container.NewHBox(
+				layout.NewSpacer(),
+				linkExport,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			container.NewHBox(
+				layout.NewSpacer(),
+				linkClose,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			rectSpacer,
+		),
+	)
+}
+
+func layoutTransfers() fyne.CanvasObject {
+	session.Domain = "app.transfers"
+
+	wSpacer := widget.NewLabel(" ")
+
+	sendTitle := canvas.NewText("T R A N S F E R S", colors.Gray)
+	sendTitle.TextStyle = fyne.TextStyle{Bold: true}
+	sendTitle.TextSize = 16
+
+	sendDesc := canvas.NewText("", colors.Gray)
+	sendDesc.TextSize = 18
+	sendDesc.Alignment = fyne.TextAlignCenter
+	sendDesc.TextStyle = fyne.TextStyle{Bold: true}
+
+	sendHeading := canvas.NewText("S A V E D    T R A N S F E R S", colors.Gray)
+	sendHeading.TextSize = 16
+	sendHeading.Alignment = fyne.TextAlignCenter
+	sendHeading.TextStyle = fyne.TextStyle{Bold: true}
+
+	rectStatus := canvas.NewRectangle(color.Transparent)
+	rectStatus.SetMinSize(fyne.NewSize(10, 10))
+	rect := canvas.NewRectangle(color.Transparent)
+	rect.SetMinSize(fyne.NewSize(ui.Width, 20))
+	frame := &iframe{}
+	rect.SetMinSize(fyne.NewSize(ui.Width, 30))
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+	rect.SetMinSize(fyne.NewSize(10, 10))
+	rectEmpty := canvas.NewRectangle(color.Transparent)
+	rectEmpty.SetMinSize(fyne.NewSize(10, 10))
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(ui.Width, 35))
+	rectListBox := canvas.NewRectangle(color.Transparent)
+	rectListBox.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.53))
+
+	menuLabel := canvas.NewText("  M O R E   O P T I O N S  ", colors.Gray)
+	menuLabel.TextSize = 11
+	menuLabel.Alignment = fyne.TextAlignCenter
+	menuLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	sep := canvas.NewRectangle(colors.Gray)
+	sep.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+
+	line1 := container.NewVBox(
+		layout.NewSpacer(),
+		sep,
+		layout.NewSpacer(),
+	)
+
+	sep2 := canvas.NewRectangle(colors.Gray)
+	sep2.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+
+	line2 := container.NewVBox(
+		layout.NewSpacer(),
+		sep2,
+		layout.NewSpacer(),
+	)
+
+	linkBack := widget.NewHyperlinkWithStyle("Back to Dashboard", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkBack.OnTapped = func() {
+		session.LastDomain = session.Window.Content()
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutDashboard())
+		removeOverlays()
+	}
+
+	var pendingList []string
+
+	for i := 0; i < len(tx.Pending); i++ {
+		pendingList = append(pendingList, strconv.Itoa(i)+","+globals.FormatMoney(tx.Pending[i].Amount)+","+tx.Pending[i].Destination)
+	}
+
+	data := binding.BindStringList(&pendingList)
+
+	scrollBox := widget.NewListWithData(data,
+		func() fyne.CanvasObject {
+			c := container.NewStack(
+				rectList,
+				container.NewHBox(
+					canvas.NewText("", colors.Account),
+					layout.NewSpacer(),
+					canvas.NewText("", colors.Account),
+				),
+			)
+			return c
+		},
+		func(di binding.DataItem, co fyne.CanvasObject) {
+			dat := di.(binding.String)
+			str, err := dat.Get()
+			if err != nil {
+				return
+			}
+			dataItem := strings.SplitN(str, ",", 3)
+			dest := dataItem[2]
+			dest = "   " + dest[0:4] + " ... " + dest[len(dataItem[2])-10:]
+			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[0].(*canvas.Text).Text = dest
+			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[0].(*canvas.Text).TextSize = 17
+			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[0].(*canvas.Text).TextStyle.Bold = true
+			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[2].(*canvas.Text).Text = dataItem[1] + "   "
+			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[2].(*canvas.Text).TextSize = 17
+			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[2].(*canvas.Text).TextStyle.Bold = true
+		})
+
+	scrollBox.OnSelected = func(id widget.ListItemID) {
+		session.LastDomain = session.Window.Content()
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutTransfersDetail(id))
+	}
+
+	btnSend := widget.NewButton("Send Transfers", nil)
+
+	btnClear := widget.NewButton("Clear", func() {
+		pendingList = pendingList[:0]
+		tx = Transfers{}
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutTransfers())
+	})
+
+	if len(pendingList) > 0 {
+		btnClear.Enable()
+		btnSend.Enable()
+	} else {
+		btnClear.Disable()
+		btnSend.Disable()
+	}
+
+	if session.Offline {
+		btnSend.Text = "Disabled in Offline Mode"
+		btnSend.Disable()
+	}
+
+	btnSend.OnTapped = func() {
+		overlay := session.Window.Canvas().Overlays()
+
+		header := canvas.NewText("ACCOUNT  VERIFICATION  REQUIRED", colors.Gray)
+		header.TextSize = 14
+		header.Alignment = fyne.TextAlignCenter
+		header.TextStyle = fyne.TextStyle{Bold: true}
+
+		subHeader := canvas.NewText("Confirm Password", colors.Account)
+		subHeader.TextSize = 22
+		subHeader.Alignment = fyne.TextAlignCenter
+		subHeader.TextStyle = fyne.TextStyle{Bold: true}
+
+		linkClose := widget.NewHyperlinkWithStyle("Cancel", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+		linkClose.OnTapped = func() {
+			overlay := session.Window.Canvas().Overlays()
+			overlay.Top().Hide()
+			overlay.Remove(overlay.Top())
+			overlay.Remove(overlay.Top())
+		}
+
+		btnSubmit := widget.NewButton("Submit", nil)
+
+		entryPassword := NewReturnEntry()
+		entryPassword.Password = true
+		entryPassword.PlaceHolder = "Password"
+		entryPassword.OnChanged = func(s string) {
+			if s == "" {
+				btnSubmit.Text = "Submit"
+				btnSubmit.Disable()
+				btnSubmit.Refresh()
+			} else {
+				btnSubmit.Text = "Submit"
+				btnSubmit.Enable()
+				btnSubmit.Refresh()
+			}
+		}
+
+		btnSubmit.OnTapped = func() {
+			if engram.Disk.Check_Password(entryPassword.Text) {
+				removeOverlays()
+				if len(tx.Pending) == 0 {
+					return
+				} else {
+					btnSend.Text = "Setting up transfer..."
+					btnSend.Disable()
+					btnSend.Refresh()
+					txid, err := sendTransfers()
+					if err != nil {
+						btnSend.Text = "Send Transfers"
+						btnSend.Enable()
+						btnSend.Refresh()
+						return
+					}
+
+					go func() {
+						btnClear.Disable()
+						btnSend.Text = "Confirming..."
+						btnSend.Refresh()
+
+						walletapi.WaitNewHeightBlock()
+						sHeight := walletapi.Get_Daemon_Height()
+
+						for session.Domain == "app.transfers" {
+							var zeroscid crypto.Hash
+							_, result := engram.Disk.Get_Payments_TXID(zeroscid, txid.String())
+
+							if result.TXID == txid.String() {
+								btnSend.Text = "Transfer Successful!"
+								btnSend.Refresh()
+
+								break
+							}
+
+							// If we go DEFAULT_CONFIRMATION_TIMEOUT blocks without exiting 'Confirming...' loop, display failed to transfer and break
+							if walletapi.Get_Daemon_Height() > sHeight+int64(DEFAULT_CONFIRMATION_TIMEOUT) {
+								btnSend.Text = "Transfer failed..."
+								btnSend.Disable()
+								btnSend.Refresh()
+								break
+							}
+
+							// If daemon height has incremented, print retry counters into button space
+							if walletapi.Get_Daemon_Height()-sHeight > 0 {
+								btnSend.Text = fmt.Sprintf("Confirming... (%d/%d)", walletapi.Get_Daemon_Height()-sHeight, DEFAULT_CONFIRMATION_TIMEOUT)
+								btnSend.Refresh()
+							}
+
+							time.Sleep(time.Second * 1)
+						}
+					}()
+
+					pendingList = pendingList[:0]
+					data.Reload()
+					btnSend.Disable()
+					btnClear.Disable()
+				}
+			} else {
+				btnSubmit.Text = "Invalid Password..."
+				btnSubmit.Disable()
+				btnSubmit.Refresh()
+			}
+		}
+
+		btnSubmit.Disable()
+
+		entryPassword.OnReturn = btnSubmit.OnTapped
+
+		span := canvas.NewRectangle(color.Transparent)
+		span.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+		overlay.Add(
+			container.NewStack(
+				&iframe{},
+				canvas.NewRectangle(colors.DarkMatter),
+			),
+		)
+
+		overlay.Add(
+			container.NewStack(
+				&iframe{},
+				container.NewCenter(
+					container.NewVBox(
+						span,
+						container.NewCenter(
+							header,
+						),
+						rectSpacer,
+						rectSpacer,
+						subHeader,
+						widget.NewLabel(""),
+						container.NewCenter(
+							container.NewStack(
+								span,
+								entryPassword,
+							),
+						),
+						rectSpacer,
+						rectSpacer,
+						btnSubmit,
+						rectSpacer,
+						rectSpacer,
+						container.NewHBox(
+							layout.NewSpacer(),
+							linkClose,
+							layout.NewSpacer(),
+						),
+						rectSpacer,
+						rectSpacer,
+					),
+				),
+			),
+		)
+
+		session.Window.Canvas().Focus(entryPassword)
+	}
+
+	session.Window.Canvas().SetOnTypedKey(func(k *fyne.KeyEvent) {
+		if session.Domain != "app.transfers" {
+			return
+		}
+
+		if k.Name == fyne.KeyDown {
+			session.Dashboard = "main"
+			session.Window.SetContent(layoutTransition())
+			session.Window.SetContent(layoutDashboard())
+			removeOverlays()
+		}
+	})
+
+	sendForm := container.NewVBox(
+		rectSpacer,
+		rectSpacer,
+		sendHeading,
+		rectSpacer,
+		rectSpacer,
+		container.NewStack(
+			rectListBox,
+			scrollBox,
+		),
+		wSpacer,
+		btnSend,
+		rectSpacer,
+		btnClear,
+		rectSpacer,
+		rectSpacer,
+	)
+
+	gridItem1 := container.NewCenter(
+		sendForm,
+	)
+
+	gridItem2 := container.NewCenter()
+
+	gridItem3 := container.NewCenter()
+
+	gridItem4 := container.NewCenter()
+
+	gridItem1.Hidden = false
+	gridItem2.Hidden = true
+	gridItem3.Hidden = true
+	gridItem4.Hidden = true
+
+	features := container.NewCenter(
+		layout.NewSpacer(),
+		gridItem1,
+		layout.NewSpacer(),
+		gridItem2,
+		layout.NewSpacer(),
+		gridItem3,
+		layout.NewSpacer(),
+		gridItem4,
+		layout.NewSpacer(),
+	)
+
+	bottom := container.NewStack(
+		container.NewVBox(
+			container.NewStack(
+				container.NewHBox(
+					layout.NewSpacer(),
+					line1,
+					layout.NewSpacer(),
+					menuLabel,
+					layout.NewSpacer(),
+					line2,
+					layout.NewSpacer(),
+				),
+			),
+			rectSpacer,
+			rectSpacer,
+			container.NewCenter(
+				layout.NewSpacer(),
+				linkBack,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			rectSpacer,
+			rectSpacer,
+			rectSpacer,
+		),
+	)
+
+	c := container.NewBorder(
+		features,
+		bottom,
+		nil,
+		nil,
+	)
+
+	layout := container.NewStack(
+		frame,
+		c,
+	)
+
+	return NewVScroll(layout)
+}
+
+func layoutTransfersDetail(index int) fyne.CanvasObject {
+	wSpacer := widget.NewLabel(" ")
+
+	rectWidth := canvas.NewRectangle(color.Transparent)
+	rectWidth.SetMinSize(fyne.NewSize(ui.MaxWidth*0.99, 10))
+
+	rectWidth90 := canvas.NewRectangle(color.Transparent)
+	rectWidth90.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	frame := &iframe{}
+
+	heading := canvas.NewText("T R A N S F E R    D E T A I L", colors.Gray)
+	heading.TextSize = 16
+	heading.Alignment = fyne.TextAlignCenter
+	heading.TextStyle = fyne.TextStyle{Bold: true}
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
+
+	labelDestination := canvas.NewText("   RECEIVER  ADDRESS", colors.Gray)
+	labelDestination.TextSize = 14
+	labelDestination.Alignment = fyne.TextAlignLeading
+	labelDestination.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelAmount := canvas.NewText("   AMOUNT", colors.Gray)
+	labelAmount.TextSize = 14
+	labelAmount.Alignment = fyne.TextAlignLeading
+	labelAmount.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelService := canvas.NewText("   SERVICE  ADDRESS", colors.Gray)
+	labelService.TextSize = 14
+	labelService.Alignment = fyne.TextAlignLeading
+	labelService.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelDestPort := canvas.NewText("   DESTINATION  PORT", colors.Gray)
+	labelDestPort.TextSize = 14
+	labelDestPort.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelSourcePort := canvas.NewText("   SOURCE  PORT", colors.Gray)
+	labelSourcePort.TextSize = 14
+	labelSourcePort.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelFees := canvas.NewText("   TRANSACTION  FEES", colors.Gray)
+	labelFees.TextSize = 14
+	labelFees.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelPayload := canvas.NewText("   PAYLOAD", colors.Gray)
+	labelPayload.TextSize = 14
+	labelPayload.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelReply := canvas.NewText("   REPLY  ADDRESS", colors.Gray)
+	labelReply.TextSize = 14
+	labelReply.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelSeparator := widget.NewRichTextFromMarkdown("")
+	labelSeparator.Wrapping = fyne.TextWrapOff
+	labelSeparator.ParseMarkdown("---")
+
+	labelSeparator2 := widget.NewRichTextFromMarkdown("")
+	labelSeparator2.Wrapping = fyne.TextWrapOff
+	labelSeparator2.ParseMarkdown("---")
+
+	labelSeparator3 := widget.NewRichTextFromMarkdown("")
+	labelSeparator3.Wrapping = fyne.TextWrapOff
+	labelSeparator3.ParseMarkdown("---")
+
+	labelSeparator4 := widget.NewRichTextFromMarkdown("")
+	labelSeparator4.Wrapping = fyne.TextWrapOff
+	labelSeparator4.ParseMarkdown("---")
+
+	labelSeparator5 := widget.NewRichTextFromMarkdown("")
+	labelSeparator5.Wrapping = fyne.TextWrapOff
+	labelSeparator5.ParseMarkdown("---")
+
+	menuLabel := canvas.NewText("  M O R E   O P T I O N S  ", colors.Gray)
+	menuLabel.TextSize = 11
+	menuLabel.Alignment = fyne.TextAlignCenter
+	menuLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	sep := canvas.NewRectangle(colors.Gray)
+	sep.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+
+	line1 := container.NewVBox(
+		layout.NewSpacer(),
+		sep,
+		layout.NewSpacer(),
+	)
+
+	sep2 := canvas.NewRectangle(colors.Gray)
+	sep2.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+
+	line2 := container.NewVBox(
+		layout.NewSpacer(),
+		sep2,
+		layout.NewSpacer(),
+	)
+
+	details := tx.Pending[index]
+
+	valueDestination := widget.NewRichTextFromMarkdown("--")
+	valueDestination.Wrapping = fyne.TextWrapBreak
+
+	valueType := widget.NewRichTextFromMarkdown("--")
+	valueType.Wrapping = fyne.TextWrapOff
+
+	if details.Destination != "" {
+		address, _ := globals.ParseValidateAddress(details.Destination)
+		if address.IsIntegratedAddress() {
+			setAddressMarkdown(valueDestination, address.BaseAddress().String())
+			valueType.ParseMarkdown("### SERVICE")
+		} else {
+			setAddressMarkdown(valueDestination, details.Destination)
+			valueType.ParseMarkdown("### NORMAL")
+		}
+	}
+
+	valueReply := widget.NewRichTextFromMarkdown("--")
+	valueReply.Wrapping = fyne.TextWrapBreak
+
+	if details.Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
+		if details.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string) != "" {
+			valueReply.ParseMarkdown("" + details.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string))
+		}
+	}
+
+	valuePayload := widget.NewRichTextFromMarkdown("--")
+	valuePayload.Wrapping = fyne.TextWrapBreak
+
+	if details.Payload_RPC.HasValue(rpc.RPC_COMMENT, rpc.DataString) {
+		if details.Payload_RPC.Value(rpc.RPC_COMMENT, rpc.DataString).(string) != "" {
+			valuePayload.ParseMarkdown("" + details.Payload_RPC.Value(rpc.RPC_COMMENT, rpc.DataString).(string))
+		}
+	}
+
+	valueAmount := canvas.NewText("", colors.Account)
+	valueAmount.TextSize = 22
+	valueAmount.TextStyle = fyne.TextStyle{Bold: true}
+	valueAmount.Text = "  " + globals.FormatMoney(details.Amount)
+
+	valueDestPort := canvas.NewText("", colors.Account)
+	valueDestPort.TextSize = 22
+	valueDestPort.TextStyle = fyne.TextStyle{Bold: true}
+
+	if details.Payload_RPC.HasValue(rpc.RPC_DESTINATION_PORT, rpc.DataUint64) {
+		port := fmt.Sprintf("%d", details.Payload_RPC.Value(rpc.RPC_DESTINATION_PORT, rpc.DataUint64))
+		valueDestPort.Text = "  " + port
+	} else {
+		valueDestPort.Text = "  0"
+	}
+
+	linkBack := widget.NewHyperlinkWithStyle("Back to Transfers", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkBack.OnTapped = func() {
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutTransfers())
+	}
+
+	btnDelete := widget.NewButton("Cancel Transfer", nil)
+	btnDelete.OnTapped = func() {
+		if len(tx.Pending) > index+1 {
+			tx.Pending = append(tx.Pending[:index], tx.Pending[index+1:]...)
+		} else if len(tx.Pending) == 1 {
+			tx = Transfers{}
+		} else {
+			tx.Pending = tx.Pending[:index]
+		}
+
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutTransfers())
+	}
+
+	top := container.NewVBox(
+		rectSpacer,
+		rectSpacer,
+		container.NewCenter(
+			heading,
+		),
+		rectSpacer,
+		container.NewCenter(
+			valueType,
+		),
+		rectSpacer,
+		rectSpacer,
+	)
+
+	center := container.NewStack(
+		container.NewVScroll(
+			container.NewStack(
+				rectWidth,
+				container.NewHBox(
+					layout.NewSpacer(),
+					container.NewVBox(
+						rectSpacer,
+						rectSpacer,
+						labelDestination,
+						rectSpacer,
+						valueDestination,
+						rectSpacer,
+						rectSpacer,
+						labelSeparator,
+						rectSpacer,
+						rectSpacer,
+						labelAmount,
+						rectSpacer,
+						container.NewStack(
+							rectWidth90,
+							valueAmount,
+						),
+						rectSpacer,
+						rectSpacer,
+						labelSeparator2,
+						rectSpacer,
+						rectSpacer,
+						labelReply,
+						rectSpacer,
+						valueReply,
+						rectSpacer,
+						rectSpacer,
+						labelSeparator3,
+						rectSpacer,
+						rectSpacer,
+						labelPayload,
+						rectSpacer,
+						container.NewStack(
+							rectWidth90,
+							valuePayload,
+						),
+						rectSpacer,
+						rectSpacer,
+						labelSeparator4,
+						rectSpacer,
+						rectSpacer,
+						labelDestPort,
+						rectSpacer,
+						container.NewStack(
+							rectWidth90,
+							valueDestPort,
+						),
+						wSpacer,
+					),
+					layout.NewSpacer(),
+				),
+			),
+		),
+	)
+
+	bottom := container.NewStack(
+		container.NewVBox(
+			rectSpacer,
+			rectSpacer,
+			container.NewStack(
+				rectWidth,
+				container.NewHBox(
+					layout.NewSpacer(),
+					container.NewStack(
+						rectWidth90,
+						btnDelete,
+					),
+					layout.NewSpacer(),
+				),
+			),
+			rectSpacer,
+			rectSpacer,
+			rectSpacer,
+			container.NewStack(
+				container.NewHBox(
+					layout.NewSpacer(),
+					line1,
+					layout.NewSpacer(),
+					menuLabel,
+					layout.NewSpacer(),
+					line2,
+					layout.NewSpacer(),
+				),
+			),
+			rectSpacer,
+			rectSpacer,
+			container.NewCenter(
+				layout.NewSpacer(),
+				linkBack,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			rectSpacer,
+			rectSpacer,
+			rectSpacer,
+		),
+	)
+
+	layout := container.NewStack(
+		frame,
+		container.NewBorder(
+			top,
+			bottom,
+			nil,
+			center,
+		),
+	)
+
+	return NewVScroll(layout)
+}
+
+func layoutTransition() fyne.CanvasObject {
+	frame := &iframe{}
+	resizeWindow(ui.MaxWidth, ui.MaxHeight)
+
+	rect := canvas.NewRectangle(color.Transparent)
+	rect.SetMinSize(fyne.NewSize(ui.Width*0.45, ui.Width*0.45))
+
+	if res.loading == nil {
+		res.loading, _ = x.NewAnimatedGifFromResource(resourceLoadingGif)
+		res.loading.SetMinSize(fyne.NewSize(ui.Width*0.45, ui.Width*0.45))
+		res.loading.Resize(fyne.NewSize(ui.Width*0.45, ui.Width*0.45))
+	}
+
+	res.loading.Start()
+
+	layout := container.NewStack(
+		frame,
+		container.NewCenter(
+			rect,
+			res.loading,
+		),
+	)
+
+	return NewVScroll(layout)
+}
+
+func layoutSettings() fyne.CanvasObject {
+	stopGnomon()
+	rect := canvas.NewRectangle(color.Transparent)
+	rect.SetMinSize(fyne.NewSize(ui.Width, 10))
+	rectScroll := canvas.NewRectangle(color.Transparent)
+	rectScroll.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.65))
+	frame := &iframe{}
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	heading := canvas.NewText("My Settings", colors.Green)
+	heading.TextSize = 22
+	heading.Alignment = fyne.TextAlignCenter
+	heading.TextStyle = fyne.TextStyle{Bold: true}
+
+	labelNetwork := canvas.NewText("NETWORK", colors.Gray)
+	labelNetwork.TextStyle = fyne.TextStyle{Bold: true}
+	labelNetwork.TextSize = 14
+
+	labelNode := canvas.NewText("CONNECTION", colors.Gray)
+	labelNode.TextStyle = fyne.TextStyle{Bold: true}
+	labelNode.TextSize = 14
+
+	labelSecurity := canvas.NewText("SECURITY", colors.Gray)
+	labelSecurity.TextStyle = fyne.TextStyle{Bold: true}
+	labelSecurity.TextSize = 14
+
+	labelGnomon := canvas.NewText("GNOMON", colors.Gray)
+	labelGnomon.TextStyle = fyne.TextStyle{Bold: true}
+	labelGnomon.TextSize = 14
+
+	textGnomon := widget.NewRichTextWithText("Gnomon scans and indexes blockchain data in order to unlock more features, like native asset tracking.")
+	textGnomon.Wrapping = fyne.TextWrapWord
+
+	textSync := widget.NewRichTextFromMarkdown("")
+	textSync.Wrapping = fyne.TextWrapWord
+
+	// The checksum of a snapshot exported from this view is kept with the sync status
+	snapshotChecksum := ""
+	showSync := func(r GetSyncStatus_Result) {
+		md := formatGnomonSyncStatus(r)
+		if snapshotChecksum != "" {
+			md += fmt.Sprintf("* Snapshot SHA-256:  %s\n", snapshotChecksum)
+		}
+		textSync.ParseMarkdown(md)
+	}
+
+	refreshSync := func() {
+		showSync(getGnomonSyncStatus())
+	}
+
+	refreshSync()
+
+	textCyberdeck := widget.NewRichTextWithText("A username and password is required in order to allow application connectivity.")
+	textCyberdeck.Wrapping = fyne.TextWrapWord
+
+	btnRestore := widget.NewButton("Restore Defaults", nil)
+	btnDelete := widget.NewButton("Clear Local Data", nil)
+	btnExport := widget.NewButton("Export Index Snapshot", nil)
+	btnImport := widget.NewButton("Import Index Snapshot", nil)
+
+	entryChecksum := widget.NewEntry()
+	entryChecksum.PlaceHolder = "Snapshot SHA-256 Checksum"
+	entryChecksum.Validator = func(s string) error {
+		if _, err := hex.DecodeString(s); err != nil || len(s) != 64 {
+			return errors.New("invalid checksum")
+		}
+		return nil
+	}
+
+	entryAddress := widget.NewEntry()
+	entryAddress.Validator = func(s string) (err error) {
+		/*
+			_, err := net.ResolveTCPAddr("tcp", s)
+		*/
+		if validEndpoint(s) {
+			entryAddress.SetValidationError(nil)
+			setDaemon(s)
+		} else {
+			err = errors.New("invalid host name")
+			entryAddress.SetValidationError(err)
+		}
+
+		return
+	}
+	entryAddress.PlaceHolder = "0.0.0.0:10102"
+	entryAddress.SetText(getDaemon())
+	entryAddress.Refresh()
+
+	selectNodes := widget.NewSelect(nil, nil)
+	selectNodes.PlaceHolder = "Select Public Node ..."
+	switch session.Network {
+	case NETWORK_TESTNET:
+		selectNodes.Options = []string{"testnetexplorer.derofoundation.org:40402", "127.0.0.1:40402"}
+	case NETWORK_SIMULATOR:
+		selectNodes.Options = []string{"127.0.0.1:20000"}
+		selectNodes.PlaceHolder = "Select Simulator Node ..."
+	default:
+		selectNodes.Options = []string{"node.derofoundation.org:11012", "127.0.0.1:10102"}
+	}
+	selectNodes.OnChanged = func(s string) {
+		if s != "" {
+			err := setDaemon(s)
+			if err == nil {
+				entryAddress.Text = s
+				entryAddress.Refresh()
+			}
+			selectNodes.ClearSelected()
+		}
+	}
+
+	labelScan := widget.NewRichTextFromMarkdown("Enter the number of past blocks that the wallet should scan:")
+	labelScan.Wrapping = fyne.TextWrapWord
+
+	entryScan := widget.NewEntry()
+	entryScan.PlaceHolder = "# of Latest Blocks (Optional)"
+	entryScan.Validator = func(s string) (err error) {
+		blocks, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			entryScan.SetValidationError(err)
+		} else {
+			entryScan.SetValidationError(nil)
+			if blocks > 0 {
+				session.TrackRecentBlocks = blocks
+			} else {
+				session.TrackRecentBlocks = 0
+			}
+		}
+
+		return
+	}
+
+	if session.TrackRecentBlocks > 0 {
+		blocks := strconv.FormatInt(session.TrackRecentBlocks, 10)
+		entryScan.Text = blocks
+		entryScan.Refresh()
+	}
+
+	// Load the Gnomon indexing profile of the selected network, set once the profile form is built
+	var reloadProfile func(network string)
+
+	radioNetwork := widget.NewRadioGroup([]string{NETWORK_MAINNET, NETWORK_TESTNET, NETWORK_SIMULATOR}, nil)
+	radioNetwork.Required = true
+	radioNetwork.Horizontal = false
+	radioNetwork.OnChanged = func(s string) {
+		if s == NETWORK_TESTNET {
+			setNetwork(s)
+			selectNodes.Options = []string{"testnetexplorer.derofoundation.org:40402", "127.0.0.1:40402"}
+			selectNodes.PlaceHolder = "Select Public Node ..."
+		} else if s == NETWORK_SIMULATOR {
+			setNetwork(s)
+			selectNodes.Options = []string{"127.0.0.1:20000"}
+			selectNodes.PlaceHolder = "Select Simulator Node ..."
+		} else {
+			setNetwork(NETWORK_MAINNET)
+			selectNodes.Options = []string{"node.derofoundation.org:11012", "127.0.0.1:10102"}
+			selectNodes.PlaceHolder = "Select Public Node ..."
+		}
+
+		// Change globals.Config mainnet/testnet to match network
+		globals.InitNetwork()
+
+		selectNodes.Refresh()
+
+		if reloadProfile != nil {
+			reloadProfile(session.Network)
+			refreshSync()
+		}
+	}
+
+	net, _ := GetValue("settings", []byte("network"))
+
+	if string(net) == NETWORK_TESTNET {
+		radioNetwork.SetSelected(NETWORK_TESTNET)
+	} else if string(net) == NETWORK_SIMULATOR {
+		radioNetwork.SetSelected(NETWORK_SIMULATOR)
+	} else {
+		radioNetwork.SetSelected(NETWORK_MAINNET)
+	}
+
+	radioNetwork.Refresh()
+
+	entryUser := widget.NewEntry()
+	entryUser.PlaceHolder = "Username"
+	entryUser.SetText(cyberdeck.RPC.user)
+
+	entryPass := widget.NewEntry()
+	entryPass.PlaceHolder = "Password"
+	entryPass.Password = true
+	entryPass.SetText(cyberdeck.RPC.pass)
+
+	entryUser.OnChanged = func(s string) {
+		cyberdeck.RPC.user = s
+	}
+
+	entryPass.OnChanged = func(s string) {
+		cyberdeck.RPC.pass = s
+	}
+
+	entryGnomonEndpoint := widget.NewEntry()
+	entryGnomonEndpoint.PlaceHolder = "Gnomon Node (Optional, Default: Wallet Node)"
+	entryGnomonEndpoint.Validator = func(s string) (err error) {
+		if s != "" && !validEndpoint(s) {
+			err = errors.New("invalid host name")
+			entryGnomonEndpoint.SetValidationError(err)
+			return
+		}
+
+		entryGnomonEndpoint.SetValidationError(nil)
+
+		return
+	}
+
+	// Text fields are saved once typing pauses rather than on every keystroke
+	var endpointTimer *time.Timer
+	entryGnomonEndpoint.OnChanged = func(s string) {
+		if endpointTimer != nil {
+			endpointTimer.Stop()
+		}
+
+		endpointTimer = time.AfterFunc(time.Second, func() {
+			fyne.Do(func() {
+				if entryGnomonEndpoint.Validate() == nil {
+					setGnomonEndpoint(session.Network, entryGnomonEndpoint.Text)
+				}
+			})
+		})
+	}
+
+	textProfile := widget.NewRichTextWithText("The indexing profile is saved for each network and applied the next time Gnomon starts. Changing the storage backend starts a new index.")
+	textProfile.Wrapping = fyne.TextWrapWord
+
+	profileStatus := canvas.NewText("", colors.Green)
+	profileStatus.TextSize = 12
+
+	selectProfile := widget.NewSelect([]string{GNOMON_PROFILE_LIGHT, GNOMON_PROFILE_FULL, GNOMON_PROFILE_CUSTOM}, nil)
+	selectProfile.PlaceHolder = "Indexing Profile ..."
+
+	entryFilters := widget.NewMultiLineEntry()
+	entryFilters.PlaceHolder = "Search Filters (one per line)"
+	entryFilters.Wrapping = fyne.TextWrapOff
+
+	entryExclusions := widget.NewMultiLineEntry()
+	entryExclusions.PlaceHolder = "Excluded SCIDs (one per line)"
+	entryExclusions.Wrapping = fyne.TextWrapOff
+
+	entryInclusions := widget.NewMultiLineEntry()
+	entryInclusions.PlaceHolder = "Included SCIDs (one per line)"
+	entryInclusions.Wrapping = fyne.TextWrapOff
+
+	checkFastSync := widget.NewCheck("Enable fastsync", nil)
+	checkForceFastSync := widget.NewCheck("Force fastsync", nil)
+	checkSkipRecheck := widget.NewCheck("Skip fastsync recheck", nil)
+	checkNoCode := widget.NewCheck("Skip smart contract code", nil)
+
+	entryDiff := widget.NewEntry()
+	entryDiff.PlaceHolder = "Force Fastsync Difference (Blocks)"
+
+	var parallel []string
+	for i := 1; i <= DEFAULT_GNOMON_MAX_PARALLEL; i++ {
+		parallel = append(parallel, strconv.Itoa(i))
+	}
+
+	selectParallel := widget.NewSelect(parallel, nil)
+	selectParallel.PlaceHolder = "Parallel Blocks ..."
+
+	selectBackend := widget.NewSelect([]string{"gravdb", "boltdb"}, nil)
+	selectBackend.PlaceHolder = "Storage Backend ..."
+
+	// Split a list entry by lines, and commas when sep is true
+	splitList := func(s string, sep bool) (list []string) {
+		if sep {
+			s = strings.ReplaceAll(s, ",", "\n")
+		}
+
+		for _, l := range strings.Split(s, "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				list = append(list, l)
+			}
+		}
+
+		return
+	}
+
+	loading := false
+
+	loadProfile := func(p GnomonProfile) {
+		loading = true
+		selectProfile.SetSelected(p.Preset)
+		entryFilters.SetText(strings.Join(p.SearchFilters, "\n"))
+		entryExclusions.SetText(strings.Join(p.Exclusions, "\n"))
+		entryInclusions.SetText(strings.Join(p.Inclusions, "\n"))
+		checkFastSync.SetChecked(p.FastSync)
+		checkForceFastSync.SetChecked(p.ForceFastSync)
+		checkSkipRecheck.SetChecked(p.SkipFSRecheck)
+		checkNoCode.SetChecked(p.NoCode)
+		entryDiff.SetText(strconv.FormatInt(p.FastSyncDiff, 10))
+		selectParallel.SetSelected(strconv.Itoa(p.Parallel))
+		selectBackend.SetSelected(p.Backend)
+		loading = false
+	}
+
+	saveProfile := func() {
+		p := GnomonProfile{
+			Preset:        selectProfile.Selected,
+			SearchFilters: splitList(entryFilters.Text, false),
+			Exclusions:    splitList(entryExclusions.Text, true),
+			Inclusions:    splitList(entryInclusions.Text, true),
+			FastSync:      checkFastSync.Checked,
+			ForceFastSync: checkForceFastSync.Checked,
+			SkipFSRecheck: checkSkipRecheck.Checked,
+			NoCode:        checkNoCode.Checked,
+			Backend:       selectBackend.Selected,
+		}
+
+		var err error
+		p.Parallel, _ = strconv.Atoi(selectParallel.Selected)
+		if p.FastSyncDiff, err = strconv.ParseInt(entryDiff.Text, 10, 64); err != nil {
+			err = errors.New("invalid fastsync difference")
+		} else {
+			err = setGnomonProfile(session.Network, p)
+		}
+
+		if err != nil {
+			profileStatus.Color = colors.Red
+			profileStatus.Text = "Error: " + err.Error()
+			profileStatus.Refresh()
+			return
+		}
+
+		profileStatus.Color = colors.Green
+		profileStatus.Text = fmt.Sprintf("%s indexing profile saved.", session.Network)
+		profileStatus.Refresh()
+	}
+
+	// Any edit of the profile fields makes it a custom profile
+	changed := func() {
+		if loading {
+			return
+		}
+
+		loading = true
+		selectProfile.SetSelected(GNOMON_PROFILE_CUSTOM)
+		loading = false
+		saveProfile()
+	}
+
+	var profileTimer *time.Timer
+	typed := func() {
+		if loading {
+			return
+		}
+
+		loading = true
+		selectProfile.SetSelected(GNOMON_PROFILE_CUSTOM)
+		loading = false
+
+		if profileTimer != nil {
+			profileTimer.Stop()
+		}
+
+		profileTimer = time.AfterFunc(time.Second, func() {
+			fyne.Do(saveProfile)
+		})
+	}
+
+	selectProfile.OnChanged = func(s string) {
+		if loading || s == GNOMON_PROFILE_CUSTOM {
+			return
+		}
+
+		loadProfile(gnomonPreset(s))
+		saveProfile()
+	}
+
+	entryFilters.OnChanged = func(s string) { typed() }
+	entryExclusions.OnChanged = func(s string) { typed() }
+	entryInclusions.OnChanged = func(s string) { typed() }
+	checkFastSync.OnChanged = func(b bool) { changed() }
+	checkForceFastSync.OnChanged = func(b bool) { changed() }
+	checkSkipRecheck.OnChanged = func(b bool) { changed() }
+	checkNoCode.OnChanged = func(b bool) { changed() }
+	entryDiff.OnChanged = func(s string) { typed() }
+	selectParallel.OnChanged = func(s string) { changed() }
+	selectBackend.OnChanged = func(s string) { changed() }
+
+	reloadProfile = func(network string) {
+		endpoint, _ := GetValue("settings", []byte("gnomon.endpoint."+network))
+		entryGnomonEndpoint.SetText(string(endpoint))
+		loadProfile(getGnomonProfile(network))
+		profileStatus.Text = ""
+		profileStatus.Refresh()
+	}
+
+	reloadProfile(session.Network)
+
+	formProfile := container.NewVBox(
+		rectSpacer,
+		entryGnomonEndpoint,
+		rectSpacer,
+		textProfile,
+		rectSpacer,
+		selectProfile,
+		rectSpacer,
+		entryFilters,
+		rectSpacer,
+		entryExclusions,
+		rectSpacer,
+		entryInclusions,
+		rectSpacer,
+		checkFastSync,
+		checkForceFastSync,
+		checkSkipRecheck,
+		checkNoCode,
+		rectSpacer,
+		entryDiff,
+		rectSpacer,
+		selectParallel,
+		rectSpacer,
+		selectBackend,
+		rectSpacer,
+		profileStatus,
+	)
+
+	checkGnomon := widget.NewCheck("Enable Gnomon", nil)
+	checkGnomon.OnChanged = func(b bool) {
+		if b {
+			StoreValue("settings", []byte("gnomon"), []byte("1"))
+			checkGnomon.Checked = true
+			gnomon.Active = 1
+			formProfile.Show()
+		} else {
+			StoreValue("settings", []byte("gnomon"), []byte("0"))
+			checkGnomon.Checked = false
+			gnomon.Active = 0
+			formProfile.Hide()
+		}
+	}
+
+	gmn, err := GetValue("settings", []byte("gnomon"))
+	if err != nil {
+		gnomon.Active = 1
+		StoreValue("settings", []byte("gnomon"), []byte("1"))
+		checkGnomon.Checked = true
+	}
+
+	if string(gmn) == "1" {
+		checkGnomon.Checked = true
+	} else {
+		checkGnomon.Checked = false
+		formProfile.Hide()
+	}
+
+	labelBack := widget.NewHyperlinkWithStyle("Return to Login", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	labelBack.OnTapped = func() {
+		network := radioNetwork.Selected
+		if network == NETWORK_TESTNET {
+			setNetwork(network)
+		} else if network == NETWORK_SIMULATOR {
+			setNetwork(network)
+		} else {
+			setNetwork(NETWORK_MAINNET)
+		}
+		setDaemon(entryAddress.Text)
+
+		initSettings()
+
+		resizeWindow(ui.MaxWidth, ui.MaxHeight)
+		session.LastDomain = session.Window.Content()
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutMain())
+		removeOverlays()
+	}
+
+	btnRestore.OnTapped = func() {
+		setNetwork(NETWORK_MAINNET)
+		setDaemon(DEFAULT_REMOTE_DAEMON)
+		setAuthMode("true")
+		setGnomon("1")
+		for _, network := range []string{NETWORK_MAINNET, NETWORK_TESTNET, NETWORK_SIMULATOR} {
+			setGnomonProfile(network, gnomonPreset(GNOMON_PROFILE_LIGHT))
+			setGnomonEndpoint(network, "")
+		}
+
+		resizeWindow(ui.MaxWidth, ui.MaxHeight)
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutSettings())
+		removeOverlays()
+	}
+
+	statusText := canvas.NewText("", colors.Account)
+	statusText.TextSize = 12
+
+	btnDelete.OnTapped = func() {
+		err := cleanGnomonData()
+		if err != nil {
+			if parseError, ok := err.(*os.PathError); !ok {
+				err = fmt.Errorf("error clearing local %s data", session.Network)
+			} else {
+				err = parseError.Err
+			}
+
+			statusText.Color = colors.Red
+			statusText.Text = err.Error()
+			statusText.Refresh()
+			return
+		}
+
+		statusText.Color = colors.Green
+		statusText.Text = fmt.Sprintf("Gnomon %s data successfully deleted.", strings.ToLower(session.Network))
+		statusText.Refresh()
+
+		gnomon.Progress.clear()
+		refreshSync()
+	}
+
+	btnExport.OnTapped = func() {
+		dialogFileSave := dialog.NewFileSave(func(uri fyne.URIWriteCloser, err error) {
+			if err != nil {
+				logger.Errorf("[Engram] File dialog: %s\n", err)
+				statusText.Color = colors.Red
+				statusText.Text = "could not export snapshot"
+				statusText.Refresh()
+				return
+			}
+
+			if uri == nil {
+				return // Canceled
+			}
+
+			statusText.Color = colors.Yellow
+			statusText.Text = "Exporting Gnomon snapshot..."
+			statusText.Refresh()
+			btnExport.Disable()
+			btnImport.Disable()
+
+			go func() {
+				snapshot, checksum, err := exportGnomonSnapshot(uri)
+				uri.Close()
+
+				fyne.Do(func() {
+					btnExport.Enable()
+					btnImport.Enable()
+
+					if err != nil {
+						logger.Errorf("[Gnomon] Exporting snapshot: %s\n", err)
+						statusText.Color = colors.Red
+						statusText.Text = err.Error()
+						statusText.Refresh()
+						return
+					}
+
+					statusText.Color = colors.Green
+					statusText.Text = fmt.Sprintf("Exported snapshot at height %d.", snapshot.Height)
+					statusText.Refresh()
+					snapshotChecksum = checksum
+					refreshSync()
+				})
+			}()
+		}, session.Window)
+
+		if !a.Driver().Device().IsMobile() {
+			// Open file browser in current directory
+			uri, err := storage.ListerForURI(storage.NewFileURI(AppPath()))
+			if err == nil {
+				dialogFileSave.SetLocation(uri)
+			} else {
+				logger.Errorf("[Engram] Could not open current directory %s\n", err)
+			}
+		}
+
+		dialogFileSave.SetView(dialog.ListView)
+		dialogFileSave.SetFileName(fmt.Sprintf("gnomon-%s-%s.tar.gz", strings.ToLower(session.Network), time.Now().Format("2006-01-02")))
+		dialogFileSave.Resize(fyne.NewSize(ui.Width, ui.Height))
+		dialogFileSave.Show()
+	}
+
+	btnImport.OnTapped = func() {
+		// The checksum shown when the snapshot was exported has to be given before the archive is trusted
+		checksum := strings.TrimSpace(entryChecksum.Text)
+		if entryChecksum.Validate() != nil {
+			statusText.Color = colors.Red
+			statusText.Text = "enter the SHA-256 checksum of the snapshot"
+			statusText.Refresh()
+			return
+		}
+
+		dialogFileImport := dialog.NewFileOpen(func(uri fyne.URIReadCloser, err error) {
+			if err != nil {
+				logger.Errorf("[Engram] File dialog: %s\n", err)
+				statusText.Color = colors.Red
+				statusText.Text = "could not import snapshot"
+				statusText.Refresh()
+				return
+			}
+
+			if uri == nil {
+				return // Canceled
+			}
+
+			statusText.Color = colors.Yellow
+			statusText.Text = "Verifying Gnomon snapshot..."
+			statusText.Refresh()
+			btnExport.Disable()
+			btnImport.Disable()
+
+			go func() {
+				snapshot, err := importGnomonSnapshot(uri, checksum)
+				uri.Close()
+
+				fyne.Do(func() {
+					btnExport.Enable()
+					btnImport.Enable()
+
+					if err != nil {
+						logger.Errorf("[Gnomon] Importing snapshot: %s\n", err)
+						statusText.Color = colors.Red
+						statusText.Text = err.Error()
+						statusText.Refresh()
+						return
+					}
+
+					statusText.Color = colors.Green
+					statusText.Text = fmt.Sprintf("Imported snapshot, indexing resumes from height %d.", snapshot.Height)
+					statusText.Refresh()
+					reloadProfile(session.Network)
+					refreshSync()
+				})
+			}()
+		}, session.Window)
+
+		if !a.Driver().Device().IsMobile() {
+			// Open file browser in current directory
+			uri, err := storage.ListerForURI(storage.NewFileURI(AppPath()))
+			if err == nil {
+				dialogFileImport.SetLocation(uri)
+			} else {
+				logger.Errorf("[Engram] Could not open current directory %s\n", err)
+			}
+		}
+
+		dialogFileImport.SetView(dialog.ListView)
+		dialogFileImport.Resize(fyne.NewSize(ui.Width, ui.Height))
+		dialogFileImport.Show()
+	}
+
+	formSettings := container.NewVBox(
+		labelNetwork,
+		rectSpacer,
+		radioNetwork,
+		widget.NewLabel(""),
+		labelNode,
+		rectSpacer,
+		rectSpacer,
+		selectNodes,
+		rectSpacer,
+		entryAddress,
+		rectSpacer,
+		rectSpacer,
+		labelScan,
+		rectSpacer,
+		entryScan,
+		widget.NewLabel(""),
+		labelSecurity,
+		rectSpacer,
+		textCyberdeck,
+		rectSpacer,
+		entryUser,
+		rectSpacer,
+		entryPass,
+		rectSpacer,
+		widget.NewLabel(""),
+		labelGnomon,
+		rectSpacer,
+		textGnomon,
+		rectSpacer,
+		checkGnomon,
+		rectSpacer,
+		textSync,
+		formProfile,
+		rectSpacer,
+		statusText,
+		rectSpacer,
+		rectSpacer,
+		btnExport,
+		rectSpacer,
+		entryChecksum,
+		rectSpacer,
+		btnImport,
+		rectSpacer,
+		btnDelete,
+		rectSpacer,
+		btnRestore,
+	)
+
+	scrollBox := container.NewVScroll(
+		container.NewHBox(
+			layout.NewSpacer(),
+			container.NewStack(
+				rectScroll,
+				formSettings,
+			),
+			layout.NewSpacer(),
+		),
+	)
+
+	scrollBox.SetMinSize(fyne.NewSize(ui.MaxWidth, ui.Height*0.68))
+
+	gridItem1 := container.NewCenter(
+		container.NewVBox(
+			widget.NewLabel(""),
+			heading,
+			widget.NewLabel(""),
+			scrollBox,
+			rectSpacer,
+			rectSpacer,
+		),
+	)
+
+	features := container.NewCenter(
+		layout.NewSpacer(),
+		gridItem1,
+		layout.NewSpacer(),
+	)
+
+	footer := container.NewVBox(
+		container.NewHBox(
+			layout.NewSpacer(),
+			labelBack,
+			layout.NewSpacer(),
+		),
+		widget.NewLabel(" "),
+	)
+
+	c := container.NewBorder(
+		features,
+		footer,
+		nil,
+		nil,
+	)
+
+	layout := container.NewStack(
+		frame,
+		c,
+	)
+
+	scroll := NewVScroll(layout)
+
+	// Keep the Gnomon sync status live while the settings are shown
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			result := getGnomonSyncStatus()
+
+			shown := true
+			fyne.DoAndWait(func() {
+				if session.Window.Content() != scroll {
+					shown = false
+					return
+				}
+
+				showSync(result)
+			})
+
+			if !shown {
+				return
+			}
+		}
+	}()
+
+	return scroll
+}
+
+func layoutMessages() fyne.CanvasObject {
+	session.Domain = "app.messages"
+
+	if !walletapi.Connected {
+		session.Window.SetContent(layoutSettings())
+	}
+
+	title := canvas.NewText("M Y    C O N T A C T S", colors.Gray)
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	title.TextSize = 16
+
+	menuLabel := canvas.NewText("  M O R E   O P T I O N S  ", colors.Gray)
+	menuLabel.TextSize = 11
+	menuLabel.Alignment = fyne.TextAlignCenter
+	menuLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	checkLimit := widget.NewCheck(" Show only recent messages", nil)
+	checkLimit.OnChanged = func(b bool) {
+		if b {
+			session.LimitMessages = true
+			session.Window.SetContent(layoutTransition())
+			session.Window.SetContent(layoutMessages())
+			removeOverlays()
+		} else {
+			session.LimitMessages = false
+			session.Window.SetContent(layoutTransition())
+			session.Window.SetContent(layoutMessages())
+			removeOverlays()
+		}
+	}
+
+	if session.LimitMessages {
+		checkLimit.Checked = true
+	}
+
+	getHideUnverified()
+
+	checkVerified := widget.NewCheck(" Hide unverified senders", nil)
+	checkVerified.Checked = messages.HideUnverified
+	checkVerified.OnChanged = func(b bool) {
+		err := setHideUnverified(b)
+		if err != nil {
+			logger.Errorf("[Message] Storing hide unverified setting: %s\n", err)
+		}
+
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutMessages())
+		removeOverlays()
+	}
+
+	archiveText := canvas.NewText("", colors.Green)
+	archiveText.TextSize = 12
+	archiveText.Alignment = fyne.TextAlignCenter
+
+	selectArchive := widget.NewSelect([]string{"Export All (Encrypted)", "Export All (Markdown)", "Import Archive"}, nil)
+	selectArchive.PlaceHolder = "Message Archive ..."
+	selectArchive.OnChanged = func(s string) {
+		if s == "" {
+			return
+		}
+
+		selectArchive.ClearSelected()
+
+		switch s {
+		case "Export All (Encrypted)":
+			showMessageExport("", false, archiveText)
+		case "Export All (Markdown)":
+			showMessageExport("", true, archiveText)
+		case "Import Archive":
+			showMessageImport(archiveText, func() {
+				session.Window.SetContent(layoutTransition())
+				session.Window.SetContent(layoutMessages())
+				removeOverlays()
+			})
+		}
+	}
+
+	checkQuarantine := widget.NewCheck(" Show quarantined senders", nil)
+	checkQuarantine.Checked = messages.ShowQuarantine
+	checkQuarantine.Hidden = !getMessageFilters().QuarantineUnknown
+	checkQuarantine.OnChanged = func(b bool) {
+		messages.ShowQuarantine = b
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutMessages())
+		removeOverlays()
+	}
+
+	sep := canvas.NewRectangle(colors.Gray)
+	sep.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+
+	line1 := container.NewVBox(
+		layout.NewSpacer(),
+		sep,
+		layout.NewSpacer(),
+	)
+
+	sep2 := canvas.NewRectangle(colors.Gray)
+	sep2.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
+
+	line2 := container.NewVBox(
+		layout.NewSpacer(),
+		sep2,
+		layout.NewSpacer(),
+	)
+
+	linkBack := widget.NewHyperlinkWithStyle("Back to Dashboard", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkBack.OnTapped = func() {
+		session.LastDomain = session.Window.Content()
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutDashboard())
+		removeOverlays()
+	}
+
+	rectStatus := canvas.NewRectangle(color.Transparent)
+	rectStatus.SetMinSize(fyne.NewSize(10, 10))
+	rectEmpty := canvas.NewRectangle(color.Transparent)
+	rectEmpty.SetMinSize(fyne.NewSize(10, 10))
+	rect := canvas.NewRectangle(color.Transparent)
+	rect.SetMinSize(fyne.NewSize(ui.Width, 20))
+	frame := &iframe{}
+	rect.SetMinSize(fyne.NewSize(ui.Width, 30))
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+	rect.SetMinSize(fyne.NewSize(10, 10))
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(ui.Width, 35))
+	rectListBox := canvas.NewRectangle(color.Transparent)
+	rectListBox.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.43))
+
+	messages.Data = nil
+
+	var height uint64
+
+	if session.LimitMessages {
+		height = engram.Disk.Get_Height() - 1000000
+	} else {
+		height = 0
+	}
+
+	data := getMessages(height)
+	temp := data
+
+	list := binding.BindStringList(&data)
+
+	msgbox.List = widget.NewListWithData(list,
+		func() fyne.CanvasObject {
+			c := container.NewVBox(
+				widget.NewLabel(""),
+			)
+			return c
+		},
+		func(di binding.DataItem, co fyne.CanvasObject) {
+			dat := di.(binding.String)
+			str, err := dat.Get()
+			if err != nil {
+				return
+			}
+			dataItem := strings.Split(str, "~~~")
+			short := dataItem[0]
+			address := short[len(short)-DEFAULT_USERADDR_SHORTEN_LENGTH:]
+			username := dataItem[1]
+			// If a username is longer than what *would* be a 'short' address of ...xyzxyzxyzx (e.g. 13), then shorten as well to be similar sizing
+			if len(username) > DEFAULT_USERADDR_SHORTEN_LENGTH+3 {
+				username = "..." + username[len(username)-DEFAULT_USERADDR_SHORTEN_LENGTH:]
+			}
+
+			if username == "" {
+				username = addressUsername(short, msgbox.List.Refresh)
+				if len(username) > DEFAULT_USERADDR_SHORTEN_LENGTH+3 {
+					username = "..." + username[len(username)-DEFAULT_USERADDR_SHORTEN_LENGTH:]
+				}
+			}
+
+			if username == "" {
+				co.(*fyne.Container).Objects[0].(*widget.Label).SetText("..." + address)
+			} else {
+				co.(*fyne.Container).Objects[0].(*widget.Label).SetText(username)
+			}
+			co.(*fyne.Container).Objects[0].(*widget.Label).Wrapping = fyne.TextWrapWord
+			co.(*fyne.Container).Objects[0].(*widget.Label).TextStyle.Bold = false
+			co.(*fyne.Container).Objects[0].(*widget.Label).Alignment = fyne.TextAlignLeading
+		})
+
+	msgbox.List.OnSelected = func(id widget.ListItemID) {
+		msgbox.List.UnselectAll()
+		split := strings.Split(data[id], "~~~")
+		if split[1] == "" {
+			messages.Contact = split[0]
+		} else {
+			messages.Contact = split[1]
+		}
+
+		session.LastDomain = session.Window.Content()
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutPM())
+		removeOverlays()
+	}
+
+	searchList := []string{}
+
+	entrySearch := widget.NewEntry()
+	entrySearch.PlaceHolder = "Search for a Contact"
+	entrySearch.OnChanged = func(s string) {
+		s = strings.ToLower(s)
+		searchList = []string{}
+		if s == "" {
+			data = temp
+			list.Reload()
+		} else {
+			for _, d := range temp {
+				tempd := strings.ToLower(d)
+				split := strings.Split(tempd, "~~~")
+
+				if split[1] == "" {
+					if strings.Contains(split[0], s) {
+						searchList = append(searchList, d)
+					}
+				} else {
+					if strings.Contains(split[1], s) {
+						searchList = append(searchList, d)
+					}
+				}
+			}
+
+			data = searchList
+			list.Reload()
+		}
+	}
+
+	btnSend := widget.NewButton("New Message", func() {
+		_, err := globals.ParseValidateAddress(messages.Contact)
+		if err != nil {
+			//_, err := engram.Disk.NameToAddress(messages.Contact)
+			_, err := checkUsername(messages.Contact, -1)
+			if err != nil {
+				return
+			}
+		}
+
+		session.LastDomain = session.Window.Content()
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutPM())
+		removeOverlays()
+	})
+	btnSend.Disable()
+
+	entryDest := widget.NewEntry()
+	entryDest.MultiLine = false
+	entryDest.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
+	entryDest.PlaceHolder = "Username or Address"
+	entryDest.Validator = func(s string) error {
+		if len(s) > 0 {
+			_, err := globals.ParseValidateAddress(s)
+			if err != nil {
+				btnSend.Disable()
+				//_, err := engram.Disk.NameToAddress(s)
+				_, err = checkUsername(s, -1)
+				if err != nil {
+					btnSend.Disable()
+					return errors.New("invalid username or address")
+				} else {
+					messages.Contact = s
+					btnSend.Enable()
+					return nil
+				}
+			} else {
+				btnSend.Enable()
+				messages.Contact = s
+				return nil
+			}
+		}
+
+		return errors.New("invalid username or address")
+	}
+
+	messageForm := container.NewVBox(
+		rectSpacer,
+		rectSpacer,
+		container.NewHBox(
+			layout.NewSpacer(),
+			title,
+			layout.NewSpacer(),
+		),
+		rectSpacer,
+		rectSpacer,
+		entrySearch,
+		rectSpacer,
+		rectSpacer,
+		container.NewStack(
+			rectListBox,
+			msgbox.List,
+		),
+		rectSpacer,
+		entryDest,
+		rectSpacer,
+		btnSend,
+		rectSpacer,
+		checkLimit,
+		checkVerified,
+		checkQuarantine,
+		rectSpacer,
+		selectArchive,
+		archiveText,
+	)
+
+	gridItem1 := container.NewCenter(
+		messageForm,
+	)
+
+	gridItem2 := container.NewCenter()
+
+	gridItem3 := container.NewCenter()
+
+	gridItem4 := container.NewCenter()
+
+	gridItem1.Hidden = false
+	gridItem2.Hidden = true
+	gridItem3.Hidden = true
+	gridItem4.Hidden = true
+
+	features := container.NewCenter(
+		layout.NewSpacer(),
+		gridItem1,
+		layout.NewSpacer(),
+		gridItem2,
+		layout.NewSpacer(),
+		gridItem3,
+		layout.NewSpacer(),
+		gridItem4,
+		layout.NewSpacer(),
+	)
+
+	session.Window.Canvas().SetOnTypedKey(func(k *fyne.KeyEvent) {
+		if session.Domain != "app.messages" {
+			return
+		}
+
+		if k.Name == fyne.KeyUp {
+			session.Dashboard = "main"
+
+			session.Window.SetContent(layoutTransition())
+			session.Window.SetContent(layoutDashboard())
+			removeOverlays()
+		} else if k.Name == fyne.KeyF5 {
+			go func() {
+				updateMessageIndex()
+				fyne.Do(func() {
+					if session.Domain != "app.messages" {
+						return
+					}
+
+					session.Window.SetContent(layoutMessages())
+					removeOverlays()
+				})
+			}()
 		}
+	})
 
-		// Change globals.Config mainnet/testnet to match network
-		globals.InitNetwork()
+	linkSettings := widget.NewHyperlinkWithStyle("Message Settings", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkBroadcast := widget.NewHyperlinkWithStyle("Broadcast Message", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkFilters := widget.NewHyperlinkWithStyle("Spam Filters", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 
-		selectNodes.Refresh()
+	linkSettings.OnTapped = func() {
+		gridItem2.Objects = []fyne.CanvasObject{
+			layoutMessageSettings("", func() {
+				session.Window.SetContent(layoutTransition())
+				session.Window.SetContent(layoutMessages())
+				removeOverlays()
+			}),
+		}
+		gridItem1.Hide()
+		gridItem2.Show()
+		linkSettings.Hide()
+		linkBroadcast.Hide()
+		linkFilters.Hide()
 	}
 
-	net, _ := GetValue("settings", []byte("network"))
+	linkBroadcast.OnTapped = func() {
+		gridItem3.Objects = []fyne.CanvasObject{
+			layoutMessageBroadcast(func() {
+				session.Window.SetContent(layoutTransition())
+				session.Window.SetContent(layoutMessages())
+				removeOverlays()
+			}),
+		}
+		gridItem1.Hide()
+		gridItem3.Show()
+		linkSettings.Hide()
+		linkBroadcast.Hide()
+		linkFilters.Hide()
+	}
 
-	if string(net) == NETWORK_TESTNET {
-		radioNetwork.SetSelected(NETWORK_TESTNET)
-	} else if string(net) == NETWORK_SIMULATOR {
-		radioNetwork.SetSelected(NETWORK_SIMULATOR)
-	} else {
-		radioNetwork.SetSelected(NETWORK_MAINNET)
+	linkFilters.OnTapped = func() {
+		gridItem4.Objects = []fyne.CanvasObject{
+			layoutMessageFilters(func() {
+				session.Window.SetContent(layoutTransition())
+				session.Window.SetContent(layoutMessages())
+				removeOverlays()
+			}),
+		}
+		gridItem1.Hide()
+		gridItem4.Show()
+		linkSettings.Hide()
+		linkBroadcast.Hide()
+		linkFilters.Hide()
 	}
 
-	radioNetwork.Refresh()
+	subContainer := container.NewStack(
+		container.NewVBox(
+			container.NewStack(
+				container.NewHBox(
+					layout.NewSpacer(),
+					line1,
+					layout.NewSpacer(),
+					menuLabel,
+					layout.NewSpacer(),
+					line2,
+					layout.NewSpacer(),
+				),
+			),
+			rectSpacer,
+			rectSpacer,
+			container.NewCenter(
+				layout.NewSpacer(),
+				linkBroadcast,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			container.NewCenter(
+				layout.NewSpacer(),
+				linkFilters,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			container.NewCenter(
+				layout.NewSpacer(),
+				linkSettings,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			container.NewCenter(
+				layout.NewSpacer(),
+				linkBack,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			rectSpacer,
+			rectSpacer,
+			rectSpacer,
+		),
+	)
 
-	entryUser := widget.NewEntry()
-	entryUser.PlaceHolder = "Username"
-	entryUser.SetText(cyberdeck.RPC.user)
+	c := container.NewBorder(
+		features,
+		subContainer,
+		nil,
+		nil,
+	)
 
-	entryPass := widget.NewEntry()
-	entryPass.PlaceHolder = "Password"
-	entryPass.Password = true
-	entryPass.SetText(cyberdeck.RPC.pass)
+	layout := container.NewStack(
+		frame,
+		c,
+	)
 
-	entryUser.OnChanged = func(s string) {
-		cyberdeck.RPC.user = s
-	}
+	return NewVScroll(layout)
+}
 
-	entryPass.OnChanged = func(s string) {
-		cyberdeck.RPC.pass = s
-	}
+// Message broadcast form to send the same message to several contacts or a saved group in one transaction
+func layoutMessageBroadcast(done func()) *fyne.Container {
+	heading := canvas.NewText("B R O A D C A S T", colors.Gray)
+	heading.TextSize = 16
+	heading.Alignment = fyne.TextAlignCenter
+	heading.TextStyle = fyne.TextStyle{Bold: true}
 
-	checkGnomon := widget.NewCheck("Enable Gnomon", nil)
-	checkGnomon.OnChanged = func(b bool) {
-		if b {
-			StoreValue("settings", []byte("gnomon"), []byte("1"))
-			checkGnomon.Checked = true
-			gnomon.Active = 1
-		} else {
-			StoreValue("settings", []byte("gnomon"), []byte("0"))
-			checkGnomon.Checked = false
-			gnomon.Active = 0
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+	rectWidth := canvas.NewRectangle(color.Transparent)
+	rectWidth.SetMinSize(fyne.NewSize(ui.Width*0.8, 10))
+	rectListBox := canvas.NewRectangle(color.Transparent)
+	rectListBox.SetMinSize(fyne.NewSize(ui.Width*0.8, ui.Height*0.25))
+
+	// Label each contact the same way as the contact list, mapped back to its address
+	labels := make(map[string]string)
+	addresses := make(map[string]string)
+	var options []string
+	label := func(address, username string) string {
+		if l, ok := addresses[address]; ok {
+			return l
+		}
+
+		l := username
+		if l == "" {
+			l = "..." + address[len(address)-DEFAULT_USERADDR_SHORTEN_LENGTH:]
 		}
+		labels[l] = address
+		addresses[address] = l
+		options = append(options, l)
+
+		return l
 	}
 
-	gmn, err := GetValue("settings", []byte("gnomon"))
-	if err != nil {
-		gnomon.Active = 1
-		StoreValue("settings", []byte("gnomon"), []byte("1"))
-		checkGnomon.Checked = true
+	for _, c := range getMessages(0) {
+		split := strings.Split(c, "~~~")
+		label(split[0], split[1])
 	}
 
-	if string(gmn) == "1" {
-		checkGnomon.Checked = true
-	} else {
-		checkGnomon.Checked = false
+	groups := getMessageGroups()
+	var groupNames []string
+	for name := range groups {
+		groupNames = append(groupNames, name)
 	}
+	sort.Strings(groupNames)
 
-	labelBack := widget.NewHyperlinkWithStyle("Return to Login", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-	labelBack.OnTapped = func() {
-		network := radioNetwork.Selected
-		if network == NETWORK_TESTNET {
-			setNetwork(network)
-		} else if network == NETWORK_SIMULATOR {
-			setNetwork(network)
-		} else {
-			setNetwork(NETWORK_MAINNET)
-		}
-		setDaemon(entryAddress.Text)
+	btnSend := widget.NewButton("Send", nil)
+	btnSend.Disable()
 
-		initSettings()
+	entry := widget.NewEntry()
+	entry.MultiLine = true
+	entry.Wrapping = fyne.TextWrapWord
+	entry.PlaceHolder = "Message"
 
-		resizeWindow(ui.MaxWidth, ui.MaxHeight)
-		session.LastDomain = session.Window.Content()
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutMain())
-		removeOverlays()
+	checkContacts := widget.NewCheckGroup(nil, nil)
+
+	recipients := func() (result []string) {
+		for _, l := range checkContacts.Selected {
+			result = append(result, labels[l])
+		}
+		return
 	}
 
-	btnRestore.OnTapped = func() {
-		setNetwork(NETWORK_MAINNET)
-		setDaemon(DEFAULT_REMOTE_DAEMON)
-		setAuthMode("true")
-		setGnomon("1")
+	refresh := func() {
+		if entry.Text == "" || len(checkContacts.Selected) < 1 {
+			btnSend.Text = "Send"
+			btnSend.Disable()
+			btnSend.Refresh()
+			return
+		}
 
-		resizeWindow(ui.MaxWidth, ui.MaxHeight)
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutSettings())
-		removeOverlays()
+		cost, err := checkBroadcastPack(entry.Text, session.Username, recipients())
+		if err != nil {
+			btnSend.Text = strings.ToUpper(err.Error()[:1]) + err.Error()[1:] + "..."
+			btnSend.Disable()
+			btnSend.Refresh()
+			return
+		}
+
+		btnSend.Text = fmt.Sprintf("Send (%d recipients)  ·  %s DERO", len(checkContacts.Selected), globals.FormatMoney(cost))
+		btnSend.Enable()
+		btnSend.Refresh()
 	}
 
-	statusText := canvas.NewText("", colors.Account)
-	statusText.TextSize = 12
+	checkContacts.Options = options
+	checkContacts.OnChanged = func(s []string) { refresh() }
+	entry.OnChanged = func(s string) { refresh() }
 
-	btnDelete.OnTapped = func() {
-		err := cleanGnomonData()
-		if err != nil {
-			if parseError, ok := err.(*os.PathError); !ok {
-				err = fmt.Errorf("error clearing local %s data", session.Network)
-			} else {
-				err = parseError.Err
+	entryGroup := widget.NewEntry()
+	entryGroup.PlaceHolder = "Group Name"
+
+	selectGroup := widget.NewSelect(groupNames, func(s string) {
+		var selected []string
+		for _, address := range groups[s] {
+			selected = append(selected, label(address, ""))
+		}
+		checkContacts.Options = options
+		checkContacts.SetSelected(selected)
+		entryGroup.SetText(s)
+	})
+	selectGroup.PlaceHolder = "Select a Group"
+	if len(groupNames) < 1 {
+		selectGroup.Disable()
+	}
+
+	btnSaveGroup := widget.NewButton("Save Group", func() {
+		var members []string
+		for _, r := range recipients() {
+			if a, err := globals.ParseValidateAddress(r); err == nil {
+				members = append(members, a.BaseAddress().String())
 			}
+		}
 
-			statusText.Color = colors.Red
-			statusText.Text = err.Error()
-			statusText.Refresh()
+		if err := setMessageGroup(entryGroup.Text, members); err != nil {
+			logger.Errorf("[Message] Storing group: %s\n", err)
 			return
 		}
 
-		statusText.Color = colors.Green
-		statusText.Text = fmt.Sprintf("Gnomon %s data successfully deleted.", strings.ToLower(session.Network))
-		statusText.Refresh()
+		groups = getMessageGroups()
+		groupNames = nil
+		for name := range groups {
+			groupNames = append(groupNames, name)
+		}
+		sort.Strings(groupNames)
+		selectGroup.Options = groupNames
+		if len(groupNames) > 0 {
+			selectGroup.Enable()
+		} else {
+			selectGroup.Disable()
+		}
+		selectGroup.Refresh()
+	})
+
+	btnSend.OnTapped = func() {
+		message := entry.Text
+		list := recipients()
+
+		btnSend.Text = "Setting up transfer..."
+		btnSend.Disable()
+		btnSend.Refresh()
+
+		go func() {
+			txid, err := sendBroadcast(message, session.Username, list)
+			if err != nil {
+				logger.Errorf("[Message] Failed to send broadcast: %s\n", err)
+				fyne.Do(func() {
+					btnSend.Text = "Failed to send message..."
+					btnSend.Disable()
+					btnSend.Refresh()
+				})
+				return
+			}
+
+			fyne.Do(func() {
+				entry.SetText("")
+				btnSend.Text = "Confirming..."
+				btnSend.Disable()
+				btnSend.Refresh()
+			})
+
+			sHeight := walletapi.Get_Daemon_Height()
+			for session.Domain == "app.messages" {
+				var zeroscid crypto.Hash
+				_, result := engram.Disk.Get_Payments_TXID(zeroscid, txid.String())
+				if result.TXID == txid.String() {
+					updateMessageIndex()
+					fyne.Do(done)
+					break
+				}
+
+				// If we go DEFAULT_CONFIRMATION_TIMEOUT blocks without confirmation, display failed to transfer and break
+				if walletapi.Get_Daemon_Height() > sHeight+int64(DEFAULT_CONFIRMATION_TIMEOUT) {
+					fyne.Do(func() {
+						btnSend.Text = "Failed to send message..."
+						btnSend.Disable()
+						btnSend.Refresh()
+					})
+					break
+				}
+
+				time.Sleep(time.Second * 1)
+			}
+		}()
 	}
 
-	formSettings := container.NewVBox(
-		labelNetwork,
-		rectSpacer,
-		radioNetwork,
-		widget.NewLabel(""),
-		labelNode,
-		rectSpacer,
-		rectSpacer,
-		selectNodes,
-		rectSpacer,
-		entryAddress,
-		rectSpacer,
+	btnCancel := widget.NewButton("Cancel", func() {
+		done()
+	})
+
+	return container.NewVBox(
 		rectSpacer,
-		labelScan,
 		rectSpacer,
-		entryScan,
-		widget.NewLabel(""),
-		labelSecurity,
+		heading,
 		rectSpacer,
-		textCyberdeck,
 		rectSpacer,
-		entryUser,
+		rectWidth,
+		selectGroup,
 		rectSpacer,
-		entryPass,
+		container.NewStack(
+			rectListBox,
+			container.NewVScroll(checkContacts),
+		),
 		rectSpacer,
-		widget.NewLabel(""),
-		labelGnomon,
+		container.NewBorder(nil, nil, nil, btnSaveGroup, entryGroup),
 		rectSpacer,
-		textGnomon,
 		rectSpacer,
-		checkGnomon,
+		entry,
 		rectSpacer,
-		statusText,
+		btnSend,
 		rectSpacer,
+		btnCancel,
 		rectSpacer,
-		btnDelete,
 		rectSpacer,
-		btnRestore,
-	)
-
-	scrollBox := container.NewVScroll(
-		container.NewHBox(
-			layout.NewSpacer(),
-			container.NewStack(
-				rectScroll,
-				formSettings,
-			),
-			layout.NewSpacer(),
-		),
-	)
-
-	scrollBox.SetMinSize(fyne.NewSize(ui.MaxWidth, ui.Height*0.68))
-
-	gridItem1 := container.NewCenter(
-		container.NewVBox(
-			widget.NewLabel(""),
-			heading,
-			widget.NewLabel(""),
-			scrollBox,
-			rectSpacer,
-			rectSpacer,
-		),
-	)
-
-	features := container.NewCenter(
-		layout.NewSpacer(),
-		gridItem1,
-		layout.NewSpacer(),
-	)
-
-	footer := container.NewVBox(
-		container.NewHBox(
-			layout.NewSpacer(),
-			labelBack,
-			layout.NewSpacer(),
-		),
-		widget.NewLabel(" "),
-	)
-
-	c := container.NewBorder(
-		features,
-		footer,
-		nil,
-		nil,
 	)
+}
 
-	layout := container.NewStack(
-		frame,
-		c,
-	)
+// Show a file dialog to export the messages for a contact address, or all contacts when address is empty
+func showMessageExport(address string, markdown bool, status *canvas.Text) {
+	dialogFileSave := dialog.NewFileSave(func(uri fyne.URIWriteCloser, err error) {
+		if err != nil {
+			logger.Errorf("[Engram] File dialog: %s\n", err)
+			status.Text = "could not export messages"
+			status.Color = colors.Red
+			status.Refresh()
+			return
+		}
 
-	return NewVScroll(layout)
-}
+		if uri == nil {
+			return // Canceled
+		}
 
-func layoutMessages() fyne.CanvasObject {
-	session.Domain = "app.messages"
+		var data []byte
+		if markdown {
+			data, err = exportMessageMarkdown(address)
+		} else {
+			data, err = exportMessageArchive(address)
+		}
 
-	if !walletapi.Connected {
-		session.Window.SetContent(layoutSettings())
-	}
+		if err == nil {
+			_, err = writeToURI(data, uri)
+		}
 
-	title := canvas.NewText("M Y    C O N T A C T S", colors.Gray)
-	title.TextStyle = fyne.TextStyle{Bold: true}
-	title.TextSize = 16
+		if err != nil {
+			logger.Errorf("[Message] Exporting messages: %s\n", err)
+			status.Text = "error exporting messages"
+			status.Color = colors.Red
+			status.Refresh()
+			return
+		}
 
-	menuLabel := canvas.NewText("  M O R E   O P T I O N S  ", colors.Gray)
-	menuLabel.TextSize = 11
-	menuLabel.Alignment = fyne.TextAlignCenter
-	menuLabel.TextStyle = fyne.TextStyle{Bold: true}
+		status.Text = "exported messages successfully"
+		status.Color = colors.Green
+		status.Refresh()
+	}, session.Window)
 
-	checkLimit := widget.NewCheck(" Show only recent messages", nil)
-	checkLimit.OnChanged = func(b bool) {
-		if b {
-			session.LimitMessages = true
-			session.Window.SetContent(layoutTransition())
-			session.Window.SetContent(layoutMessages())
-			removeOverlays()
+	if !a.Driver().Device().IsMobile() {
+		// Open file browser in current directory
+		uri, err := storage.ListerForURI(storage.NewFileURI(AppPath()))
+		if err == nil {
+			dialogFileSave.SetLocation(uri)
 		} else {
-			session.LimitMessages = false
-			session.Window.SetContent(layoutTransition())
-			session.Window.SetContent(layoutMessages())
-			removeOverlays()
+			logger.Errorf("[Engram] Could not open current directory %s\n", err)
 		}
 	}
 
-	if session.LimitMessages {
-		checkLimit.Checked = true
+	name := "messages"
+	if address != "" {
+		name += "-" + address[len(address)-DEFAULT_USERADDR_SHORTEN_LENGTH:]
 	}
+	name += "-" + time.Now().Format("2006-01-02")
 
-	sep := canvas.NewRectangle(colors.Gray)
-	sep.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
-
-	line1 := container.NewVBox(
-		layout.NewSpacer(),
-		sep,
-		layout.NewSpacer(),
-	)
-
-	sep2 := canvas.NewRectangle(colors.Gray)
-	sep2.SetMinSize(fyne.NewSize(ui.Width*0.2, 2))
-
-	line2 := container.NewVBox(
-		layout.NewSpacer(),
-		sep2,
-		layout.NewSpacer(),
-	)
-
-	linkBack := widget.NewHyperlinkWithStyle("Back to Dashboard", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
-	linkBack.OnTapped = func() {
-		session.LastDomain = session.Window.Content()
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutDashboard())
-		removeOverlays()
+	if markdown {
+		name += ".md"
+	} else {
+		name += ".json.enc"
 	}
 
-	rectStatus := canvas.NewRectangle(color.Transparent)
-	rectStatus.SetMinSize(fyne.NewSize(10, 10))
-	rectEmpty := canvas.NewRectangle(color.Transparent)
-	rectEmpty.SetMinSize(fyne.NewSize(10, 10))
-	rect := canvas.NewRectangle(color.Transparent)
-	rect.SetMinSize(fyne.NewSize(ui.Width, 20))
-	frame := &iframe{}
-	rect.SetMinSize(fyne.NewSize(ui.Width, 30))
-	rectSpacer := canvas.NewRectangle(color.Transparent)
-	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
-	rect.SetMinSize(fyne.NewSize(10, 10))
-	rectList := canvas.NewRectangle(color.Transparent)
-	rectList.SetMinSize(fyne.NewSize(ui.Width, 35))
-	rectListBox := canvas.NewRectangle(color.Transparent)
-	rectListBox.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.43))
-
-	messages.Data = nil
+	dialogFileSave.SetView(dialog.ListView)
+	dialogFileSave.SetFileName(name)
+	dialogFileSave.Resize(fyne.NewSize(ui.Width, ui.Height))
+	dialogFileSave.Show()
+}
 
-	var height uint64
+// Show a file dialog to import an encrypted message archive, done is called after a successful import
+func showMessageImport(status *canvas.Text, done func()) {
+	dialogFileImport := dialog.NewFileOpen(func(uri fyne.URIReadCloser, err error) {
+		if err != nil {
+			logger.Errorf("[Engram] File dialog: %s\n", err)
+			status.Text = "could not import file"
+			status.Color = colors.Red
+			status.Refresh()
+			return
+		}
 
-	if session.LimitMessages {
-		height = engram.Disk.Get_Height() - 1000000
-	} else {
-		height = 0
-	}
+		if uri == nil {
+			return // Canceled
+		}
 
-	data := getMessages(height)
-	temp := data
+		filedata, err := readFromURI(uri)
+		if err != nil {
+			logger.Errorf("[Message] Cannot read archive file data: %s\n", err)
+			status.Text = "cannot read file data"
+			status.Color = colors.Red
+			status.Refresh()
+			return
+		}
 
-	list := binding.BindStringList(&data)
+		status.Text = "verifying archive..."
+		status.Color = colors.Gray
+		status.Refresh()
 
-	msgbox.List = widget.NewListWithData(list,
-		func() fyne.CanvasObject {
-			c := container.NewVBox(
-				widget.NewLabel(""),
-			)
-			return c
-		},
-		func(di binding.DataItem, co fyne.CanvasObject) {
-			dat := di.(binding.String)
-			str, err := dat.Get()
+		// Senders are verified with the daemon while importing
+		go func() {
+			count, err := importMessageArchive(filedata)
 			if err != nil {
+				logger.Errorf("[Message] Importing archive: %s\n", err)
+				fyne.Do(func() {
+					status.Text = err.Error()
+					status.Color = colors.Red
+					status.Refresh()
+				})
 				return
 			}
-			dataItem := strings.Split(str, "~~~")
-			short := dataItem[0]
-			address := short[len(short)-DEFAULT_USERADDR_SHORTEN_LENGTH:]
-			username := dataItem[1]
-			// If a username is longer than what *would* be a 'short' address of ...xyzxyzxyzx (e.g. 13), then shorten as well to be similar sizing
-			if len(username) > DEFAULT_USERADDR_SHORTEN_LENGTH+3 {
-				username = "..." + username[len(username)-DEFAULT_USERADDR_SHORTEN_LENGTH:]
-			}
 
-			if username == "" {
-				co.(*fyne.Container).Objects[0].(*widget.Label).SetText("..." + address)
-			} else {
-				co.(*fyne.Container).Objects[0].(*widget.Label).SetText(username)
-			}
-			co.(*fyne.Container).Objects[0].(*widget.Label).Wrapping = fyne.TextWrapWord
-			co.(*fyne.Container).Objects[0].(*widget.Label).TextStyle.Bold = false
-			co.(*fyne.Container).Objects[0].(*widget.Label).Alignment = fyne.TextAlignLeading
-		})
+			logger.Printf("[Message] Imported %d conversations from archive\n", count)
 
-	msgbox.List.OnSelected = func(id widget.ListItemID) {
-		msgbox.List.UnselectAll()
-		split := strings.Split(data[id], "~~~")
-		if split[1] == "" {
-			messages.Contact = split[0]
+			fyne.Do(done)
+		}()
+	}, session.Window)
+
+	if !a.Driver().Device().IsMobile() {
+		// Open file browser in current directory
+		uri, err := storage.ListerForURI(storage.NewFileURI(AppPath()))
+		if err == nil {
+			dialogFileImport.SetLocation(uri)
 		} else {
-			messages.Contact = split[1]
+			logger.Errorf("[Engram] Could not open current directory %s\n", err)
 		}
-
-		session.LastDomain = session.Window.Content()
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutPM())
-		removeOverlays()
 	}
 
-	searchList := []string{}
+	dialogFileImport.SetView(dialog.ListView)
+	dialogFileImport.Resize(fyne.NewSize(ui.Width, ui.Height))
+	dialogFileImport.Show()
+}
 
-	entrySearch := widget.NewEntry()
-	entrySearch.PlaceHolder = "Search for a Contact"
-	entrySearch.OnChanged = func(s string) {
-		s = strings.ToLower(s)
-		searchList = []string{}
-		if s == "" {
-			data = temp
-			list.Reload()
-		} else {
-			for _, d := range temp {
-				tempd := strings.ToLower(d)
-				split := strings.Split(tempd, "~~~")
+// Message spam filters form with the quarantine and minimum amount rules, blocked senders can be unblocked
+func layoutMessageFilters(done func()) *fyne.Container {
+	heading := canvas.NewText("S P A M    F I L T E R S", colors.Gray)
+	heading.TextSize = 16
+	heading.Alignment = fyne.TextAlignCenter
+	heading.TextStyle = fyne.TextStyle{Bold: true}
 
-				if split[1] == "" {
-					if strings.Contains(split[0], s) {
-						searchList = append(searchList, d)
-					}
-				} else {
-					if strings.Contains(split[1], s) {
-						searchList = append(searchList, d)
-					}
-				}
-			}
+	blockedLabel := canvas.NewText("Blocked Senders", colors.Green)
+	blockedLabel.TextSize = 14
+	blockedLabel.Alignment = fyne.TextAlignCenter
 
-			data = searchList
-			list.Reload()
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+	rectWidth := canvas.NewRectangle(color.Transparent)
+	rectWidth.SetMinSize(fyne.NewSize(ui.Width*0.8, 10))
+	rectListBox := canvas.NewRectangle(color.Transparent)
+	rectListBox.SetMinSize(fyne.NewSize(ui.Width*0.8, ui.Height*0.2))
+
+	filters := getMessageFilters()
+
+	btnSave := widget.NewButton("Save", nil)
+
+	checkQuarantine := widget.NewCheck(" Quarantine senders I have not written to", nil)
+	checkQuarantine.Checked = filters.QuarantineUnknown
+
+	wAmount := widget.NewEntry()
+	wAmount.SetPlaceHolder("Minimum Amount (DERO)")
+	wAmount.SetText(globals.FormatMoney(filters.MinAmount))
+	wAmount.Validator = func(s string) (err error) {
+		if _, err = globals.ParseAmount(s); err != nil {
+			btnSave.Disable()
+			return errors.New("invalid amount")
 		}
+
+		btnSave.Enable()
+		return
 	}
 
-	btnSend := widget.NewButton("New Message", func() {
-		_, err := globals.ParseValidateAddress(messages.Contact)
+	// Blocked senders stay checked, unchecking a sender unblocks it when saved
+	checkBlocked := widget.NewCheckGroup(filters.Blocked, nil)
+	checkBlocked.SetSelected(filters.Blocked)
+
+	btnSave.OnTapped = func() {
+		amount, err := globals.ParseAmount(wAmount.Text)
 		if err != nil {
-			//_, err := engram.Disk.NameToAddress(messages.Contact)
-			_, err := checkUsername(messages.Contact, -1)
-			if err != nil {
-				return
-			}
+			return
 		}
 
-		session.LastDomain = session.Window.Content()
-		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutPM())
-		removeOverlays()
-	})
-	btnSend.Disable()
+		filters.MinAmount = amount
+		filters.QuarantineUnknown = checkQuarantine.Checked
+		filters.Blocked = checkBlocked.Selected
 
-	entryDest := widget.NewEntry()
-	entryDest.MultiLine = false
-	entryDest.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
-	entryDest.PlaceHolder = "Username or Address"
-	entryDest.Validator = func(s string) error {
-		if len(s) > 0 {
-			_, err := globals.ParseValidateAddress(s)
-			if err != nil {
-				btnSend.Disable()
-				//_, err := engram.Disk.NameToAddress(s)
-				_, err = checkUsername(s, -1)
-				if err != nil {
-					btnSend.Disable()
-					return errors.New("invalid username or address")
-				} else {
-					messages.Contact = s
-					btnSend.Enable()
-					return nil
-				}
-			} else {
-				btnSend.Enable()
-				messages.Contact = s
-				return nil
-			}
+		if err = setMessageFilters(filters); err != nil {
+			logger.Errorf("[Message] Storing filters: %s\n", err)
+			btnSave.Text = "Failed to save filters..."
+			btnSave.Disable()
+			btnSave.Refresh()
+			return
 		}
 
-		return errors.New("invalid username or address")
+		done()
 	}
 
-	messageForm := container.NewVBox(
+	btnCancel := widget.NewButton("Cancel", func() {
+		done()
+	})
+
+	return container.NewVBox(
 		rectSpacer,
 		rectSpacer,
-		container.NewHBox(
-			layout.NewSpacer(),
-			title,
-			layout.NewSpacer(),
-		),
+		heading,
 		rectSpacer,
 		rectSpacer,
-		entrySearch,
+		rectWidth,
+		checkQuarantine,
+		rectSpacer,
+		wAmount,
+		rectSpacer,
 		rectSpacer,
+		blockedLabel,
 		rectSpacer,
 		container.NewStack(
 			rectListBox,
-			msgbox.List,
+			container.NewVScroll(checkBlocked),
 		),
 		rectSpacer,
-		entryDest,
 		rectSpacer,
-		btnSend,
+		btnSave,
+		rectSpacer,
+		btnCancel,
+		rectSpacer,
 		rectSpacer,
-		checkLimit,
 	)
+}
 
-	gridItem1 := container.NewCenter(
-		messageForm,
-	)
+// Message settings form for a conversation address, or the global message settings when address is empty
+func layoutMessageSettings(address string, done func()) *fyne.Container {
+	heading := canvas.NewText("M E S S A G E    S E T T I N G S", colors.Gray)
+	heading.TextSize = 16
+	heading.Alignment = fyne.TextAlignCenter
+	heading.TextStyle = fyne.TextStyle{Bold: true}
 
-	gridItem2 := container.NewCenter()
+	scope := canvas.NewText("All Conversations", colors.Green)
+	scope.TextSize = 14
+	scope.Alignment = fyne.TextAlignCenter
+	if address != "" {
+		scope.Text = "This Conversation"
+	}
 
-	gridItem3 := container.NewCenter()
+	cost := canvas.NewText("", colors.Green)
+	cost.TextSize = 14
+	cost.Alignment = fyne.TextAlignCenter
 
-	gridItem4 := container.NewCenter()
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+	rectWidth := canvas.NewRectangle(color.Transparent)
+	rectWidth.SetMinSize(fyne.NewSize(ui.Width*0.8, 10))
 
-	gridItem1.Hidden = false
-	gridItem2.Hidden = true
-	gridItem3.Hidden = true
-	gridItem4.Hidden = true
+	ms := getMessageSettings(address)
 
-	features := container.NewCenter(
-		layout.NewSpacer(),
-		gridItem1,
-		layout.NewSpacer(),
-		gridItem2,
-		layout.NewSpacer(),
-		gridItem3,
-		layout.NewSpacer(),
-		gridItem4,
-		layout.NewSpacer(),
-	)
+	btnSave := widget.NewButton("Save", nil)
 
-	session.Window.Canvas().SetOnTypedKey(func(k *fyne.KeyEvent) {
-		if session.Domain != "app.messages" {
+	wAmount := widget.NewEntry()
+	wAmount.SetPlaceHolder("Amount (DERO)")
+	wAmount.SetText(globals.FormatMoney(ms.Amount))
+
+	options := []string{"Anonymity Set:   2  (None)", "Anonymity Set:   4  (Low)", "Anonymity Set:   8  (Low)", "Anonymity Set:   16  (Recommended)", "Anonymity Set:   32  (Medium)", "Anonymity Set:   64  (High)", "Anonymity Set:   128  (High)"}
+	wRings := widget.NewSelect(options, nil)
+	for i := range options {
+		if uint64(2<<i) == ms.Ringsize {
+			wRings.SetSelectedIndex(i)
+		}
+	}
+
+	wExpiry := widget.NewEntry()
+	wExpiry.SetPlaceHolder("Expiry (minutes, 0 for none)")
+	wExpiry.SetText(strconv.FormatUint(ms.Expiry/60, 10))
+
+	wPort := widget.NewEntry()
+	wPort.SetPlaceHolder("Destination Port")
+	wPort.SetText(strconv.FormatUint(ms.Port, 10))
+
+	// Read the settings from the form and preview the cost of a single message
+	parse := func() (result MessageSettings, err error) {
+		if result.Amount, err = globals.ParseAmount(wAmount.Text); err != nil {
+			err = errors.New("invalid amount")
 			return
 		}
 
-		if k.Name == fyne.KeyUp {
-			session.Dashboard = "main"
+		if wRings.SelectedIndex() < 0 {
+			err = errors.New("invalid ring size")
+			return
+		}
+		result.Ringsize = uint64(2 << wRings.SelectedIndex())
 
-			session.Window.SetContent(layoutTransition())
-			session.Window.SetContent(layoutDashboard())
-			removeOverlays()
-		} else if k.Name == fyne.KeyF5 {
-			session.Window.SetContent(layoutMessages())
-			removeOverlays()
+		var minutes uint64
+		if minutes, err = strconv.ParseUint(wExpiry.Text, 10, 64); err != nil {
+			err = errors.New("invalid expiry")
+			return
 		}
-	})
+		result.Expiry = minutes * 60
 
-	subContainer := container.NewStack(
-		container.NewVBox(
-			container.NewStack(
-				container.NewHBox(
-					layout.NewSpacer(),
-					line1,
-					layout.NewSpacer(),
-					menuLabel,
-					layout.NewSpacer(),
-					line2,
-					layout.NewSpacer(),
-				),
-			),
-			rectSpacer,
-			rectSpacer,
-			container.NewCenter(
-				layout.NewSpacer(),
-				linkBack,
-				layout.NewSpacer(),
-			),
-			rectSpacer,
-			rectSpacer,
-			rectSpacer,
-			rectSpacer,
-		),
-	)
+		if result.Port, err = strconv.ParseUint(wPort.Text, 10, 64); err != nil {
+			err = errors.New("invalid port")
+			return
+		}
 
-	c := container.NewBorder(
-		features,
-		subContainer,
-		nil,
-		nil,
-	)
+		err = result.validate()
 
-	layout := container.NewStack(
-		frame,
-		c,
+		return
+	}
+
+	refresh := func() {
+		result, err := parse()
+		if err != nil {
+			cost.Text = "Error: " + err.Error()
+			cost.Color = colors.Red
+			cost.Refresh()
+			btnSave.Disable()
+			return
+		}
+
+		cost.Text = "Cost per message:  " + globals.FormatMoney(result.Amount+messageFees(result.Ringsize)) + " DERO"
+		cost.Color = colors.Green
+		cost.Refresh()
+		btnSave.Text = "Save"
+		btnSave.Enable()
+		btnSave.Refresh()
+	}
+
+	wAmount.OnChanged = func(s string) { refresh() }
+	wRings.OnChanged = func(s string) { refresh() }
+	wExpiry.OnChanged = func(s string) { refresh() }
+	wPort.OnChanged = func(s string) { refresh() }
+
+	fields := container.NewVBox(
+		wAmount,
+		rectSpacer,
+		wRings,
+		rectSpacer,
+		wExpiry,
+		rectSpacer,
+		wPort,
 	)
 
-	return NewVScroll(layout)
+	checkGlobal := widget.NewCheck(" Use settings for all conversations", func(b bool) {
+		if b {
+			defaults := getGlobalMessageSettings()
+			wAmount.SetText(globals.FormatMoney(defaults.Amount))
+			for i := range options {
+				if uint64(2<<i) == defaults.Ringsize {
+					wRings.SetSelectedIndex(i)
+				}
+			}
+			wExpiry.SetText(strconv.FormatUint(defaults.Expiry/60, 10))
+			wPort.SetText(strconv.FormatUint(defaults.Port, 10))
+			fields.Hide()
+		} else {
+			fields.Show()
+		}
+	})
+	checkGlobal.Hidden = address == ""
+	if address != "" && !hasMessageSettings(address) {
+		checkGlobal.SetChecked(true)
+	}
+
+	btnSave.OnTapped = func() {
+		var err error
+		if checkGlobal.Checked && address != "" {
+			err = setMessageSettings(address, nil)
+		} else {
+			var result MessageSettings
+			result, err = parse()
+			if err == nil {
+				err = setMessageSettings(address, &result)
+			}
+		}
+
+		if err != nil {
+			logger.Errorf("[Message] Storing settings: %s\n", err)
+			btnSave.Text = "Failed to save settings..."
+			btnSave.Disable()
+			btnSave.Refresh()
+			return
+		}
+
+		done()
+	}
+
+	btnCancel := widget.NewButton("Cancel", func() {
+		done()
+	})
+
+	refresh()
+
+	return container.NewVBox(
+		rectSpacer,
+		rectSpacer,
+		heading,
+		rectSpacer,
+		scope,
+		rectSpacer,
+		rectSpacer,
+		rectWidth,
+		checkGlobal,
+		rectSpacer,
+		fields,
+		rectSpacer,
+		rectSpacer,
+		cost,
+		rectSpacer,
+		rectSpacer,
+		btnSave,
+		rectSpacer,
+		btnCancel,
+		rectSpacer,
+		rectSpacer,
+	)
 }
 
 func layoutPM() fyne.CanvasObject {
@@ -6620,6 +9010,7 @@ func layoutPM() fyne.CanvasObject {
 	}
 
 	getPrimaryUsername()
+	getHideUnverified()
 
 	contactAddress := ""
 
@@ -6651,6 +9042,16 @@ func layoutPM() fyne.CanvasObject {
 	heading.Alignment = fyne.TextAlignCenter
 	heading.TextStyle = fyne.TextStyle{Bold: true}
 
+	// Show the registered username of an address contact when one is known
+	var showUsername func()
+	showUsername = func() {
+		if username := addressUsername(messages.Contact, showUsername); username != "" {
+			heading.Text = username
+			heading.Refresh()
+		}
+	}
+	showUsername()
+
 	lastActive := canvas.NewText("", colors.Gray)
 	lastActive.TextSize = 12
 	lastActive.Alignment = fyne.TextAlignCenter
@@ -6735,62 +9136,39 @@ func layoutPM() fyne.CanvasObject {
 	data := getMessagesFromUser(messages.Contact, height)
 
 	for d := range data {
-		if data[d].Incoming {
-			if data[d].Payload_RPC.Has(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
-				if data[d].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string) == "" {
-
+		t := data[d].Time
+		time := string(t.Format(time.RFC822))
+		comment := data[d].Comment
+		links := getTextURL(comment)
+
+		for i := range links {
+			if comment == links[i] {
+				if len(links[i]) > 25 {
+					comment = `[ ` + links[i][0:25] + "..." + ` ](` + links[i] + `)`
 				} else {
-					t := data[d].Time
-					time := string(t.Format(time.RFC822))
-					comment := data[d].Payload_RPC.Value(rpc.RPC_COMMENT, rpc.DataString).(string)
-					links := getTextURL(comment)
-
-					for i := range links {
-						if comment == links[i] {
-							if len(links[i]) > 25 {
-								comment = `[ ` + links[i][0:25] + "..." + ` ](` + links[i] + `)`
-							} else {
-								comment = `[ ` + links[i] + ` ](` + links[i] + `)`
-							}
-						} else {
-							linkText := ""
-							split := strings.Split(comment, links[i])
-							if len(links[i]) > 25 {
-								linkText = links[i][0:25] + "..."
-							} else {
-								linkText = links[i]
-							}
-							comment = `` + split[0] + `[link]` + split[1] + "\n\n›" + `[ ` + linkText + ` ](` + links[i] + `)`
-						}
-					}
-					messages.Data = append(messages.Data, data[d].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string)+";;;;"+comment+";;;;"+time)
+					comment = `[ ` + links[i] + ` ](` + links[i] + `)`
 				}
-			}
-		} else {
-			t := data[d].Time
-			time := string(t.Format(time.RFC822))
-			comment := data[d].Payload_RPC.Value(rpc.RPC_COMMENT, rpc.DataString).(string)
-			links := getTextURL(comment)
-
-			for i := range links {
-				if comment == links[i] {
-					if len(links[i]) > 25 {
-						comment = `[ ` + links[i][0:25] + "..." + ` ](` + links[i] + `)`
-					} else {
-						comment = `[ ` + links[i] + ` ](` + links[i] + `)`
-					}
+			} else {
+				linkText := ""
+				split := strings.Split(comment, links[i])
+				if len(links[i]) > 25 {
+					linkText = links[i][0:25] + "..."
 				} else {
-					linkText := ""
-					split := strings.Split(comment, links[i])
-					if len(links[i]) > 25 {
-						linkText = links[i][0:25] + "..."
-					} else {
-						linkText = links[i]
-					}
-					comment = `` + split[0] + `[link]` + split[1] + "\n\n›" + `[ ` + linkText + ` ](` + links[i] + `)`
+					linkText = links[i]
 				}
+				comment = `` + split[0] + `[link]` + split[1] + "\n\n›" + `[ ` + linkText + ` ](` + links[i] + `)`
 			}
-			messages.Data = append(messages.Data, engram.Disk.GetAddress().String()+";;;;"+comment+";;;;"+time)
+		}
+
+		incomplete := ""
+		if data[d].Parts > 1 && data[d].Received < data[d].Parts {
+			incomplete = fmt.Sprintf("%d/%d", data[d].Received, data[d].Parts)
+		}
+
+		if data[d].Incoming {
+			messages.Data = append(messages.Data, data[d].Sender+";;;;"+comment+";;;;"+time+";;;;"+strconv.FormatBool(data[d].Verified)+";;;;"+incomplete)
+		} else {
+			messages.Data = append(messages.Data, engram.Disk.GetAddress().String()+";;;;"+comment+";;;;"+time+";;;;true;;;;"+incomplete)
 		}
 	}
 
@@ -6809,13 +9187,7 @@ func layoutPM() fyne.CanvasObject {
 			rect5 := canvas.NewRectangle(color.Transparent)
 			rect5.SetMinSize(fyne.NewSize(5, 5))
 
-			//uname, err := engram.Disk.NameToAddress(split[0])
-			uname, err := checkUsername(split[0], -1)
-			if err != nil {
-				sender = split[0]
-			} else {
-				sender = uname
-			}
+			sender = split[0]
 
 			if sender == engram.Disk.GetAddress().String() {
 				rect.FillColor = colors.DarkGreen
@@ -6843,6 +9215,12 @@ func layoutPM() fyne.CanvasObject {
 				rect.FillColor = colors.Flint
 				mdata.ParseMarkdown(split[1])
 				datetime.Text = split[2]
+				if split[3] == "true" {
+					datetime.Text += "  ·  Verified"
+				} else {
+					datetime.Text += "  ·  Unverified Sender"
+					datetime.Color = colors.Red
+				}
 				e = container.NewBorder(
 					nil,
 					container.NewVBox(
@@ -6863,6 +9241,11 @@ func layoutPM() fyne.CanvasObject {
 				)
 			}
 
+			if split[4] != "" {
+				datetime.Text += "  ·  Incomplete (" + split[4] + " parts)"
+				datetime.Color = colors.Yellow
+			}
+
 			lastActive.Text = "Last Updated:  " + time.Now().Format(time.RFC822)
 			lastActive.Refresh()
 
@@ -6873,12 +9256,34 @@ func layoutPM() fyne.CanvasObject {
 		}
 	}
 
+	archiveText := canvas.NewText("", colors.Green)
+	archiveText.TextSize = 12
+	archiveText.Alignment = fyne.TextAlignCenter
+
+	selectArchive := widget.NewSelect([]string{"Export (Encrypted)", "Export (Markdown)"}, nil)
+	selectArchive.PlaceHolder = "Export Conversation ..."
+	selectArchive.OnChanged = func(s string) {
+		if s == "" {
+			return
+		}
+
+		selectArchive.ClearSelected()
+
+		address, _, err := resolveMessageContact(messages.Contact, -1)
+		if err != nil {
+			logger.Errorf("[Message] Resolving contact: %s\n", err)
+			return
+		}
+
+		showMessageExport(address, s == "Export (Markdown)", archiveText)
+	}
+
 	btnSend := widget.NewButton("Send", nil)
 	btnSend.Disable()
 
 	entry := widget.NewEntry()
-	entry.MultiLine = false
-	entry.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
+	entry.MultiLine = true
+	entry.Wrapping = fyne.TextWrapWord
 	entry.PlaceHolder = "Message"
 	entry.OnChanged = func(s string) {
 		messages.Message = s
@@ -6898,7 +9303,7 @@ func layoutPM() fyne.CanvasObject {
 			return
 		}
 
-		err = checkMessagePack(messages.Message, session.Username, contact)
+		parts, cost, err := checkMessagePack(messages.Message, session.Username, contact)
 		if err != nil {
 			btnSend.Text = "Message too long..."
 			btnSend.Disable()
@@ -6909,8 +9314,12 @@ func layoutPM() fyne.CanvasObject {
 				btnSend.Text = "Send"
 				btnSend.Disable()
 				btnSend.Refresh()
+			} else if parts > 1 {
+				btnSend.Text = fmt.Sprintf("Send (%d parts)  ·  %s DERO", parts, globals.FormatMoney(cost))
+				btnSend.Enable()
+				btnSend.Refresh()
 			} else {
-				btnSend.Text = "Send"
+				btnSend.Text = "Send  ·  " + globals.FormatMoney(cost) + " DERO"
 				btnSend.Enable()
 				btnSend.Refresh()
 			}
@@ -6942,24 +9351,42 @@ func layoutPM() fyne.CanvasObject {
 		btnSend.Disable()
 		btnSend.Refresh()
 
-		txid, err := sendMessage(messages.Message, session.Username, contact)
-		if err != nil {
-			logger.Errorf("[Message] Failed to send: %s\n", err)
-			btnSend.Text = "Failed to send message..."
-			btnSend.Disable()
-			btnSend.Refresh()
-			return
-		}
-
-		logger.Printf("[Message] Dispatched transaction successfully to: %s\n", messages.Contact)
-		btnSend.Text = "Confirming..."
-		btnSend.Disable()
-		btnSend.Refresh()
+		message := messages.Message
 		messages.Message = ""
 		entry.Text = ""
 		entry.Refresh()
 
 		go func() {
+			txids, err := sendMessage(message, session.Username, contact, func(part, parts int) {
+				if parts > 1 {
+					fyne.Do(func() {
+						btnSend.Text = fmt.Sprintf("Sending part %d/%d...", part, parts)
+						btnSend.Refresh()
+					})
+				}
+			})
+			if err != nil || len(txids) < 1 {
+				logger.Errorf("[Message] Failed to send: %s\n", err)
+				fyne.Do(func() {
+					btnSend.Text = "Failed to send message..."
+					btnSend.Disable()
+					btnSend.Refresh()
+					if len(txids) < 1 {
+						entry.SetText(message)
+					}
+				})
+				return
+			}
+
+			txid := txids[len(txids)-1]
+
+			logger.Printf("[Message] Dispatched transaction successfully to: %s\n", messages.Contact)
+			fyne.Do(func() {
+				btnSend.Text = "Confirming..."
+				btnSend.Disable()
+				btnSend.Refresh()
+			})
+
 			walletapi.WaitNewHeightBlock()
 			sHeight := walletapi.Get_Daemon_Height()
 			var success bool
@@ -6975,22 +9402,32 @@ func layoutPM() fyne.CanvasObject {
 
 				// If we go DEFAULT_CONFIRMATION_TIMEOUT blocks without exiting 'Confirming...' loop, display failed to transfer and break
 				if walletapi.Get_Daemon_Height() > sHeight+int64(DEFAULT_CONFIRMATION_TIMEOUT) {
-					btnSend.Text = "Failed to send message..."
-					btnSend.Disable()
-					btnSend.Refresh()
+					fyne.Do(func() {
+						btnSend.Text = "Failed to send message..."
+						btnSend.Disable()
+						btnSend.Refresh()
+					})
 					break
 				}
 
 				// If daemon height has incremented, print retry counters into button space
-				if walletapi.Get_Daemon_Height()-sHeight > 0 {
-					btnSend.Text = fmt.Sprintf("Confirming... (%d/%d)", walletapi.Get_Daemon_Height()-sHeight, DEFAULT_CONFIRMATION_TIMEOUT)
-					btnSend.Refresh()
+				if confirmations := walletapi.Get_Daemon_Height() - sHeight; confirmations > 0 {
+					fyne.Do(func() {
+						btnSend.Text = fmt.Sprintf("Confirming... (%d/%d)", confirmations, DEFAULT_CONFIRMATION_TIMEOUT)
+						btnSend.Refresh()
+					})
 				}
 
 				// If success, reload page w/ latest content. Otherwise retain the Failure message for UX relay
 				if success {
-					session.Window.SetContent(layoutTransition())
-					session.Window.SetContent(layoutPM())
+					updateMessageIndex()
+					fyne.Do(func() {
+						if session.Domain != "app.messages.contact" {
+							return
+						}
+						session.Window.SetContent(layoutTransition())
+						session.Window.SetContent(layoutPM())
+					})
 					break
 				} else {
 					time.Sleep(time.Second * 1)
@@ -7021,6 +9458,8 @@ func layoutPM() fyne.CanvasObject {
 		rectSpacer,
 		btnSend,
 		rectSpacer,
+		selectArchive,
+		archiveText,
 		rectSpacer,
 	)
 
@@ -7073,6 +9512,47 @@ func layoutPM() fyne.CanvasObject {
 		}
 	})
 
+	linkSettings := widget.NewHyperlinkWithStyle("Conversation Settings", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	linkBlock := widget.NewHyperlinkWithStyle("Block Sender", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkBlock.OnTapped = func() {
+		address, _, err := resolveMessageContact(messages.Contact, -1)
+		if err != nil {
+			logger.Errorf("[Message] Resolving contact: %s\n", err)
+			return
+		}
+
+		if err = setMessageBlocked(address, true); err != nil {
+			logger.Errorf("[Message] Blocking sender: %s\n", err)
+			return
+		}
+
+		session.Dashboard = "app.messages"
+		messages.Contact = ""
+		session.Window.SetContent(layoutTransition())
+		session.Window.SetContent(layoutMessages())
+		removeOverlays()
+	}
+	linkSettings.OnTapped = func() {
+		address, _, err := resolveMessageContact(messages.Contact, -1)
+		if err != nil {
+			logger.Errorf("[Message] Resolving contact: %s\n", err)
+			return
+		}
+
+		gridItem2.Objects = []fyne.CanvasObject{
+			layoutMessageSettings(address, func() {
+				session.Window.SetContent(layoutTransition())
+				session.Window.SetContent(layoutPM())
+				removeOverlays()
+			}),
+		}
+		gridItem1.Hide()
+		gridItem2.Show()
+		linkSettings.Hide()
+		linkBlock.Hide()
+	}
+
 	subContainer := container.NewStack(
 		container.NewVBox(
 			container.NewStack(
@@ -7088,6 +9568,18 @@ func layoutPM() fyne.CanvasObject {
 			),
 			rectSpacer,
 			rectSpacer,
+			container.NewCenter(
+				layout.NewSpacer(),
+				linkSettings,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			container.NewCenter(
+				layout.NewSpacer(),
+				linkBlock,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
 			container.NewCenter(
 				layout.NewSpacer(),
 				linkBack,
@@ -8657,106 +11149,169 @@ func layoutIdentity() fyne.CanvasObject {
 
 	btnReg := widget.NewButton(" Register ", nil)
 	btnReg.Disable()
+
+	queueData := usernameQueue.entries()
+	queueBox := widget.NewList(
+		func() int {
+			return len(queueData)
+		},
+		func() fyne.CanvasObject {
+			return container.NewVBox(
+				widget.NewLabel(""),
+			)
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id >= len(queueData) {
+				return
+			}
+
+			e := queueData[id]
+			str := e.Name
+			if len(str) > DEFAULT_USERADDR_SHORTEN_LENGTH+3 {
+				str = "..." + str[len(str)-DEFAULT_USERADDR_SHORTEN_LENGTH:]
+			}
+
+			str = str + "  ·  " + e.Status
+			if e.Error != "" {
+				str = str + ": " + e.Error
+			}
+
+			label := co.(*fyne.Container).Objects[0].(*widget.Label)
+			label.Wrapping = fyne.TextWrapWord
+			label.Alignment = fyne.TextAlignLeading
+			label.SetText(str)
+		})
+
+	queueBox.OnSelected = func(id widget.ListItemID) {
+		queueBox.UnselectAll()
+		if id < len(queueData) && queueData[id].TXID != "" {
+			a.Clipboard().SetContent(queueData[id].TXID)
+		}
+	}
+
+	rectQueueBox := canvas.NewRectangle(color.Transparent)
+	rectQueueBox.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.12))
+
+	linkClear := widget.NewHyperlinkWithStyle("Clear Finished", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClear.OnTapped = func() {
+		usernameQueue.clear()
+		queueData = usernameQueue.entries()
+		queueBox.Refresh()
+	}
+
+	queueForm := container.NewVBox(
+		container.NewStack(
+			rectQueueBox,
+			queueBox,
+		),
+		container.NewCenter(
+			linkClear,
+		),
+	)
+
+	if len(queueData) == 0 {
+		queueForm.Hide()
+	}
+
 	btnReg.OnTapped = func() {
-		if len(session.NewUser) > 5 {
-			valid, _ := checkUsername(session.NewUser, -1)
-			if valid == "" {
-				btnReg.Text = "Confirming..."
-				btnReg.Disable()
-				btnReg.Refresh()
-				entryReg.Disable()
-				storage, err := registerUsername(session.NewUser)
-				if err != nil {
-					if strings.Contains(err.Error(), "somehow the tx could not be built") {
-						btnReg.Text = fmt.Sprintf("Insufficient Balance: Need %v", globals.FormatMoney(storage))
-					} else {
-						btnReg.Text = "Unable to register..."
-					}
-					btnReg.Refresh()
-					logger.Errorf("[Username] %s\n", err)
-				} else {
-					go func() {
-						entryReg.Text = ""
-						entryReg.Refresh()
-						walletapi.WaitNewHeightBlock()
-						sHeight := walletapi.Get_Daemon_Height()
+		err := queueUsernames(strings.Split(session.NewUser, ","))
+		if err != nil {
+			btnReg.Text = "Unable to register..."
+			btnReg.Disable()
+			btnReg.Refresh()
+			entryReg.SetValidationError(err)
+			logger.Errorf("[Username] %s\n", err)
+			return
+		}
 
-						for {
-							if session.Domain == "app.Identity" {
-								//vars, _, _, err := gnomon.Index.RPC.GetSCVariables("0000000000000000000000000000000000000000000000000000000000000001", engram.Disk.Get_Daemon_TopoHeight(), nil, []string{session.NewUser}, nil, false)
-								usernames, err := queryUsernames(engram.Disk.GetAddress().String())
-								if err != nil {
-									logger.Errorf("[Username] Error querying usernames: %s\n", err)
-									btnReg.Text = "Error querying usernames"
-									btnReg.Refresh()
-									return
-								}
+		session.NewUser = ""
+		entryReg.SetText("")
+		btnReg.Text = " Register "
+		btnReg.Disable()
+		btnReg.Refresh()
+		queueData = usernameQueue.entries()
+		queueForm.Show()
+		queueBox.Refresh()
+	}
 
-								for u := range usernames {
-									if usernames[u] == session.NewUser {
-										logger.Printf("[Username] Successfully registered username: %s\n", session.NewUser)
-										_ = tx
-										btnReg.Text = "Registration successful!"
-										btnReg.Refresh()
-										session.NewUser = ""
-										session.Window.SetContent(layoutIdentity())
-										return
-									}
-								}
+	registered := 0
+	for _, e := range queueData {
+		if e.Status == USERNAME_STATUS_REGISTERED {
+			registered++
+		}
+	}
 
-								// If we go DEFAULT_CONFIRMATION_TIMEOUT blocks without exiting 'Confirming...' loop, display failed to transfer and break
-								if walletapi.Get_Daemon_Height() > sHeight+int64(DEFAULT_CONFIRMATION_TIMEOUT) {
-									btnReg.Text = "Unable to register..."
-									btnReg.Refresh()
-									break
-								}
+	usernameQueue.Lock()
+	usernameQueue.OnUpdate = func() {
+		if session.Domain != "app.Identity" {
+			return
+		}
 
-								// If daemon height has incremented, print retry counters into button space
-								if walletapi.Get_Daemon_Height()-sHeight > 0 {
-									btnReg.Text = fmt.Sprintf("Confirming... (%d/%d)", walletapi.Get_Daemon_Height()-sHeight, DEFAULT_CONFIRMATION_TIMEOUT)
-									btnReg.Refresh()
-								}
-							} else {
-								break
-							}
+		queueData = usernameQueue.entries()
+		if len(queueData) > 0 {
+			queueForm.Show()
+		}
+		queueBox.Refresh()
 
-							time.Sleep(time.Second * 1)
-						}
-					}()
-				}
+		count := 0
+		for _, e := range queueData {
+			if e.Status == USERNAME_STATUS_REGISTERED {
+				count++
 			}
 		}
+
+		// Reload the usernames and primary username once a new registration is verified
+		if count > registered {
+			registered = count
+			session.Window.SetContent(layoutIdentity())
+		}
 	}
+	usernameQueue.Unlock()
 
-	entryReg.PlaceHolder = "New Username"
+	entryReg.PlaceHolder = "New Username(s), comma separated"
 	entryReg.Validator = func(s string) error {
 		btnReg.Text = " Register "
-		btnReg.Enable()
 		btnReg.Refresh()
 		session.NewUser = s
-		// Name Service SCID Logic
-		//	15  IF STRLEN(name) >= 64 THEN GOTO 50 // skip names misuse
-		//	20  IF STRLEN(name) >= 6 THEN GOTO 40
-		if len(s) > 5 && len(s) < 64 {
-			valid, _ := checkUsername(s, -1)
-			if valid == "" {
-				btnReg.Enable()
-				btnReg.Refresh()
+
+		names := strings.Split(s, ",")
+		for _, n := range names {
+			n = strings.TrimSpace(n)
+			// Name Service SCID Logic
+			//	15  IF STRLEN(name) >= 64 THEN GOTO 50 // skip names misuse
+			//	20  IF STRLEN(name) >= 6 THEN GOTO 40
+			if len(n) > 5 && len(n) < 64 {
+				valid, _ := checkUsername(n, -1)
+				if valid != "" {
+					btnReg.Disable()
+					err := fmt.Errorf("username %s already exists", n)
+					entryReg.SetValidationError(err)
+					btnReg.Refresh()
+					return err
+				}
 			} else {
 				btnReg.Disable()
-				err := errors.New("username already exists")
+				err := errors.New("username too short need a minimum of six characters")
+				if len(n) > 63 {
+					err = errors.New("username too long need a maximum of 63 characters")
+				}
 				entryReg.SetValidationError(err)
 				btnReg.Refresh()
 				return err
 			}
-		} else {
+		}
+
+		if len(names) > DEFAULT_USERNAME_QUEUE_LIMIT {
 			btnReg.Disable()
-			err := errors.New("username too short need a minimum of six characters")
+			err := fmt.Errorf("a maximum of %d usernames can be queued", DEFAULT_USERNAME_QUEUE_LIMIT)
 			entryReg.SetValidationError(err)
 			btnReg.Refresh()
 			return err
 		}
 
+		btnReg.Enable()
+		btnReg.Refresh()
+
 		return nil
 	}
 
@@ -8850,6 +11405,7 @@ func layoutIdentity() fyne.CanvasObject {
 		rectSpacer,
 		btnReg,
 		rectSpacer,
+		queueForm,
 		rectSpacer,
 		rectSpacer,
 		rectSpacer,
@@ -9410,6 +11966,7 @@ func layoutHistory() fyne.CanvasObject {
 	var zeroscid crypto.Hash
 	var listData binding.StringList
 	var listBox *widget.List
+	var menu *widget.Select
 	var txid string
 
 	view := ""
@@ -9469,12 +12026,27 @@ func layoutHistory() fyne.CanvasObject {
 
 			split := strings.Split(str, ";;;")
 
+			// Show the registered username of the counterparty when one is known
+			if len(split) > 5 && split[5] != "" {
+				if username := addressUsername(split[5], listBox.Refresh); username != "" {
+					if len(username) > 10 {
+						username = username[0:10] + ".."
+					}
+
+					if menu.Selected == "Messages" {
+						split[1] = username
+					} else {
+						split[0] += " · " + username
+					}
+				}
+			}
+
 			co.(*fyne.Container).Objects[0].(*fyne.Container).Objects[1].(*widget.Label).SetText(split[0])
 			co.(*fyne.Container).Objects[1].(*fyne.Container).Objects[1].(*widget.Label).SetText(split[1])
 			co.(*fyne.Container).Objects[2].(*fyne.Container).Objects[1].(*widget.Label).SetText(split[3])
 		})
 
-	menu := widget.NewSelect([]string{"Normal", "Coinbase", "Messages"}, nil)
+	menu = widget.NewSelect([]string{"Normal", "Coinbase", "Messages"}, nil)
 	menu.PlaceHolder = "(Select Transaction Type)"
 
 	rectSpacer := canvas.NewRectangle(color.Transparent)
@@ -9513,6 +12085,15 @@ func layoutHistory() fyne.CanvasObject {
 		removeOverlays()
 	}
 
+	exportText := canvas.NewText("", colors.Green)
+	exportText.TextSize = 12
+	exportText.Alignment = fyne.TextAlignCenter
+
+	linkExport := widget.NewHyperlinkWithStyle("Export History", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkExport.OnTapped = func() {
+		showHistoryExport(zeroscid, 5, exportText)
+	}
+
 	label := canvas.NewText(view, colors.Account)
 	label.TextSize = 15
 	label.TextStyle = fyne.TextStyle{Bold: true}
@@ -9545,16 +12126,19 @@ func layoutHistory() fyne.CanvasObject {
 							amount := ""
 							txid = entries[e].TXID
 
+							// The counterparty is the destination of sent transfers and the sender of received ones
+							counterparty := entries[e].Destination
 							if !entries[e].Incoming {
 								direction = "Sent"
 								amount = "(" + globals.FormatMoney(entries[e].Amount) + ")"
 							} else {
 								direction = "Received"
 								amount = globals.FormatMoney(entries[e].Amount)
+								counterparty = entries[e].Sender
 							}
 
 							count += 1
-							data = append(data, direction+";;;"+amount+";;;"+height+";;;"+stamp+";;;"+txid)
+							data = append(data, direction+";;;"+amount+";;;"+height+";;;"+stamp+";;;"+txid+";;;"+counterparty)
 						}
 					}
 
@@ -9623,7 +12207,7 @@ func layoutHistory() fyne.CanvasObject {
 							txid = entries[e].TXID
 
 							count += 1
-							data = append(data, direction+";;;"+amount+";;;"+height+";;;"+stamp+";;;"+txid)
+							data = append(data, direction+";;;"+amount+";;;"+height+";;;"+stamp+";;;"+txid+";;;"+entries[e].Destination)
 						}
 					}
 
@@ -9679,8 +12263,14 @@ func layoutHistory() fyne.CanvasObject {
 						if entries[e].Payload_RPC.HasValue(rpc.RPC_COMMENT, rpc.DataString) {
 							contact := ""
 							username := ""
-							if entries[e].Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
+							// Sent messages carry our own reply-back address, so the recipient is shown for them
+							if !entries[e].Incoming {
+								contact = entries[e].Destination
+							} else if entries[e].Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) {
 								contact = entries[e].Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string)
+							}
+
+							if contact != "" {
 								if len(contact) > 10 {
 									username = contact[0:10] + ".."
 								} else {
@@ -9749,6 +12339,13 @@ func layoutHistory() fyne.CanvasObject {
 					rectList,
 					listBox,
 				),
+				rectSpacer,
+				container.NewHBox(
+					layout.NewSpacer(),
+					linkExport,
+					layout.NewSpacer(),
+				),
+				exportText,
 			),
 			layout.NewSpacer(),
 		),
@@ -9964,10 +12561,10 @@ func layoutHistoryDetail(txid string) fyne.CanvasObject {
 
 	if details.Payload_RPC.HasValue(rpc.RPC_REPLYBACK_ADDRESS, rpc.DataAddress) {
 		address := details.Payload_RPC.Value(rpc.RPC_REPLYBACK_ADDRESS, rpc.DataAddress).(rpc.Address)
-		valueReply.ParseMarkdown("" + address.String())
+		setAddressMarkdown(valueReply, address.String())
 	} else if details.Payload_RPC.HasValue(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString) && details.DestinationPort == 1337 {
 		address := details.Payload_RPC.Value(rpc.RPC_NEEDS_REPLYBACK_ADDRESS, rpc.DataString).(string)
-		valueReply.ParseMarkdown("" + address)
+		setAddressMarkdown(valueReply, address)
 	}
 
 	valuePayload := widget.NewRichTextFromMarkdown("--")
@@ -9992,7 +12589,7 @@ func layoutHistoryDetail(txid string) fyne.CanvasObject {
 		if details.Sender == "" || details.Sender == engram.Disk.GetAddress().String() {
 			valueMember.ParseMarkdown("--")
 		} else {
-			valueMember.ParseMarkdown("" + details.Sender)
+			setAddressMarkdown(valueMember, details.Sender)
 		}
 
 		if details.Amount == 0 {
@@ -10005,7 +12602,7 @@ func layoutHistoryDetail(txid string) fyne.CanvasObject {
 	} else {
 		valueDirection.Text = "  Sent"
 		labelMember.Text = "  RECEIVER  ADDRESS"
-		valueMember.ParseMarkdown("" + details.Destination)
+		setAddressMarkdown(valueMember, details.Destination)
 
 		if details.Amount == 0 {
 			valueAmount.Color = colors.Account
@@ -13234,7 +15831,7 @@ func layoutContractBuilder(promptText string) fyne.CanvasObject {
 			removeOverlays()
 			capture := session.Window.Content()
 			session.Window.SetContent(layoutTransition())
-			session.Window.SetContent(layoutContractEditor(strings.TrimSuffix(filename, ".bas"), string(filedata)))
+			session.Window.SetContent(layoutContractEditor(strings.TrimSuffix(filename, ".bas"), string(filedata), ""))
 			session.LastDomain = capture
 		}
 	}, session.Window)
@@ -13264,7 +15861,7 @@ func layoutContractBuilder(promptText string) fyne.CanvasObject {
 		removeOverlays()
 		capture := session.Window.Content()
 		session.Window.SetContent(layoutTransition())
-		session.Window.SetContent(layoutContractEditor("", ""))
+		session.Window.SetContent(layoutContractEditor("", "", ""))
 		session.LastDomain = capture
 	}
 
@@ -13350,7 +15947,7 @@ func layoutContractBuilder(promptText string) fyne.CanvasObject {
 					removeOverlays()
 					capture := session.Window.Content()
 					session.Window.SetContent(layoutTransition())
-					session.Window.SetContent(layoutContractEditor(strings.TrimSuffix(filepath.Base(filename), ".bas"), string(filedata)))
+					session.Window.SetContent(layoutContractEditor(strings.TrimSuffix(filepath.Base(filename), ".bas"), string(filedata), ""))
 					session.LastDomain = capture
 				}()
 			}
@@ -13388,7 +15985,7 @@ func layoutContractBuilder(promptText string) fyne.CanvasObject {
 				return
 			}
 
-			session.Window.SetContent(layoutContractEditor("", code))
+			session.Window.SetContent(layoutContractEditor("", code, ""))
 			session.LastDomain = capture
 		} else {
 			if s == "" {
@@ -13488,7 +16085,8 @@ func layoutContractBuilder(promptText string) fyne.CanvasObject {
 	return NewVScroll(layout)
 }
 
-func layoutContractEditor(filename, filedata string) fyne.CanvasObject {
+// When update is a SCID the editor holds its installed code and offers to update it instead of installing
+func layoutContractEditor(filename, filedata, update string) fyne.CanvasObject {
 	session.Domain = "app.sc.editor"
 
 	frame := &iframe{}
@@ -13654,11 +16252,18 @@ func layoutContractEditor(filename, filedata string) fyne.CanvasObject {
 
 	entryCode.SetText(filedata)
 
-	options := []string{"Initialize", "Set Headers", "New Function", "Parse", "Format", "Clear", "Export"}
+	// Installed code to diff updates against
+	installed := filedata
+
+	options := []string{"Initialize", "Set Headers", "New Function", "Parse", "Format", "Simulate", "Clear", "Export"}
 	if !session.Offline {
 		splice := append([]string{"Import Function"}, options[3:]...)
 		options = append(options[:3], splice...)
-		options = append(options, "Install")
+		if update != "" {
+			options = append(options, "Update Code")
+		} else {
+			options = append(options, "Install")
+		}
 	}
 
 	selectEditor := widget.NewSelect(options, nil)
@@ -13715,6 +16320,22 @@ func layoutContractEditor(filename, filedata string) fyne.CanvasObject {
 	labelSeparator.ParseMarkdown("---")
 
 	linkBack := widget.NewHyperlinkWithStyle("Back to Contract Builder", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	if update != "" {
+		linkBack.SetText("Back to Asset Manager")
+	}
+
+	back := func() {
+		capture := session.Window.Content()
+		session.Window.SetContent(layoutTransition())
+		if update != "" {
+			session.Domain = "app.manager"
+			session.Window.SetContent(layoutAssetManager(update))
+		} else {
+			session.Window.SetContent(layoutContractBuilder(""))
+		}
+		session.LastDomain = capture
+	}
+
 	linkBack.OnTapped = func() {
 		if unsavedChanges {
 			verificationOverlay(
@@ -13724,19 +16345,13 @@ func layoutContractEditor(filename, filedata string) fyne.CanvasObject {
 				"Confirm",
 				func(b bool) {
 					if b {
-						capture := session.Window.Content()
-						session.Window.SetContent(layoutTransition())
-						session.Window.SetContent(layoutContractBuilder(""))
-						session.LastDomain = capture
+						back()
 					}
 				},
 			)
 		} else {
 			removeOverlays()
-			capture := session.Window.Content()
-			session.Window.SetContent(layoutTransition())
-			session.Window.SetContent(layoutContractBuilder(""))
-			session.LastDomain = capture
+			back()
 		}
 	}
 
@@ -14254,6 +16869,41 @@ func layoutContractEditor(filename, filedata string) fyne.CanvasObject {
 					addFunction()
 				}
 			}
+		case "Simulate": // Run SC in a local DVM sandbox
+			if entryCode.Text == "" {
+				errorText.Text = "contract code is empty"
+				errorText.Color = colors.Red
+				errorText.Refresh()
+				return
+			}
+
+			_, pos, err := dvm.ParseSmartContract(entryCode.Text)
+			if err != nil {
+				errorText.Text = fmt.Sprintf("error parsing contract %s", pos)
+				errorText.Color = colors.Red
+				errorText.Refresh()
+				logger.Errorf("[Engram] Simulate SC: %s %s\n", err, pos)
+				return
+			}
+
+			overlay := session.Window.Canvas().Overlays()
+			overlay.Add(
+				container.NewStack(
+					&iframe{},
+					canvas.NewRectangle(colors.DarkMatter),
+				),
+			)
+			overlay.Add(
+				container.NewStack(
+					&iframe{},
+					NewVScroll(layoutDVMSandbox(entryCode.Text, func() {
+						overlay.Top().Hide()
+						overlay.Remove(overlay.Top())
+						overlay.Remove(overlay.Top())
+					})),
+				),
+			)
+			overlay.Top().Show()
 		case "Format": // Format SC code
 			if entryCode.Text == "" {
 				errorText.Text = "contract code is empty"
@@ -14359,6 +17009,41 @@ func layoutContractEditor(filename, filedata string) fyne.CanvasObject {
 			dialogFileSave.SetFileName(exportFileName)
 			dialogFileSave.Resize(fyne.NewSize(ui.Width, ui.Height))
 			dialogFileSave.Show()
+		case "Update Code": // Update installed SC code
+			if entryCode.Text == "" {
+				errorText.Text = "contract code is empty"
+				errorText.Color = colors.Red
+				errorText.Refresh()
+				return
+			}
+
+			_, pos, err := dvm.ParseSmartContract(entryCode.Text)
+			if err != nil {
+				errorText.Text = fmt.Sprintf("error parsing contract %s", pos)
+				errorText.Color = colors.Red
+				errorText.Refresh()
+				logger.Errorf("[Engram] Update SC: %s %s\n", err, pos)
+				return
+			}
+
+			overlay := session.Window.Canvas().Overlays()
+			overlay.Add(
+				container.NewStack(
+					&iframe{},
+					canvas.NewRectangle(colors.DarkMatter),
+				),
+			)
+			overlay.Add(
+				container.NewStack(
+					&iframe{},
+					NewVScroll(layoutContractUpdate(update, installed, entryCode.Text, func() {
+						overlay.Top().Hide()
+						overlay.Remove(overlay.Top())
+						overlay.Remove(overlay.Top())
+					})),
+				),
+			)
+			overlay.Top().Show()
 		case "Install": // Install SC
 			code := entryCode.Text
 			if code == "" {
@@ -14667,6 +17352,522 @@ func layoutContractEditor(filename, filedata string) fyne.CanvasObject {
 	return NewVScroll(layout)
 }
 
+// Review a contract code update, shows the diff against the installed code and the estimated gas before the update call is sent
+func layoutContractUpdate(scid string, current string, updated string, done func()) *fyne.Container {
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
+
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.4))
+
+	header := canvas.NewText("UPDATE  CONTRACT  CODE", colors.Gray)
+	header.TextSize = 14
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
+
+	title := canvas.NewText(scid[0:8]+"..."+scid[len(scid)-8:], colors.Account)
+	title.TextSize = 22
+	title.Alignment = fyne.TextAlignCenter
+	title.TextStyle = fyne.TextStyle{Bold: true}
+
+	summary := canvas.NewText("", colors.Gray)
+	summary.TextSize = 13
+	summary.Alignment = fyne.TextAlignCenter
+
+	gasText := canvas.NewText("Estimating gas...", colors.Gray)
+	gasText.TextSize = 13
+	gasText.Alignment = fyne.TextAlignCenter
+
+	warningText := canvas.NewText("", colors.Yellow)
+	warningText.TextSize = 12
+	warningText.Alignment = fyne.TextAlignCenter
+
+	errorText := canvas.NewText("", colors.Red)
+	errorText.TextSize = 12
+	errorText.Alignment = fyne.TextAlignCenter
+
+	btnSubmit := widget.NewButton("Submit Update", nil)
+	btnSubmit.Disable()
+
+	linkClose := widget.NewHyperlinkWithStyle("Close", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClose.OnTapped = func() {
+		done()
+	}
+
+	diff := diffContractCode(current, updated)
+
+	added, removed := 0, 0
+	for _, line := range diff {
+		switch {
+		case strings.HasPrefix(line, "+ "):
+			added++
+		case strings.HasPrefix(line, "- "):
+			removed++
+		}
+	}
+
+	summary.Text = fmt.Sprintf("Lines Added: %d   Lines Removed: %d", added, removed)
+
+	listDiff := widget.NewList(
+		func() int {
+			return len(diff)
+		},
+		func() fyne.CanvasObject {
+			text := canvas.NewText("", colors.Gray)
+			text.TextSize = 12
+			text.TextStyle = fyne.TextStyle{Monospace: true}
+			return text
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id >= len(diff) {
+				return
+			}
+
+			text := co.(*canvas.Text)
+			text.Text = diff[id]
+			switch {
+			case strings.HasPrefix(diff[id], "+ "):
+				text.Color = colors.Green
+			case strings.HasPrefix(diff[id], "- "):
+				text.Color = colors.Red
+			default:
+				text.Color = colors.Gray
+			}
+			text.Refresh()
+		},
+	)
+
+	// The installed code decides which entrypoint takes the update
+	entrypoint, param, ok := contractUpdateEntrypoint(current)
+	if !ok {
+		gasText.Text = ""
+		errorText.Text = "installed contract has no code update entrypoint"
+	} else if added == 0 && removed == 0 {
+		gasText.Text = ""
+		errorText.Text = "contract code is unchanged"
+	}
+
+	if _, _, updatable := contractUpdateEntrypoint(updated); !updatable {
+		warningText.Text = "new code has no update entrypoint, the contract cannot be updated again"
+	}
+
+	var gas uint64
+	if ok && (added > 0 || removed > 0) {
+		go func() {
+			estimate, err := estimateContractUpdate(crypto.HashHexToHash(scid), entrypoint, param, updated)
+			fyne.Do(func() {
+				if err != nil {
+					logger.Errorf("[%s] Error estimating fees: %s\n", entrypoint, err)
+					gasText.Text = ""
+					gasText.Refresh()
+					errorText.Text = "could not estimate gas for update"
+					errorText.Refresh()
+					return
+				}
+
+				gas = estimate
+				gasText.Text = fmt.Sprintf("Entrypoint: %s   Gas Storage: %s DERO", entrypoint, globals.FormatMoney(gas))
+				gasText.Refresh()
+				btnSubmit.Enable()
+			})
+		}()
+	}
+
+	btnSubmit.OnTapped = func() {
+		btnSubmit.Text = "Submitting..."
+		btnSubmit.Disable()
+		btnSubmit.Refresh()
+		linkClose.Hide()
+
+		go func() {
+			txid, err := updateContractCode(crypto.HashHexToHash(scid), entrypoint, param, updated, gas)
+			if err == nil {
+				fyne.Do(func() {
+					btnSubmit.Text = "Waiting for confirmation..."
+					btnSubmit.Refresh()
+				})
+
+				err = waitTxConfirmation(txid)
+				if err == nil {
+					// The cached headers and code hash are stale after the update
+					assetMetadata.refresh(scid)
+				}
+			}
+
+			fyne.Do(func() {
+				linkClose.Show()
+				if err != nil {
+					errorText.Text = err.Error()
+					errorText.Refresh()
+					btnSubmit.Text = "Submit Update"
+					btnSubmit.Enable()
+					btnSubmit.Refresh()
+					return
+				}
+
+				a.Clipboard().SetContent(txid)
+				btnSubmit.Text = "Contract code updated!"
+				btnSubmit.Refresh()
+				errorText.Text = "TXID copied to clipboard"
+				errorText.Color = colors.Green
+				errorText.Refresh()
+			})
+		}()
+	}
+
+	return container.NewCenter(
+		container.NewVBox(
+			span,
+			container.NewCenter(
+				header,
+			),
+			rectSpacer,
+			rectSpacer,
+			container.NewCenter(
+				title,
+			),
+			rectSpacer,
+			summary,
+			rectSpacer,
+			container.NewStack(
+				rectList,
+				listDiff,
+			),
+			rectSpacer,
+			gasText,
+			warningText,
+			errorText,
+			rectSpacer,
+			btnSubmit,
+			rectSpacer,
+			rectSpacer,
+			container.NewHBox(
+				layout.NewSpacer(),
+				linkClose,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			rectSpacer,
+		),
+	)
+}
+
+// Local DVM sandbox for the contract editor, the contract is initialized and called against in-memory state before it is deployed
+func layoutDVMSandbox(code string, done func()) *fyne.Container {
+	span := canvas.NewRectangle(color.Transparent)
+	span.SetMinSize(fyne.NewSize(ui.Width, 10))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(6, 5))
+
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(ui.Width, ui.Height*0.2))
+
+	header := canvas.NewText("DVM  SANDBOX", colors.Gray)
+	header.TextSize = 14
+	header.Alignment = fyne.TextAlignCenter
+	header.TextStyle = fyne.TextStyle{Bold: true}
+
+	subHeader := canvas.NewText("Calls run locally and are never sent to the network", colors.Account)
+	subHeader.TextSize = 13
+	subHeader.Alignment = fyne.TextAlignCenter
+
+	labelStorage := canvas.NewText("   CONTRACT  STORAGE", colors.Gray)
+	labelStorage.TextSize = 14
+	labelStorage.Alignment = fyne.TextAlignLeading
+	labelStorage.TextStyle = fyne.TextStyle{Bold: true}
+
+	resultText := canvas.NewText("", colors.Green)
+	resultText.TextSize = 13
+	resultText.Alignment = fyne.TextAlignCenter
+
+	gasText := canvas.NewText("", colors.Gray)
+	gasText.TextSize = 12
+	gasText.Alignment = fyne.TextAlignCenter
+
+	entrySigner := widget.NewEntry()
+	entrySigner.PlaceHolder = "SIGNER (Address, empty for anonymous)"
+	if engram.Disk != nil {
+		entrySigner.SetText(engram.Disk.GetAddress().String())
+	}
+
+	entryDERO := widget.NewEntry()
+	entryDERO.PlaceHolder = "DEROVALUE (DERO Amount)"
+
+	entryAsset := widget.NewEntry()
+	entryAsset.PlaceHolder = "ASSETVALUE SCID"
+
+	entryAssetAmount := widget.NewEntry()
+	entryAssetAmount.PlaceHolder = "ASSETVALUE (Atomic Units)"
+
+	selectFunction := widget.NewSelect(nil, nil)
+	selectFunction.PlaceHolder = "(Select Function)"
+
+	paramBox := container.NewVBox()
+	paramEntries := map[string]*widget.Entry{}
+
+	btnRun := widget.NewButton("Run Function", nil)
+	btnRun.Disable()
+
+	linkReset := widget.NewHyperlinkWithStyle("Reset Sandbox", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	linkClose := widget.NewHyperlinkWithStyle("Close", nil, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	var sandbox *DVMSandbox
+	var lines []string
+
+	listStorage := widget.NewList(
+		func() int {
+			return len(lines)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id < len(lines) {
+				co.(*widget.Label).SetText(lines[id])
+			}
+		},
+	)
+	listStorage.OnSelected = func(id widget.ListItemID) {
+		listStorage.UnselectAll()
+		if id < len(lines) {
+			a.Clipboard().SetContent(lines[id])
+			gasText.Text = "Copied to clipboard"
+			gasText.Refresh()
+		}
+	}
+
+	refreshStorage := func() {
+		lines = nil
+		if sandbox != nil {
+			lines = sandbox.storage()
+		}
+		listStorage.Refresh()
+	}
+
+	// Offer the initializer until the contract is installed, then its exported functions
+	refresh := func() {
+		refreshStorage()
+		if sandbox == nil {
+			selectFunction.SetOptions(nil)
+			selectFunction.Disable()
+			return
+		}
+
+		if sandbox.Installed {
+			selectFunction.SetOptions(sandbox.functions())
+		} else {
+			selectFunction.SetOptions([]string{sandbox.initializer()})
+		}
+		selectFunction.ClearSelected()
+		selectFunction.Enable()
+	}
+
+	reset := func() {
+		var err error
+		sandbox, err = newDVMSandbox(code)
+		if err != nil {
+			sandbox = nil
+			resultText.Text = err.Error()
+			resultText.Color = colors.Red
+		} else {
+			resultText.Text = ""
+		}
+		resultText.Refresh()
+		gasText.Text = ""
+		gasText.Refresh()
+		refresh()
+	}
+
+	selectFunction.OnChanged = func(s string) {
+		paramBox.RemoveAll()
+		paramEntries = map[string]*widget.Entry{}
+		btnRun.Disable()
+
+		if sandbox == nil || s == "" {
+			return
+		}
+
+		function, ok := sandbox.Contract.Functions[s]
+		if !ok {
+			return
+		}
+
+		for _, p := range function.Params {
+			// The value parameter is filled from DEROVALUE
+			if p.Type == dvm.Uint64 && p.Name == "value" {
+				continue
+			}
+
+			entry := widget.NewEntry()
+			entry.PlaceHolder = p.Name
+			if p.Type == dvm.Uint64 {
+				entry.PlaceHolder = p.Name + " (Numbers Only)"
+			}
+			paramEntries[p.Name] = entry
+			paramBox.Add(entry)
+		}
+
+		if sandbox.Installed {
+			btnRun.SetText("Run Function")
+		} else {
+			btnRun.SetText("Initialize Contract")
+		}
+		btnRun.Enable()
+	}
+
+	running := false
+	btnRun.OnTapped = func() {
+		if sandbox == nil || selectFunction.Selected == "" || running {
+			return
+		}
+
+		resultText.Color = colors.Red
+
+		var dero, assetValue uint64
+		var err error
+		if entryDERO.Text != "" {
+			if dero, err = globals.ParseAmount(entryDERO.Text); err != nil {
+				resultText.Text = "invalid DEROVALUE amount"
+				resultText.Refresh()
+				return
+			}
+		}
+
+		var asset crypto.Hash
+		if entryAssetAmount.Text != "" {
+			if assetValue, err = strconv.ParseUint(entryAssetAmount.Text, 10, 64); err != nil {
+				resultText.Text = "invalid ASSETVALUE amount"
+				resultText.Refresh()
+				return
+			}
+
+			if len(entryAsset.Text) != 64 {
+				resultText.Text = "invalid ASSETVALUE SCID"
+				resultText.Refresh()
+				return
+			}
+			asset = crypto.HashHexToHash(entryAsset.Text)
+		}
+
+		params := map[string]string{}
+		for name, entry := range paramEntries {
+			params[name] = entry.Text
+		}
+
+		// Contracts can run up to the gas limit, the sandbox runs off the UI thread and a reset discards the result
+		sb := sandbox
+		installed := sb.Installed
+		entrypoint := selectFunction.Selected
+		signer := entrySigner.Text
+
+		running = true
+		btnRun.Disable()
+		resultText.Text = "running " + entrypoint + "..."
+		resultText.Color = colors.Gray
+		resultText.Refresh()
+
+		go func() {
+			result := sb.run(entrypoint, signer, dero, asset, assetValue, params)
+
+			fyne.Do(func() {
+				running = false
+				if sandbox != sb {
+					return
+				}
+
+				btnRun.Enable()
+				resultText.Color = colors.Red
+
+				gasText.Text = fmt.Sprintf("Gas Compute: %d   Gas Storage: %d   Transfers: %d", result.GasCompute, result.GasStorage, len(result.Transfers))
+				gasText.Refresh()
+
+				switch {
+				case result.Err != nil:
+					logger.Errorf("[DVM Sandbox] %s: %s\n", result.Entrypoint, result.Err)
+					message := strings.Split(result.Err.Error(), "\n")[0]
+					if len(message) > 60 {
+						message = message[0:60] + "..."
+					}
+					resultText.Text = message
+				case result.Return != 0:
+					resultText.Text = fmt.Sprintf("%s returned %d, changes discarded", result.Entrypoint, result.Return)
+					resultText.Color = colors.Yellow
+				default:
+					resultText.Text = fmt.Sprintf("%s returned 0, changes stored", result.Entrypoint)
+					resultText.Color = colors.Green
+				}
+				resultText.Refresh()
+
+				logger.Printf("[DVM Sandbox] %s returned %d - Gas: %d compute %d storage\n", result.Entrypoint, result.Return, result.GasCompute, result.GasStorage)
+
+				if sb.Installed != installed {
+					refresh()
+				} else {
+					refreshStorage()
+				}
+			})
+		}()
+	}
+
+	linkReset.OnTapped = func() {
+		reset()
+	}
+
+	linkClose.OnTapped = func() {
+		done()
+	}
+
+	reset()
+
+	return container.NewCenter(
+		container.NewVBox(
+			span,
+			container.NewCenter(
+				header,
+			),
+			rectSpacer,
+			container.NewCenter(
+				subHeader,
+			),
+			rectSpacer,
+			rectSpacer,
+			selectFunction,
+			paramBox,
+			rectSpacer,
+			entrySigner,
+			entryDERO,
+			entryAsset,
+			entryAssetAmount,
+			rectSpacer,
+			btnRun,
+			rectSpacer,
+			resultText,
+			gasText,
+			rectSpacer,
+			labelStorage,
+			rectSpacer,
+			container.NewStack(
+				rectList,
+				listStorage,
+			),
+			rectSpacer,
+			container.NewHBox(
+				layout.NewSpacer(),
+				linkReset,
+				layout.NewSpacer(),
+				linkClose,
+				layout.NewSpacer(),
+			),
+			rectSpacer,
+			rectSpacer,
+		),
+	)
+}
+
 func layoutTELA() fyne.CanvasObject {
 	session.Domain = "app.tela"
 
@@ -52,6 +52,64 @@ const (
 	DEFAULT_CONFIRMATION_TIMEOUT     = 5
 	DEFAULT_DAEMON_RECONNECT_TIMEOUT = 10
 	DEFAULT_USERADDR_SHORTEN_LENGTH  = 10
+	DEFAULT_MESSAGE_MAX_PARTS        = 16
+	DEFAULT_MESSAGE_PORT             = 1337
+	DEFAULT_MESSAGE_AMOUNT           = 1
+	DEFAULT_MESSAGE_EXPIRY           = 3600
+	DEFAULT_MESSAGE_MAX_RECIPIENTS   = 16
+	MESSAGE_ARCHIVE_VERSION          = 1
+	DEFAULT_USERNAME_CACHE_BLOCKS    = 100
+	NAME_SERVICE_SCID                = "0000000000000000000000000000000000000000000000000000000000000001"
+	DEFAULT_USERNAME_QUEUE_LIMIT     = 10
+	USERNAME_STATUS_QUEUED           = "Queued"
+	USERNAME_STATUS_CONFIRMING       = "Confirming"
+	USERNAME_STATUS_REGISTERED       = "Registered"
+	USERNAME_STATUS_FAILED           = "Failed"
+	GNOMON_PROFILE_LIGHT             = "Light"
+	GNOMON_PROFILE_FULL              = "Full"
+	GNOMON_PROFILE_CUSTOM            = "Custom"
+	DEFAULT_GNOMON_MAX_PARALLEL      = 10
+	DEFAULT_GNOMON_PAGE_LIMIT        = 100
+	DEFAULT_GNOMON_MAX_PAGE_LIMIT    = 1000
+	DEFAULT_GNOMON_STATUS_INTERVAL   = 30
+	GNOMON_EVENT_INVOKE              = "gnomon_new_invoke"
+	GNOMON_EVENT_VARIABLE            = "gnomon_variable_change"
+	GNOMON_EVENT_SCID                = "gnomon_new_scid"
+	GNOMON_SNAPSHOT_VERSION          = 1
+	GNOMON_SNAPSHOT_MANIFEST         = "manifest.json"
+	DEFAULT_ASSET_SCAN_WORKERS       = 8
+	DEFAULT_ASSET_METADATA_EXPIRY    = 86400
+	DEFAULT_ASSET_METADATA_WORKERS   = 4
+	MAX_ASSET_DECIMALS               = 18
+	ASSET_STANDARD_G45_NFT           = "G45-NFT"
+	ASSET_STANDARD_G45_AT            = "G45-AT"
+	ASSET_STANDARD_G45_FAT           = "G45-FAT"
+	ASSET_STANDARD_G45_C             = "G45-C"
+	ASSET_STANDARD_NFA               = "ART-NFA"
+	ASSET_STANDARD_TELA_INDEX        = "TELA-INDEX"
+	ASSET_STANDARD_TELA_DOC          = "TELA-DOC"
+	ASSET_STANDARD_TOKEN             = "Token"
+	ASSET_STANDARD_CONTRACT          = "Contract"
+	NFA_LIST_SALE                    = "sale"
+	NFA_LIST_AUCTION                 = "auction"
+	NFA_ACTION_START                 = "Start"
+	NFA_ACTION_BID_OR_BUY            = "BidOrBuy"
+	NFA_ACTION_CANCEL                = "CancelListing"
+	NFA_ACTION_CLOSE                 = "CloseListing"
+	NFA_ACTION_CLAIM                 = "ClaimOwnership"
+	DEFAULT_AIRDROP_BATCH_SIZE       = 4
+	AIRDROP_STATUS_PENDING           = "Pending"
+	AIRDROP_STATUS_SENDING           = "Sending"
+	AIRDROP_STATUS_SENT              = "Sent"
+	AIRDROP_STATUS_FAILED            = "Failed"
+	DVM_GAS_COMPUTE_LIMIT            = 10000000
+	CONTRACT_PARAM_UINT64            = "Uint64"
+	CONTRACT_PARAM_STRING            = "String"
+	CONTRACT_PARAM_HEX               = "Hex"
+	CONTRACT_PARAM_ADDRESS           = "Address"
+	MESSAGE_ARG_ID                   = "MI"
+	MESSAGE_ARG_PART                 = "MP"
+	MESSAGE_ARG_PARTS                = "MT"
 	NETWORK_MAINNET                  = "Mainnet"
 	NETWORK_TESTNET                  = "Testnet"
 	NETWORK_SIMULATOR                = "Simulator"
@@ -65,6 +123,11 @@ var session Session
 var gnomon Gnomon
 var msgbox MessageBox
 var messages Messages
+var nameService = NameService{Marker: -1}
+var usernameQueue UsernameQueue
+var assets AssetTracker
+var assetMetadata AssetMetadataCache
+var airdrop AirdropRunner
 var status Status
 var tx Transfers
 var res Res
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -11,6 +13,8 @@ import (
 	"github.com/civilware/epoch"
 	"github.com/civilware/tela"
 	"github.com/creachadair/jrpc2/handler"
+	"github.com/deroproject/derohe/walletapi/rpcserver"
+	"github.com/deroproject/derohe/walletapi/xswd"
 )
 
 // Further methods to add to XSWD,
@@ -18,6 +22,7 @@ import (
 var EngramHandler = map[string]handler.Func{
 	"GetPrimaryUsername":                         handler.New(GetPrimaryUsername),
 	"Gnomon.GetLastIndexHeight":                  handler.New(GetLastIndexHeight),
+	"Gnomon.GetSyncStatus":                       handler.New(GetSyncStatus),
 	"Gnomon.GetTxCount":                          handler.New(GetTxCount),
 	"Gnomon.GetOwner":                            handler.New(GetOwner),
 	"Gnomon.GetAllOwnersAndSCIDs":                handler.New(GetAllOwnersAndSCIDs),
@@ -40,6 +45,10 @@ var EngramHandler = map[string]handler.Func{
 	"Gnomon.GetMiniblockDetailsByHash":           handler.New(GetMiniblockDetailsByHash),
 	"Gnomon.GetMiniblockCountByAddress":          handler.New(GetMiniblockCountByAddress),
 	"Gnomon.GetSCIDInteractionByAddr":            handler.New(GetSCIDInteractionByAddr),
+	"Gnomon.GetOwnersAndSCIDsPaged":              handler.New(GetOwnersAndSCIDsPaged),
+	"Gnomon.GetSCIDVariableDetailsPaged":         handler.New(GetSCIDVariableDetailsPaged),
+	"Gnomon.GetMiniblockDetailsPaged":            handler.New(GetMiniblockDetailsPaged),
+	"Gnomon.GetSCIDInvokeDetailsPaged":           handler.New(GetSCIDInvokeDetailsPaged),
 }
 
 type SCID_Param struct {
@@ -50,6 +59,52 @@ type Address_Param struct {
 	Address string `json:"address"`
 }
 
+// Page_Params are used by the paged Gnomon methods, after is the next cursor of the previous page and empty for the first page.
+// Limit 0 will use DEFAULT_GNOMON_PAGE_LIMIT
+type Page_Params struct {
+	After string `json:"after"`
+	Limit int    `json:"limit"`
+}
+
+// Page_Result is returned by the paged Gnomon methods, next is the cursor of the following page or empty when there are no more results
+type Page_Result struct {
+	Next string `json:"next"`
+}
+
+// Get the page limit
+func (p Page_Params) limit() (limit int, err error) {
+	limit = p.Limit
+	if limit == 0 {
+		limit = DEFAULT_GNOMON_PAGE_LIMIT
+	}
+
+	if limit < 0 || limit > DEFAULT_GNOMON_MAX_PAGE_LIMIT {
+		err = fmt.Errorf("limit must be between 1 and %d", DEFAULT_GNOMON_MAX_PAGE_LIMIT)
+	}
+
+	return
+}
+
+// Get the bounds of the page after the cursor within sorted keys, keys are stable so pages do not shift as the index grows
+func (p Page_Params) bounds(keys []string) (start, end int, page Page_Result, err error) {
+	limit, err := p.limit()
+	if err != nil {
+		return
+	}
+
+	start = sort.SearchStrings(keys, p.After)
+	if p.After != "" && start < len(keys) && keys[start] == p.After {
+		start++
+	}
+
+	end = min(start+limit, len(keys))
+	if end < len(keys) {
+		page.Next = keys[end-1]
+	}
+
+	return
+}
+
 // GetPrimaryUsername result
 type Username_Result struct {
 	Username string `json:"username"`
@@ -251,6 +306,68 @@ func GetLastIndexHeight(ctx context.Context) (result GetLastIndexHeight_Result,
 	return
 }
 
+// GetSyncStatus
+type GetSyncStatus_Result struct {
+	Active            bool    `json:"active"`
+	Status            string  `json:"status"`
+	Endpoint          string  `json:"endpoint,omitempty"`
+	LastIndexedHeight int64   `json:"lastIndexedHeight"`
+	ChainHeight       int64   `json:"chainHeight"`
+	Progress          float64 `json:"progress"`
+	BlocksPerSecond   float64 `json:"blocksPerSecond"`
+	ETA               int64   `json:"eta"`
+	SCIDsIndexed      int     `json:"scidsIndexed"`
+	DBSize            int64   `json:"dbSize"`
+}
+
+// GetSyncStatus gets Gnomon's sync progress, ETA is in seconds and -1 when unknown, DBSize is in bytes
+func GetSyncStatus(ctx context.Context) (result GetSyncStatus_Result, err error) {
+	if gnomon.Index == nil {
+		err = fmt.Errorf("gnomon is not active")
+		return
+	}
+
+	result = getGnomonSyncStatus()
+
+	// The Gnomon endpoint can reveal the user's node, it is not shared with applications
+	result.Endpoint = ""
+
+	return
+}
+
+// GnomonEvent is the value of the Gnomon events broadcast to subscribed XSWD applications
+type GnomonEvent struct {
+	SCID   string                `json:"scid"`
+	Height int64                 `json:"height"`
+	Invoke *structures.SCTXParse `json:"invoke,omitempty"`
+	Key    string                `json:"key,omitempty"`
+	Value  string                `json:"value,omitempty"`
+	Owner  string                `json:"owner,omitempty"`
+}
+
+// Subscribe wraps the XSWD Subscribe method so Engram can track the Gnomon events applications subscribe to,
+// the application's event map is written under the lock Gnomon events are broadcast with
+func Subscribe(ctx context.Context, p xswd.Subscribe_Params) bool {
+	w := rpcserver.FromContext(ctx)
+	app, ok := w.Extra["app_data"].(*xswd.ApplicationData)
+	if !ok {
+		return false
+	}
+
+	gnomon.Events.Subscriptions.Lock()
+	defer gnomon.Events.Subscriptions.Unlock()
+
+	if !xswd.Subscribe(ctx, p) {
+		return false
+	}
+
+	if strings.HasPrefix(string(p.Event), "gnomon_") {
+		gnomon.Events.Subscriptions.add(app.Id, p.Event)
+	}
+
+	return true
+}
+
 // GetTxCount
 type (
 	GetTxCount_Params struct {
@@ -857,3 +974,191 @@ func GetSCIDInteractionByAddr(ctx context.Context, p Address_Param) (result GetS
 
 	return
 }
+
+// GetOwnersAndSCIDsPaged
+type (
+	GetOwnersAndSCIDsPaged_Params struct {
+		Owner string `json:"owner"`
+		Page_Params
+	}
+
+	OwnerAndSCID struct {
+		SCID  string `json:"scid"`
+		Owner string `json:"owner"`
+	}
+
+	GetOwnersAndSCIDsPaged_Result struct {
+		Owners []OwnerAndSCID `json:"owners"`
+		Page_Result
+	}
+)
+
+// GetOwnersAndSCIDsPaged from Gnomon sorted by SCID, optionally filtered by owner. The cursor is the last SCID of the previous page
+func GetOwnersAndSCIDsPaged(ctx context.Context, p GetOwnersAndSCIDsPaged_Params) (result GetOwnersAndSCIDsPaged_Result, err error) {
+	if gnomon.Index == nil {
+		err = fmt.Errorf("gnomon is not active")
+		return
+	}
+
+	limit, err := p.limit()
+	if err != nil {
+		return
+	}
+
+	owners, more := gnomon.GetOwnersAndSCIDsAfter(p.After, limit, p.Owner)
+	if more {
+		result.Next = owners[len(owners)-1].SCID
+	}
+
+	result.Owners = owners
+
+	return
+}
+
+// GetSCIDVariableDetailsPaged
+type (
+	GetSCIDVariableDetailsPaged_Params struct {
+		SCID      string `json:"scid"`
+		KeyPrefix string `json:"keyPrefix"`
+		Page_Params
+	}
+
+	GetSCIDVariableDetailsPaged_Result struct {
+		Variables []*structures.SCIDVariable `json:"variables"`
+		Page_Result
+	}
+)
+
+// GetSCIDVariableDetailsPaged from Gnomon sorted by key, optionally filtered by key prefix. The cursor is the last key of the previous page
+func GetSCIDVariableDetailsPaged(ctx context.Context, p GetSCIDVariableDetailsPaged_Params) (result GetSCIDVariableDetailsPaged_Result, err error) {
+	if gnomon.Index == nil {
+		err = fmt.Errorf("gnomon is not active")
+		return
+	}
+
+	// Variables are stored per height and merged to their latest values, so all of them are read
+	var keys []string
+	vars := make(map[string]*structures.SCIDVariable)
+	for _, v := range gnomon.GetAllSCIDVariableDetails(p.SCID) {
+		key := fmt.Sprint(v.Key)
+		if _, ok := vars[key]; !ok && strings.HasPrefix(key, p.KeyPrefix) {
+			keys = append(keys, key)
+			vars[key] = v
+		}
+	}
+
+	sort.Strings(keys)
+
+	start, end, page, err := p.bounds(keys)
+	if err != nil {
+		return
+	}
+
+	for _, key := range keys[start:end] {
+		result.Variables = append(result.Variables, vars[key])
+	}
+	result.Page_Result = page
+
+	return
+}
+
+// GetMiniblockDetailsPaged
+type (
+	GetMiniblockDetailsPaged_Params struct {
+		Miner string `json:"miner"`
+		Page_Params
+	}
+
+	MiniblockDetails struct {
+		Blid       string                `json:"blid"`
+		MBLdetails []*structures.MBLInfo `json:"mblDetails"`
+	}
+
+	GetMiniblockDetailsPaged_Result struct {
+		Blocks []MiniblockDetails `json:"blocks"`
+		Page_Result
+	}
+)
+
+// GetMiniblockDetailsPaged from Gnomon sorted by block hash, optionally filtered to the miniblocks of a miner. The cursor is the last block hash of the previous page
+func GetMiniblockDetailsPaged(ctx context.Context, p GetMiniblockDetailsPaged_Params) (result GetMiniblockDetailsPaged_Result, err error) {
+	if gnomon.Index == nil {
+		err = fmt.Errorf("gnomon is not active")
+		return
+	}
+
+	limit, err := p.limit()
+	if err != nil {
+		return
+	}
+
+	blocks, more := gnomon.GetMiniblockDetailsAfter(p.After, limit, p.Miner)
+	if more {
+		result.Next = blocks[len(blocks)-1].Blid
+	}
+
+	result.Blocks = blocks
+
+	return
+}
+
+// GetSCIDInvokeDetailsPaged
+type (
+	GetSCIDInvokeDetailsPaged_Params struct {
+		SCID       string `json:"scid"`
+		Entrypoint string `json:"entrypoint"`
+		Signer     string `json:"signer"`
+		MinHeight  int64  `json:"minHeight"`
+		MaxHeight  int64  `json:"maxHeight"`
+		Page_Params
+	}
+
+	GetSCIDInvokeDetailsPaged_Result struct {
+		Invokes []*structures.SCTXParse `json:"invokeDetails"`
+		Page_Result
+	}
+)
+
+// GetSCIDInvokeDetailsPaged from Gnomon sorted by height, optionally filtered by entrypoint, signer and height range, a max height of 0 has no upper bound.
+// The cursor is the height and TXID of the last invoke of the previous page
+func GetSCIDInvokeDetailsPaged(ctx context.Context, p GetSCIDInvokeDetailsPaged_Params) (result GetSCIDInvokeDetailsPaged_Result, err error) {
+	if gnomon.Index == nil {
+		err = fmt.Errorf("gnomon is not active")
+		return
+	}
+
+	var keys []string
+	invokes := make(map[string]*structures.SCTXParse)
+	for _, i := range gnomon.GetAllSCIDInvokeDetails(p.SCID) {
+		if p.Entrypoint != "" && i.Entrypoint != p.Entrypoint {
+			continue
+		}
+
+		if p.Signer != "" && i.Sender != p.Signer {
+			continue
+		}
+
+		if i.Height < p.MinHeight || (p.MaxHeight > 0 && i.Height > p.MaxHeight) {
+			continue
+		}
+
+		// Heights are padded so the keys sort by height, then TXID
+		key := fmt.Sprintf("%020d:%s", i.Height, i.Txid)
+		keys = append(keys, key)
+		invokes[key] = i
+	}
+
+	sort.Strings(keys)
+
+	start, end, page, err := p.bounds(keys)
+	if err != nil {
+		return
+	}
+
+	for _, key := range keys[start:end] {
+		result.Invokes = append(result.Invokes, invokes[key])
+	}
+	result.Page_Result = page
+
+	return
+}
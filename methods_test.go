@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/civilware/Gnomon/indexer"
+	"github.com/civilware/Gnomon/storage"
+)
+
+func TestPageLimit(t *testing.T) {
+	tests := []struct {
+		limit    int
+		expected int
+		err      bool
+	}{
+		{limit: 0, expected: DEFAULT_GNOMON_PAGE_LIMIT},
+		{limit: 1, expected: 1},
+		{limit: DEFAULT_GNOMON_MAX_PAGE_LIMIT, expected: DEFAULT_GNOMON_MAX_PAGE_LIMIT},
+		{limit: DEFAULT_GNOMON_MAX_PAGE_LIMIT + 1, err: true},
+		{limit: -1, err: true},
+	}
+
+	for _, tt := range tests {
+		limit, err := Page_Params{Limit: tt.limit}.limit()
+		if tt.err {
+			if err == nil {
+				t.Errorf("limit %d: expected error, got %d", tt.limit, limit)
+			}
+			continue
+		}
+
+		if err != nil || limit != tt.expected {
+			t.Errorf("limit %d: expected %d, got %d (%v)", tt.limit, tt.expected, limit, err)
+		}
+	}
+}
+
+func TestPageBounds(t *testing.T) {
+	keys := []string{"b", "d", "f", "h", "j"}
+
+	tests := []struct {
+		name  string
+		keys  []string
+		after string
+		limit int
+		page  []string
+		next  string
+		err   bool
+	}{
+		{name: "empty set", keys: nil, limit: 2},
+		{name: "empty set with cursor", keys: nil, after: "b", limit: 2},
+		{name: "first page", keys: keys, limit: 2, page: []string{"b", "d"}, next: "d"},
+		{name: "cursor in set", keys: keys, after: "d", limit: 2, page: []string{"f", "h"}, next: "h"},
+		{name: "cursor not in set", keys: keys, after: "e", limit: 2, page: []string{"f", "h"}, next: "h"},
+		{name: "cursor before set", keys: keys, after: "a", limit: 2, page: []string{"b", "d"}, next: "d"},
+		{name: "last page exactly", keys: keys, after: "f", limit: 2, page: []string{"h", "j"}},
+		{name: "last page short", keys: keys, after: "h", limit: 2, page: []string{"j"}},
+		{name: "cursor at end", keys: keys, after: "j", limit: 2},
+		{name: "cursor past end", keys: keys, after: "z", limit: 2},
+		{name: "default limit", keys: keys, page: keys},
+		{name: "limit over max", keys: keys, limit: DEFAULT_GNOMON_MAX_PAGE_LIMIT + 1, err: true},
+		{name: "negative limit", keys: keys, limit: -1, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, page, err := Page_Params{After: tt.after, Limit: tt.limit}.bounds(tt.keys)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error, got bounds %d:%d", start, end)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if strings.Join(tt.keys[start:end], ",") != strings.Join(tt.page, ",") {
+				t.Errorf("expected page %v, got %v", tt.page, tt.keys[start:end])
+			}
+
+			if page.Next != tt.next {
+				t.Errorf("expected next %q, got %q", tt.next, page.Next)
+			}
+		})
+	}
+}
+
+// Keys added before the cursor while paging must not shift the following pages
+func TestPageBoundsStable(t *testing.T) {
+	keys := []string{"b", "d", "f", "h"}
+
+	var seen []string
+	after := ""
+	for i := 0; ; i++ {
+		start, end, page, err := Page_Params{After: after, Limit: 2}.bounds(keys)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		seen = append(seen, keys[start:end]...)
+		if page.Next == "" {
+			break
+		}
+
+		if i == 0 {
+			keys = []string{"a", "b", "c", "d", "f", "h"}
+		}
+
+		after = page.Next
+	}
+
+	if strings.Join(seen, ",") != "b,d,f,h" {
+		t.Fatalf("expected b,d,f,h, got %v", seen)
+	}
+}
+
+func TestGetOwnersAndSCIDsAfter(t *testing.T) {
+	bbs, err := storage.NewBBoltDB(t.TempDir(), "gnomon.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bbs.DB.Close()
+
+	grav, err := storage.NewGravDBRAM("25ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var scids []string
+	for i := 0; i < 7; i++ {
+		scid := fmt.Sprintf("%064x", i*3)
+		owner := "owner1"
+		if i%2 == 1 {
+			owner = "owner2"
+		}
+
+		scids = append(scids, scid)
+		if _, err := bbs.StoreOwner(scid, owner); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, _, err := grav.StoreOwner(scid, owner, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, backend := range []string{"boltdb", "gravdb"} {
+		g := &Gnomon{Index: &indexer.Indexer{DBType: backend, BBSBackend: bbs, GravDBBackend: grav}}
+
+		// Walk every page with the cursor of the previous one
+		walk := func(owner string, limit int) (result []string) {
+			after := ""
+			for {
+				owners, more := g.GetOwnersAndSCIDsAfter(after, limit, owner)
+				if len(owners) > limit {
+					t.Fatalf("%s: page of %d exceeds limit %d", backend, len(owners), limit)
+				}
+
+				for _, o := range owners {
+					if owner != "" && o.Owner != owner {
+						t.Fatalf("%s: owner %s does not match filter %s", backend, o.Owner, owner)
+					}
+					result = append(result, o.SCID)
+				}
+
+				if !more {
+					return
+				}
+
+				if len(owners) == 0 {
+					t.Fatalf("%s: empty page with more results", backend)
+				}
+				after = owners[len(owners)-1].SCID
+			}
+		}
+
+		for _, limit := range []int{1, 2, 3, 7, 8} {
+			if got := walk("", limit); strings.Join(got, ",") != strings.Join(scids, ",") {
+				t.Errorf("%s limit %d: expected %v, got %v", backend, limit, scids, got)
+			}
+		}
+
+		expected := []string{scids[1], scids[3], scids[5]}
+		if got := walk("owner2", 2); strings.Join(got, ",") != strings.Join(expected, ",") {
+			t.Errorf("%s owner filter: expected %v, got %v", backend, expected, got)
+		}
+
+		// A cursor that is not a stored SCID starts after its position
+		if owners, _ := g.GetOwnersAndSCIDsAfter(scids[2]+"0", 2, ""); len(owners) != 2 || owners[0].SCID != scids[3] {
+			t.Errorf("%s: cursor not in set returned %v", backend, owners)
+		}
+
+		if owners, more := g.GetOwnersAndSCIDsAfter(scids[len(scids)-1], 2, ""); len(owners) != 0 || more {
+			t.Errorf("%s: cursor at end returned %v, more %t", backend, owners, more)
+		}
+
+		if owners, more := g.GetOwnersAndSCIDsAfter("z", 2, ""); len(owners) != 0 || more {
+			t.Errorf("%s: cursor past end returned %v, more %t", backend, owners, more)
+		}
+	}
+}
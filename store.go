@@ -312,3 +312,38 @@ func DeleteKey(t string, key []byte) (err error) {
 
 	return
 }
+
+// Get all keys in a Graviton tree
+func GetKeys(t string) (keys [][]byte, err error) {
+	if t == "" {
+		err = errors.New("error: missing graviton tree input")
+		return
+	}
+
+	shard, err := GetShard()
+	if err != nil {
+		return
+	}
+
+	store, err := graviton.NewDiskStore(shard)
+	if err != nil {
+		return
+	}
+
+	ss, err := store.LoadSnapshot(0)
+	if err != nil {
+		return
+	}
+
+	tree, err := ss.GetTree(t)
+	if err != nil {
+		return
+	}
+
+	c := tree.Cursor()
+	for k, _, e := c.First(); e == nil; k, _, e = c.Next() {
+		keys = append(keys, k)
+	}
+
+	return
+}